@@ -62,6 +62,9 @@ type Store struct {
 		StakerOldRewards           kvdb.KeyValueStore `table:"7"`
 		StakerDelegatorsOldRewards kvdb.KeyValueStore `table:"8"`
 
+		// state snapshot tables
+		Snapshot kvdb.KeyValueStore `table:"Y"`
+
 		Evm      ethdb.Database
 		EvmState state.Database
 		EvmLogs  *topicsdb.Index
@@ -174,6 +177,11 @@ func (s *Store) StateDB(from common.Hash) *state.StateDB {
 	return db
 }
 
+// OpenStateDB returns state database, or an error if the state isn't found (e.g. it's pruned).
+func (s *Store) OpenStateDB(from common.Hash) (*state.StateDB, error) {
+	return state.New(common.Hash(from), s.table.EvmState)
+}
+
 // StateDB returns state database.
 func (s *Store) IndexLogs(recs ...*types.Log) {
 	err := s.table.EvmLogs.Push(recs...)
@@ -246,6 +254,26 @@ func (s *Store) dropTable(it ethdb.Iterator, t kvdb.KeyValueStore) {
 	}
 }
 
+// getSnapshot returns a consistent read-only view of the table.
+func (s *Store) getSnapshot(t kvdb.KeyValueStore) kvdb.Snapshot {
+	snapshoter, ok := t.(kvdb.Snapshoter)
+	if !ok {
+		s.Log.Crit("Table doesn't support snapshots")
+	}
+	snap, err := snapshoter.GetSnapshot()
+	if err != nil {
+		s.Log.Crit("Failed to get snapshot", "err", err)
+	}
+	return snap
+}
+
+// deleteRange deletes the table's keys in the range [start, limit). A nil limit is the end of the table.
+func (s *Store) deleteRange(t kvdb.KeyValueStore, start, limit []byte) {
+	if err := kvdb.DeleteRange(t, start, limit); err != nil {
+		s.Log.Crit("Failed to erase key range", "err", err)
+	}
+}
+
 func (s *Store) makeCache(size int) *lru.Cache {
 	if size <= 0 {
 		return nil
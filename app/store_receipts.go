@@ -71,3 +71,35 @@ func (s *Store) GetReceipts(n idx.Block) types.Receipts {
 	}
 	return receipts
 }
+
+// PruneReceipts deletes up to limit receipts of the blocks before the specified one.
+// Returns true if there are no such receipts left.
+func (s *Store) PruneReceipts(before idx.Block, limit int) bool {
+	blocks := make([]idx.Block, 0, limit)
+
+	it := s.table.Receipts.NewIterator()
+	for it.Next() && len(blocks) < limit {
+		n := idx.BytesToBlock(it.Key())
+		if n >= before {
+			break
+		}
+		blocks = append(blocks, n)
+	}
+	it.Release()
+
+	if len(blocks) == 0 {
+		return true
+	}
+
+	// the keys are ordered by block, so the receipts are deleted by a range
+	s.deleteRange(s.table.Receipts, nil, (blocks[len(blocks)-1] + 1).Bytes())
+
+	// Remove from LRU cache.
+	if s.cache.Receipts != nil {
+		for _, n := range blocks {
+			s.cache.Receipts.Remove(n)
+		}
+	}
+
+	return len(blocks) < limit
+}
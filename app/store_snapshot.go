@@ -0,0 +1,91 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/kvdb"
+)
+
+type snapshotTable struct {
+	Name  byte
+	Table kvdb.KeyValueStore
+}
+
+// snapshotTables returns the SFC/economy tables, which make up the app state at an epoch boundary.
+// API-only tables aren't a part of the snapshot.
+func (s *Store) snapshotTables() []snapshotTable {
+	return []snapshotTable{
+		{'V', s.table.ActiveValidationScore},
+		{'v', s.table.DirtyValidationScore},
+		{'O', s.table.ActiveOriginationScore},
+		{'o', s.table.DirtyOriginationScore},
+		{'m', s.table.BlockDowntime},
+		{'s', s.table.StakerPOIScore},
+		{'a', s.table.AddressPOIScore},
+		{'g', s.table.AddressFee},
+		{'d', s.table.StakerDelegatorsFee},
+		{'X', s.table.AddressLastTxTime},
+		{'U', s.table.TotalPoiFee},
+		{'R', s.table.GasPowerRefund},
+		{'1', s.table.Validators},
+		{'2', s.table.Stakers},
+		{'3', s.table.Delegators},
+		{'4', s.table.SfcConstants},
+		{'5', s.table.TotalSupply},
+	}
+}
+
+// CaptureSnapshot copies the SFC/economy tables as they are at the beginning of the epoch.
+// Only the last captured epoch is kept.
+func (s *Store) CaptureSnapshot(epoch idx.Epoch) {
+	s.deleteRange(s.table.Snapshot, nil, nil)
+
+	for _, t := range s.snapshotTables() {
+		prefix := append(epoch.Bytes(), t.Name)
+		it := t.Table.NewIterator()
+		for it.Next() {
+			key := append(append([]byte{}, prefix...), it.Key()...)
+			if err := s.table.Snapshot.Put(key, it.Value()); err != nil {
+				s.Log.Crit("Failed to put key-value", "err", err)
+			}
+		}
+		it.Release()
+	}
+}
+
+// ForEachSnapshotRecord iterates over the SFC/economy records captured at the beginning of the epoch.
+// The records are read from a DB snapshot, so a capture of the next epoch doesn't interleave with them.
+// Returns false if the epoch isn't captured.
+func (s *Store) ForEachSnapshotRecord(epoch idx.Epoch, onRecord func(key, value []byte) bool) bool {
+	snap := s.getSnapshot(s.table.Snapshot)
+	defer snap.Release()
+
+	it := snap.NewIteratorWithPrefix(epoch.Bytes())
+	defer it.Release()
+
+	captured := false
+	for it.Next() {
+		captured = true
+		if !onRecord(it.Key()[4:], it.Value()) {
+			break
+		}
+	}
+	return captured
+}
+
+// ApplySnapshotRecord writes the SFC/economy record, returned by ForEachSnapshotRecord.
+// Caches aren't updated, so it's intended only for a store which isn't used yet.
+func (s *Store) ApplySnapshotRecord(key, value []byte) error {
+	if len(key) < 1 {
+		return errors.New("empty snapshot record key")
+	}
+	for _, t := range s.snapshotTables() {
+		if t.Name != key[0] {
+			continue
+		}
+		return t.Table.Put(key[1:], value)
+	}
+	return fmt.Errorf("unknown snapshot table %q", key[0])
+}
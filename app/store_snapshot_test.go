@@ -0,0 +1,39 @@
+package app
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/logger"
+)
+
+func TestStoreSnapshot(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	src := NewMemStore()
+	src.SetTotalSupply(big.NewInt(100))
+	src.CaptureSnapshot(2)
+
+	// changes after the capture aren't a part of the snapshot
+	src.SetTotalSupply(big.NewInt(200))
+
+	dst := NewMemStore()
+	captured := src.ForEachSnapshotRecord(2, func(key, value []byte) bool {
+		assertar.NoError(dst.ApplySnapshotRecord(key, value))
+		return true
+	})
+	assertar.True(captured)
+	assertar.Equal(big.NewInt(100), dst.GetTotalSupply())
+
+	// only the last epoch is kept
+	src.CaptureSnapshot(3)
+	assertar.False(src.ForEachSnapshotRecord(2, func(key, value []byte) bool {
+		return true
+	}))
+
+	assertar.Error(dst.ApplySnapshotRecord([]byte{'r', 0x01}, []byte{0x01}))
+	assertar.Error(dst.ApplySnapshotRecord(nil, nil))
+}
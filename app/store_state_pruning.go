@@ -0,0 +1,98 @@
+package app
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// StateMarker collects the EVM trie nodes and contract codes, which are reachable from the kept state roots.
+// Memory usage is proportional to the number of state nodes.
+type StateMarker struct {
+	db     state.Database
+	marked map[common.Hash]struct{}
+}
+
+// NewStateMarker makes an empty marker of EVM states.
+func (s *Store) NewStateMarker() *StateMarker {
+	return &StateMarker{
+		db:     s.table.EvmState,
+		marked: make(map[common.Hash]struct{}),
+	}
+}
+
+// Mark marks all the trie nodes and contract codes of the state.
+// Subtries which are already marked aren't traversed again.
+func (m *StateMarker) Mark(root common.Hash) error {
+	tr, err := m.db.OpenTrie(root)
+	if err != nil {
+		return err
+	}
+	return m.markTrie(tr.NodeIterator(nil), func(leaf []byte) error {
+		var acc state.Account
+		if err := rlp.DecodeBytes(leaf, &acc); err != nil {
+			return err
+		}
+		m.marked[common.BytesToHash(acc.CodeHash)] = struct{}{}
+
+		storage, err := m.db.OpenStorageTrie(common.Hash{}, acc.Root)
+		if err != nil {
+			return err
+		}
+		return m.markTrie(storage.NodeIterator(nil), nil)
+	})
+}
+
+func (m *StateMarker) markTrie(it trie.NodeIterator, onLeaf func(leaf []byte) error) error {
+	descend := true
+	for it.Next(descend) {
+		descend = true
+		if h := it.Hash(); h != (common.Hash{}) {
+			if _, ok := m.marked[h]; ok {
+				// the subtrie is already marked
+				descend = false
+				continue
+			}
+			m.marked[h] = struct{}{}
+		}
+		if it.Leaf() && onLeaf != nil {
+			if err := onLeaf(it.LeafBlob()); err != nil {
+				return err
+			}
+		}
+	}
+	return it.Error()
+}
+
+// SweepStates checks up to limit EVM records, starting from the key,
+// and deletes the trie nodes and contract codes which aren't marked.
+// Returns the key to continue from, or nil if the sweeping is done.
+func (s *Store) SweepStates(m *StateMarker, from []byte, limit int) (next []byte, deleted int) {
+	keys := make([][]byte, 0, limit)
+
+	it := s.table.Evm.NewIteratorWithStart(from)
+	for checked := 0; it.Next(); checked++ {
+		if checked >= limit {
+			next = common.CopyBytes(it.Key())
+			break
+		}
+		// trie nodes and codes are stored by hash, other records have longer keys
+		if len(it.Key()) != common.HashLength {
+			continue
+		}
+		if _, ok := m.marked[common.BytesToHash(it.Key())]; ok {
+			continue
+		}
+		keys = append(keys, common.CopyBytes(it.Key()))
+	}
+	it.Release()
+
+	for _, key := range keys {
+		if err := s.table.Evm.Delete(key); err != nil {
+			s.Log.Crit("Failed to erase key-value", "err", err)
+		}
+	}
+
+	return next, len(keys)
+}
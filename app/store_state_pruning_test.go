@@ -0,0 +1,63 @@
+package app
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/logger"
+)
+
+func TestStoreStatePruning(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	store := NewMemStore()
+	commit := func(statedb *state.StateDB) common.Hash {
+		root, err := statedb.Commit(true)
+		assertar.NoError(err)
+		assertar.NoError(store.Commit(nil, true))
+		return root
+	}
+
+	statedb := store.StateDB(common.Hash{})
+	for i := int64(0); i < 100; i++ {
+		addr := common.BigToAddress(big.NewInt(i))
+		statedb.SetBalance(addr, big.NewInt(i))
+		statedb.SetState(addr, common.Hash{1}, common.BigToHash(big.NewInt(i)))
+	}
+	statedb.SetCode(common.Address{1}, []byte{0x01, 0x02})
+	oldRoot := commit(statedb)
+
+	statedb = store.StateDB(oldRoot)
+	statedb.SetBalance(common.Address{1}, big.NewInt(1000))
+	statedb.SetState(common.Address{2}, common.Hash{1}, common.Hash{2})
+	newRoot := commit(statedb)
+
+	marker := store.NewStateMarker()
+	assertar.NoError(marker.Mark(newRoot))
+
+	deleted := 0
+	for from := []byte{}; from != nil; {
+		var n int
+		from, n = store.SweepStates(marker, from, 10)
+		deleted += n
+	}
+	assertar.NotZero(deleted)
+
+	// the kept state is complete
+	statedb = store.StateDB(newRoot)
+	assertar.Equal(big.NewInt(1000), statedb.GetBalance(common.Address{1}))
+	assertar.Equal([]byte{0x01, 0x02}, statedb.GetCode(common.Address{1}))
+	it := state.NewNodeIterator(statedb)
+	for it.Next() {
+	}
+	assertar.NoError(it.Error)
+
+	// the old state root is deleted
+	_, err := state.New(oldRoot, state.NewDatabase(store.EvmTable()))
+	assertar.Error(err)
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/Fantom-foundation/go-lachesis/integration"
+)
+
+var DataDirFlag = cli.StringFlag{
+	Name:  "datadir",
+	Usage: "Data directory of the node",
+	Value: defaultDataDir(),
+}
+
+var DbEngineFlag = cli.StringFlag{
+	Name:  "db.engine",
+	Usage: "Engine of the databases (" + strings.Join(integration.DbEngines, ", ") + ")",
+	Value: integration.LevelDbEngine,
+}
+
+var RepairFlag = cli.BoolFlag{
+	Name:  "repair",
+	Usage: "Rebuild the broken indexes and mark the databases as synced",
+}
+
+func defaultDataDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".lachesis")
+}
+
+func getDataDir(ctx *cli.Context) string {
+	// datadir may be passed as the argument for back compatibility
+	if ctx.NArg() > 0 {
+		return ctx.Args().First()
+	}
+	return ctx.GlobalString(DataDirFlag.Name)
+}
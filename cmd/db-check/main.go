@@ -1,27 +1,143 @@
 package main
 
 import (
+	"fmt"
 	"os"
-	"path/filepath"
+	"sort"
 
-	"github.com/Fantom-foundation/go-lachesis/kvdb/leveldb"
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"gopkg.in/urfave/cli.v1"
+
+	lachesisapp "github.com/Fantom-foundation/go-lachesis/app"
+	"github.com/Fantom-foundation/go-lachesis/gossip"
+	"github.com/Fantom-foundation/go-lachesis/integration"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/flushable"
+	"github.com/Fantom-foundation/go-lachesis/poset"
+	_ "github.com/Fantom-foundation/go-lachesis/version"
+)
+
+var (
+	// Git SHA1 commit hash of the release (set via linker flags).
+	gitCommit = ""
+	gitDate   = ""
+	// The app that holds all commands and flags.
+	app = utils.NewApp(gitCommit, gitDate, "the offline database integrity checker")
+
+	flags = []cli.Flag{
+		DataDirFlag,
+		DbEngineFlag,
+		RepairFlag,
+	}
 )
 
+// init the CLI app.
+func init() {
+	app.Action = checkMain
+	app.ArgsUsage = "[<datadir>]"
+	app.Version = params.VersionWithCommit(gitCommit, gitDate)
+
+	app.Commands = []cli.Command{}
+	sort.Sort(cli.CommandsByName(app.Commands))
+
+	app.Flags = append(app.Flags, flags...)
+}
+
 func main() {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		panic(err)
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
 	}
-	dir := filepath.Join(home, ".lachesis")
+}
+
+// checkMain is the main entry point.
+// Repairs are written only if all the found issues are repaired,
+// otherwise the databases are left untouched.
+func checkMain(ctx *cli.Context) error {
+	log.Root().SetHandler(log.LvlFilterHandler(log.LvlWarn, log.StreamHandler(os.Stderr, log.TerminalFormat(false))))
+
+	dir := getDataDir(ctx)
+	repair := ctx.GlobalBool(RepairFlag.Name)
 
-	if len(os.Args) >= 2 {
-		dir = os.Args[1]
+	if _, err := os.Stat(dir); err != nil {
+		return err
 	}
+	producer := integration.DbProducer(dir, ctx.GlobalString(DbEngineFlag.Name))
+	dbs := flushable.NewUncheckedSyncedPool(producer)
 
-	p := leveldb.NewProducer(dir)
-	db := p.OpenDb("gossip-main")
-	defer db.Close()
+	var found, unrepairable int
+	onIssue := func(issue gossip.IntegrityIssue) {
+		found++
+		switch {
+		case !issue.Repairable:
+			unrepairable++
+			fmt.Println(issue.String(), "(not repairable)")
+		case repair:
+			fmt.Println(issue.String(), "(repaired)")
+		default:
+			fmt.Println(issue.String())
+		}
+	}
+
+	if err := dbs.CheckDbsSynced(); err != nil {
+		// flush markers are rewritten on the repair,
+		// unless the state of the last block isn't stored, which is checked by checkCheckpoint
+		onIssue(gossip.IntegrityIssue{Table: "DBs", Descr: err.Error(), Repairable: true})
+	}
 
-	//checkPacks(db)
-	checkEvents(db)
+	gdb := gossip.NewStore(dbs, gossip.LiteStoreConfig())
+	defer gdb.Close()
+	adb := lachesisapp.NewStore(dbs, lachesisapp.LiteStoreConfig())
+	defer adb.Close()
+	cdb := poset.NewStore(dbs, poset.DefaultStoreConfig())
+	defer cdb.Close()
+
+	epoch := cdb.GetEpoch()
+	if epoch == nil {
+		return fmt.Errorf("no poset state in %s", dir)
+	}
+
+	gdb.CheckDag(epoch.EpochN, repair, onIssue)
+	gdb.CheckBlockIndexes(repair, onIssue)
+	checkCheckpoint(cdb, gdb, adb, onIssue)
+
+	switch {
+	case found == 0:
+		fmt.Println("No issues found")
+		return nil
+	case unrepairable != 0:
+		return fmt.Errorf("%d issues found, %d of them aren't repairable, nothing is written. Consider to restore the node from a snapshot", found, unrepairable)
+	case !repair:
+		return fmt.Errorf("%d issues found, run with --%s to repair", found, RepairFlag.Name)
+	}
+
+	if err := gdb.Commit(nil, true); err != nil {
+		return err
+	}
+	fmt.Printf("%d issues repaired\n", found)
+	return nil
+}
+
+// checkCheckpoint checks that the poset checkpoint agrees with the last block,
+// and that the node may continue from the last block.
+func checkCheckpoint(cdb *poset.Store, gdb *gossip.Store, adb *lachesisapp.Store, onIssue func(gossip.IntegrityIssue)) {
+	cp := cdb.GetCheckpoint()
+	if cp == nil {
+		onIssue(gossip.IntegrityIssue{Table: "Checkpoint", Descr: "checkpoint is missing"})
+		return
+	}
+
+	block := gdb.GetBlock(cp.LastBlockN)
+	if block == nil {
+		onIssue(gossip.IntegrityIssue{Table: "Checkpoint", Descr: fmt.Sprintf("last block %d is missing", cp.LastBlockN)})
+	} else if block.Atropos != cp.LastAtropos {
+		onIssue(gossip.IntegrityIssue{Table: "Checkpoint", Descr: fmt.Sprintf("last block %d has atropos %s, but checkpoint has %s", cp.LastBlockN, block.Atropos, cp.LastAtropos)})
+	} else {
+		gdb.CheckBlockState(adb, cp.LastBlockN, onIssue)
+	}
+
+	if gdb.GetBlock(cp.LastBlockN+1) != nil {
+		onIssue(gossip.IntegrityIssue{Table: "Checkpoint", Descr: fmt.Sprintf("block %d is stored after the last block %d", cp.LastBlockN+1, cp.LastBlockN)})
+	}
 }
@@ -0,0 +1,737 @@
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/rlp"
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/Fantom-foundation/go-lachesis/eventcheck"
+	"github.com/Fantom-foundation/go-lachesis/eventcheck/epochcheck"
+	"github.com/Fantom-foundation/go-lachesis/gossip"
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/integration"
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/utils/errlock"
+)
+
+const (
+	// eventsFileVersion is the current version of the events file format.
+	eventsFileVersion = 1
+	// statsReportLimit is the time limit during import and export after which we always print out progress.
+	statsReportLimit = 8 * time.Second
+)
+
+var (
+	// eventsFileMagic is an eyecatcher at the start of the events file.
+	eventsFileMagic = [4]byte{'L', 'E', 'V', 'S'}
+
+	errNotEventsFile       = errors.New("not an events file")
+	errEventsFileVersion   = errors.New("unsupported events file version")
+	errEventsFileGenesis   = errors.New("events file is for another genesis")
+	errEventsFileNoGenesis = errors.New("events file has no genesis hash")
+)
+
+var (
+	importCheckFlag = cli.BoolTFlag{
+		Name:  "check",
+		Usage: "Check the events before connecting them (default = true)",
+	}
+
+	exportEventsCommand = cli.Command{
+		Action:    utils.MigrateFlags(exportEvents),
+		Name:      "export-events",
+		Usage:     "Export the DAG events into file",
+		ArgsUsage: "<filename> [<epochFrom> <epochTo>]",
+		Flags: []cli.Flag{
+			DataDirFlag,
+			DbEngineFlag,
+			FakeNetFlag,
+			utils.TestnetFlag,
+			GenesisFlag,
+			configFileFlag,
+		},
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+    lachesis export-events <filename> [<epochFrom> <epochTo>]
+
+Requires a first argument of the file to write to.
+Optional second and third arguments control the first and
+last epoch to write. The events are written epoch by epoch in
+topological order, after a versioned header with the genesis hash.
+If the file ends with .gz, the output will be gzipped.
+`,
+	}
+
+	dagFormatFlag = cli.StringFlag{
+		Name:  "format",
+		Usage: "Output format of the DAG: dot or json",
+		Value: "dot",
+	}
+	dagArrivalTimeFlag = cli.BoolFlag{
+		Name:  "arrival-time",
+		Usage: "Include local arrival time of the events, if EventLocalTimeIndex was enabled",
+	}
+
+	exportDagCommand = cli.Command{
+		Action:    utils.MigrateFlags(exportDag),
+		Name:      "export-dag",
+		Usage:     "Export the DAG of epochs as Graphviz DOT or JSON",
+		ArgsUsage: "<filename> [<epochFrom> <epochTo>]",
+		Flags: []cli.Flag{
+			DataDirFlag,
+			DbEngineFlag,
+			FakeNetFlag,
+			utils.TestnetFlag,
+			GenesisFlag,
+			configFileFlag,
+			dagFormatFlag,
+			dagArrivalTimeFlag,
+		},
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+    lachesis export-dag [--format dot|json] <filename> [<epochFrom> <epochTo>]
+
+Requires a first argument of the file to write to.
+Optional second and third arguments control the first and
+last epoch to write, the current epoch is the last by default.
+Each event is written with its creator, seq, frame, root flag,
+Atropos mark, confirming block and fork branch of a cheater.
+The DOT output draws each epoch as a cluster, render it with
+e.g. "dot -Tsvg <filename> -o dag.svg". The JSON output is an
+array of epochs, the same as dag_exportEpoch RPC returns.
+`,
+	}
+
+	importEventsCommand = cli.Command{
+		Action:    utils.MigrateFlags(importEvents),
+		Name:      "import-events",
+		Usage:     "Import the DAG events from file(s)",
+		ArgsUsage: "<filename> (<filename 2> ... <filename N>)",
+		Flags: []cli.Flag{
+			DataDirFlag,
+			DbEngineFlag,
+			FakeNetFlag,
+			utils.TestnetFlag,
+			GenesisFlag,
+			configFileFlag,
+			importCheckFlag,
+		},
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+    lachesis import-events <filename> (<filename 2> ... <filename N>)
+
+The import command connects the events from the files, made by
+the export-events command, as if they were received from peers.
+The genesis hash of each file must match the node's genesis.
+Already connected events are skipped, so an interrupted import may
+be restarted. If the file ends with .gz, it's treated as gzipped.
+`,
+	}
+
+	reindexCommand = cli.Command{
+		Action: utils.MigrateFlags(reindex),
+		Name:   "reindex",
+		Usage:  "Fill the transaction positions, receipts and decisive events of the applied blocks",
+		Flags: []cli.Flag{
+			DataDirFlag,
+			DbEngineFlag,
+			FakeNetFlag,
+			utils.TestnetFlag,
+			GenesisFlag,
+			configFileFlag,
+		},
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+    lachesis reindex
+
+The reindex command builds the transactions index of the blocks,
+which were applied while the TxIndex option was disabled. The
+transactions are replayed on the stored EVM states to restore
+their receipts. The progress is saved, so an interrupted reindex
+is resumed by the next run. The blocks with pruned events or EVM
+states are skipped, and the reindex is aborted if the replayed
+transactions don't match a block.
+Then the DecisiveEvents index is filled by replaying all the events
+on a fresh in-memory node, it requires the events to be not pruned.
+The EventLocalTimes index can't be rebuilt, because it's a record of
+when the node received the events.
+Enable the TxIndex and DecisiveEventsIndex options before starting
+the node, to keep indexing the new blocks.
+`,
+	}
+
+	replayCommand = cli.Command{
+		Action:    utils.MigrateFlags(replay),
+		Name:      "replay",
+		Usage:     "Replay the stored events on a fresh node and find where the consensus diverges",
+		ArgsUsage: "[<epochTo>]",
+		Flags: []cli.Flag{
+			DataDirFlag,
+			DbEngineFlag,
+			FakeNetFlag,
+			utils.TestnetFlag,
+			GenesisFlag,
+			configFileFlag,
+		},
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+    lachesis replay [<epochTo>]
+
+The replay command connects the stored events epoch by epoch to
+a fresh in-memory node with the same genesis. Each produced block
+is compared with the stored one: Atropos, events, skipped txs and
+state root. The consensus AppHash is compared at the beginning of
+each epoch sealed by the node, and at the end. The first block
+where they differ is reported, and the command fails.
+Optional argument is the last epoch to replay. The datadir isn't
+modified, but the replayed state is kept in memory.
+The events must be not pruned.
+`,
+	}
+
+	exportSnapshotCommand = cli.Command{
+		Action:    utils.MigrateFlags(exportSnapshot),
+		Name:      "export-snapshot",
+		Usage:     "Export the state at the beginning of the last captured epoch into file",
+		ArgsUsage: "<filename>",
+		Flags: []cli.Flag{
+			DataDirFlag,
+			DbEngineFlag,
+			FakeNetFlag,
+			utils.TestnetFlag,
+			GenesisFlag,
+			configFileFlag,
+		},
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+    lachesis export-snapshot <filename>
+
+Requires a first argument of the file to write to.
+The node captures the state at the beginning of each epoch if EpochSnapshot
+option is enabled, otherwise only of the next epoch after admin.requestSnapshot().
+The snapshot contains the consensus checkpoint, the last block, the epoch stats
+and headers, the SFC/economy tables and the EVM state trie.
+If the file ends with .gz, the output will be gzipped.
+`,
+	}
+
+	importSnapshotCommand = cli.Command{
+		Action:    utils.MigrateFlags(importSnapshot),
+		Name:      "import-snapshot",
+		Usage:     "Import the state from snapshot file into a new datadir",
+		ArgsUsage: "<filename>",
+		Flags: []cli.Flag{
+			DataDirFlag,
+			DbEngineFlag,
+			FakeNetFlag,
+			utils.TestnetFlag,
+			GenesisFlag,
+			configFileFlag,
+		},
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+    lachesis import-snapshot <filename>
+
+The import command writes the state, made by the export-snapshot
+command, into a datadir which has only the genesis state.
+The genesis hash of the file must match the node's genesis.
+After the import, the node starts from the snapshot epoch and
+syncs only the events of this epoch and later ones.
+If the file ends with .gz, it's treated as gzipped.
+`,
+	}
+)
+
+// eventsFileHeader is written at the start of the events file.
+type eventsFileHeader struct {
+	Magic   [4]byte
+	Version uint32
+	Genesis common.Hash
+}
+
+func writeEventsFileHeader(w io.Writer, genesis common.Hash) error {
+	return rlp.Encode(w, &eventsFileHeader{
+		Magic:   eventsFileMagic,
+		Version: eventsFileVersion,
+		Genesis: genesis,
+	})
+}
+
+func readEventsFileHeader(stream *rlp.Stream, genesis common.Hash) error {
+	var header eventsFileHeader
+	if err := stream.Decode(&header); err != nil {
+		return errNotEventsFile
+	}
+	if header.Magic != eventsFileMagic {
+		return errNotEventsFile
+	}
+	if header.Version != eventsFileVersion {
+		return errEventsFileVersion
+	}
+	if header.Genesis == (common.Hash{}) {
+		return errEventsFileNoGenesis
+	}
+	if header.Genesis != genesis {
+		return errEventsFileGenesis
+	}
+	return nil
+}
+
+func exportEvents(ctx *cli.Context) error {
+	if len(ctx.Args()) < 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+
+	cfg := makeAllConfigs(ctx)
+	engine, _, gdb := makeEngine(ctx, &cfg)
+	defer gdb.Close()
+
+	fn := ctx.Args().First()
+
+	// Open the file handle and potentially wrap with a gzip stream
+	fh, err := os.OpenFile(fn, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	var writer io.Writer = fh
+	if strings.HasSuffix(fn, ".gz") {
+		gz := gzip.NewWriter(writer)
+		defer gz.Close()
+		writer = gz
+	}
+	buffered := bufio.NewWriter(writer)
+	defer buffered.Flush()
+
+	from := idx.Epoch(1)
+	to := idx.Epoch(0)
+	if len(ctx.Args()) > 1 {
+		n, err := strconv.ParseUint(ctx.Args().Get(1), 10, 32)
+		if err != nil {
+			return err
+		}
+		from = idx.Epoch(n)
+	}
+	if len(ctx.Args()) > 2 {
+		n, err := strconv.ParseUint(ctx.Args().Get(2), 10, 32)
+		if err != nil {
+			return err
+		}
+		to = idx.Epoch(n)
+	}
+
+	log.Info("Exporting events to file", "file", fn)
+	err = writeEventsFileHeader(buffered, engine.GetGenesisHash())
+	if err != nil {
+		return err
+	}
+
+	start, reported := time.Now(), time.Now()
+	var (
+		counter int
+		last    hash.Event
+	)
+	gdb.ForEachEventRLP(from, func(id hash.Event, event rlp.RawValue) bool {
+		if to >= from && id.Epoch() > to {
+			return false
+		}
+		_, err = buffered.Write(event)
+		if err != nil {
+			return false
+		}
+		counter++
+		last = id
+		if time.Since(reported) >= statsReportLimit {
+			log.Info("Exporting events", "last", last.String(), "exported", counter, "elapsed", common.PrettyDuration(time.Since(start)))
+			reported = time.Now()
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	log.Info("Exported events to file", "file", fn, "last", last.String(), "exported", counter, "elapsed", common.PrettyDuration(time.Since(start)))
+
+	return nil
+}
+
+func exportDag(ctx *cli.Context) error {
+	if len(ctx.Args()) < 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+	format := ctx.String(dagFormatFlag.Name)
+	if format != "dot" && format != "json" {
+		utils.Fatalf("Unknown DAG format %s", format)
+	}
+
+	cfg := makeAllConfigs(ctx)
+	engine, _, gdb := makeEngine(ctx, &cfg)
+	defer gdb.Close()
+
+	fn := ctx.Args().First()
+
+	from, to, err := parseEpochRange(ctx.Args().Tail(), engine.GetLastState().Epoch.EpochN)
+	if err != nil {
+		return err
+	}
+
+	dags := make([]*inter.EpochDag, 0, to+1-from)
+	events := 0
+	for epoch := from; epoch <= to; epoch++ {
+		dag := gdb.GetEpochDag(epoch, engine.GetEventConfirmedOn, ctx.Bool(dagArrivalTimeFlag.Name))
+		if len(dag.Events) == 0 {
+			log.Warn("No events of epoch, probably they are pruned", "epoch", epoch)
+			continue
+		}
+		dags = append(dags, dag)
+		events += len(dag.Events)
+	}
+
+	fh, err := os.OpenFile(fn, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+	buffered := bufio.NewWriter(fh)
+	defer buffered.Flush()
+
+	if format == "dot" {
+		err = inter.WriteDagDOT(buffered, dags...)
+	} else {
+		encoder := json.NewEncoder(buffered)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(dags)
+	}
+	if err != nil {
+		return err
+	}
+	log.Info("Exported DAG to file", "file", fn, "epochs", len(dags), "events", events)
+
+	return nil
+}
+
+// parseEpochRange parses the optional <epochFrom> <epochTo> arguments,
+// the range must be within the epochs from 1 to the current one.
+func parseEpochRange(args []string, current idx.Epoch) (from, to idx.Epoch, err error) {
+	from, to = 1, current
+	if len(args) > 0 {
+		n, err := strconv.ParseUint(args[0], 10, 32)
+		if err != nil {
+			return 0, 0, err
+		}
+		from = idx.Epoch(n)
+	}
+	if len(args) > 1 {
+		n, err := strconv.ParseUint(args[1], 10, 32)
+		if err != nil {
+			return 0, 0, err
+		}
+		to = idx.Epoch(n)
+	}
+	if from < 1 || from > to || to > current {
+		return 0, 0, fmt.Errorf("invalid epochs range %d-%d, expected 1 <= <epochFrom> <= <epochTo> <= %d (the current epoch)", from, to, current)
+	}
+	return from, to, nil
+}
+
+func importEvents(ctx *cli.Context) error {
+	if len(ctx.Args()) < 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+
+	cfg := makeAllConfigs(ctx)
+
+	// check errlock file
+	errlock.SetDefaultDatadir(cfg.Node.DataDir)
+	errlock.Check()
+
+	engine, adb, gdb := makeEngine(ctx, &cfg)
+	defer gdb.Close()
+
+	// the service isn't started, so it neither emits events nor talks to peers
+	cfg.Lachesis.TxPool.Journal = ""
+	srv, err := gossip.NewService(&node.ServiceContext{}, &cfg.Lachesis, gdb, engine, adb)
+	if err != nil {
+		return err
+	}
+
+	check := ctx.BoolT(importCheckFlag.Name)
+	genesis := engine.GetGenesisHash()
+	for _, fn := range ctx.Args() {
+		if err := importEventsFile(srv, genesis, check, fn); err != nil {
+			return err
+		}
+	}
+
+	// flush the state, the events are already written
+	err = adb.Commit(nil, true)
+	if err != nil {
+		return err
+	}
+	return gdb.Commit(nil, true)
+}
+
+func reindex(ctx *cli.Context) error {
+	cfg := makeAllConfigs(ctx)
+
+	// check errlock file
+	errlock.SetDefaultDatadir(cfg.Node.DataDir)
+	errlock.Check()
+
+	engine, adb, gdb := makeEngine(ctx, &cfg)
+	defer gdb.Close()
+
+	// the service isn't started, so it neither emits events nor talks to peers
+	cfg.Lachesis.TxPool.Journal = ""
+	srv, err := gossip.NewService(&node.ServiceContext{}, &cfg.Lachesis, gdb, engine, adb)
+	if err != nil {
+		return err
+	}
+
+	// Watch for Ctrl-C while the reindex is running.
+	// If a signal is received, the reindex will stop.
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(interrupt)
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-interrupt:
+			close(stop)
+		case <-done:
+		}
+	}()
+
+	if err := srv.ReindexTxs(stop); err != nil {
+		return err
+	}
+	_, err = integration.ReindexDecisiveEvents(&cfg.Lachesis, engine, gdb, stop)
+	return err
+}
+
+func replay(ctx *cli.Context) error {
+	cfg := makeAllConfigs(ctx)
+
+	to := idx.Epoch(0)
+	if len(ctx.Args()) > 0 {
+		n, err := strconv.ParseUint(ctx.Args().First(), 10, 32)
+		if err != nil {
+			return err
+		}
+		to = idx.Epoch(n)
+	}
+
+	engine, _, gdb := makeEngine(ctx, &cfg)
+	defer gdb.Close()
+
+	// Watch for Ctrl-C while the replay is running.
+	// If a signal is received, the replay will stop.
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(interrupt)
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-interrupt:
+			close(stop)
+		case <-done:
+		}
+	}()
+
+	divergence, err := integration.Replay(&cfg.Lachesis, engine, gdb, to, stop)
+	if err != nil {
+		return err
+	}
+	if divergence != nil {
+		return fmt.Errorf("consensus diverged at %s", divergence.String())
+	}
+	log.Info("Replayed consensus matches the stored one")
+	return nil
+}
+
+func importEventsFile(srv *gossip.Service, genesis common.Hash, check bool, fn string) error {
+	// Watch for Ctrl-C while the import is running.
+	// If a signal is received, the import will stop.
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(interrupt)
+
+	log.Info("Importing events from file", "file", fn)
+
+	// Open the file handle and potentially unwrap the gzip stream
+	fh, err := os.Open(fn)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	var reader io.Reader = fh
+	if strings.HasSuffix(fn, ".gz") {
+		if reader, err = gzip.NewReader(reader); err != nil {
+			return err
+		}
+	}
+
+	stream := rlp.NewStream(bufio.NewReader(reader), 0)
+	if err := readEventsFileHeader(stream, genesis); err != nil {
+		return fmt.Errorf("%s: %v", fn, err)
+	}
+
+	start, reported := time.Now(), time.Now()
+	var (
+		counter int
+		skipped int
+		last    hash.Event
+	)
+	for {
+		select {
+		case <-interrupt:
+			return fmt.Errorf("interrupted")
+		default:
+		}
+
+		e := new(inter.Event)
+		err = stream.Decode(e)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if check {
+			err = srv.ImportEvent(e)
+		} else {
+			err = srv.ProcessEvent(e)
+		}
+		if err == eventcheck.ErrAlreadyConnectedEvent || err == epochcheck.ErrNotRelevant {
+			skipped++
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("event %s isn't imported: %v", e.Hash().String(), err)
+		}
+
+		counter++
+		last = e.Hash()
+		if time.Since(reported) >= statsReportLimit {
+			log.Info("Importing events", "last", last.String(), "imported", counter, "skipped", skipped, "elapsed", common.PrettyDuration(time.Since(start)))
+			reported = time.Now()
+		}
+	}
+	log.Info("Imported events from file", "file", fn, "last", last.String(), "imported", counter, "skipped", skipped, "elapsed", common.PrettyDuration(time.Since(start)))
+
+	return nil
+}
+
+func exportSnapshot(ctx *cli.Context) error {
+	if len(ctx.Args()) < 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+
+	cfg := makeAllConfigs(ctx)
+	engine, adb, gdb := makeEngine(ctx, &cfg)
+	defer gdb.Close()
+
+	fn := ctx.Args().First()
+
+	// Open the file handle and potentially wrap with a gzip stream
+	fh, err := os.OpenFile(fn, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	var writer io.Writer = fh
+	if strings.HasSuffix(fn, ".gz") {
+		gz := gzip.NewWriter(writer)
+		defer gz.Close()
+		writer = gz
+	}
+	buffered := bufio.NewWriter(writer)
+	defer buffered.Flush()
+
+	log.Info("Exporting snapshot to file", "file", fn)
+	start := time.Now()
+	epoch, err := integration.WriteSnapshot(buffered, engine, adb, gdb)
+	if err != nil {
+		return err
+	}
+	log.Info("Exported snapshot to file", "file", fn, "epoch", epoch, "elapsed", common.PrettyDuration(time.Since(start)))
+
+	return nil
+}
+
+func importSnapshot(ctx *cli.Context) error {
+	if len(ctx.Args()) < 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+
+	cfg := makeAllConfigs(ctx)
+
+	// check errlock file
+	errlock.SetDefaultDatadir(cfg.Node.DataDir)
+	errlock.Check()
+
+	engine, adb, gdb := makeEngine(ctx, &cfg)
+	defer gdb.Close()
+
+	fn := ctx.Args().First()
+
+	// Open the file handle and potentially unwrap the gzip stream
+	fh, err := os.Open(fn)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	var reader io.Reader = fh
+	if strings.HasSuffix(fn, ".gz") {
+		if reader, err = gzip.NewReader(reader); err != nil {
+			return err
+		}
+	}
+
+	log.Info("Importing snapshot from file", "file", fn)
+	start := time.Now()
+	epoch, err := integration.ApplySnapshot(bufio.NewReader(reader), engine, adb, gdb)
+	if err != nil {
+		return fmt.Errorf("%s: %v", fn, err)
+	}
+
+	err = adb.Commit(nil, true)
+	if err != nil {
+		return err
+	}
+	err = gdb.Commit(nil, true)
+	if err != nil {
+		return err
+	}
+	log.Info("Imported snapshot from file", "file", fn, "epoch", epoch, "elapsed", common.PrettyDuration(time.Since(start)))
+
+	return nil
+}
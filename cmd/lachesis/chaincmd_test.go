@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+)
+
+func TestEventsFileHeader(t *testing.T) {
+	assertar := assert.New(t)
+
+	genesis := common.HexToHash("0x1234")
+	other := common.HexToHash("0x5678")
+
+	buf := bytes.NewBuffer(nil)
+	assertar.NoError(writeEventsFileHeader(buf, genesis))
+	data := buf.Bytes()
+
+	assertar.NoError(readEventsFileHeader(rlp.NewStream(bytes.NewReader(data), 0), genesis))
+	assertar.Equal(errEventsFileGenesis, readEventsFileHeader(rlp.NewStream(bytes.NewReader(data), 0), other))
+	assertar.Equal(errNotEventsFile, readEventsFileHeader(rlp.NewStream(bytes.NewReader([]byte{0x01, 0x02}), 0), genesis))
+
+	buf.Reset()
+	assertar.NoError(rlp.Encode(buf, &eventsFileHeader{
+		Magic:   eventsFileMagic,
+		Version: eventsFileVersion + 1,
+		Genesis: genesis,
+	}))
+	assertar.Equal(errEventsFileVersion, readEventsFileHeader(rlp.NewStream(buf, 0), genesis))
+}
+
+func TestParseEpochRange(t *testing.T) {
+	assertar := assert.New(t)
+
+	for _, c := range []struct {
+		args     []string
+		from, to idx.Epoch
+	}{
+		{nil, 1, 10},
+		{[]string{"5"}, 5, 10},
+		{[]string{"5", "7"}, 5, 7},
+		{[]string{"10", "10"}, 10, 10},
+	} {
+		from, to, err := parseEpochRange(c.args, 10)
+		assertar.NoError(err, c.args)
+		assertar.Equal(c.from, from, c.args)
+		assertar.Equal(c.to, to, c.args)
+	}
+
+	for _, args := range [][]string{
+		{"10", "5"},
+		{"0"},
+		{"11"},
+		{"5", "11"},
+		{"x"},
+		{"1", "4294967296"},
+	} {
+		_, _, err := parseEpochRange(args, 10)
+		assertar.Error(err, args)
+	}
+}
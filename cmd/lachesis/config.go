@@ -22,6 +22,7 @@ import (
 	"github.com/Fantom-foundation/go-lachesis/evmcore"
 	"github.com/Fantom-foundation/go-lachesis/gossip"
 	"github.com/Fantom-foundation/go-lachesis/gossip/gasprice"
+	"github.com/Fantom-foundation/go-lachesis/integration"
 	"github.com/Fantom-foundation/go-lachesis/lachesis"
 )
 
@@ -54,6 +55,19 @@ var (
 		Usage: "Data directory for the databases and keystore",
 		Value: utils.DirectoryString(DefaultDataDir()),
 	}
+
+	// RPCLogsLimitFlag defines the max number of the logs returned by eth_getLogs
+	RPCLogsLimitFlag = cli.IntFlag{
+		Name:  "rpc.logslimit",
+		Usage: "Max number of the logs which eth_getLogs and the logs filters return, the query fails if more logs match (0 = no limit)",
+	}
+
+	// DbEngineFlag defines engine of the on-disk databases
+	DbEngineFlag = cli.StringFlag{
+		Name:  "db.engine",
+		Usage: "Engine of the databases (" + strings.Join(integration.DbEngines, ", ") + ")",
+		Value: integration.LevelDbEngine,
+	}
 )
 
 // These settings ensure that TOML keys use the same names as Go struct fields.
@@ -97,6 +111,8 @@ func defaultLachesisConfig(ctx *cli.Context) lachesis.Config {
 	var cfg lachesis.Config
 
 	switch {
+	case ctx.GlobalIsSet(GenesisFlag.Name):
+		cfg = genesisNetConfig(ctx)
 	case ctx.GlobalIsSet(FakeNetFlag.Name):
 		_, accs, err := parseFakeGen(ctx.GlobalString(FakeNetFlag.Name))
 		if err != nil {
@@ -118,6 +134,8 @@ func setDataDir(ctx *cli.Context, cfg *node.Config) {
 	switch {
 	case ctx.GlobalIsSet(utils.DataDirFlag.Name):
 		cfg.DataDir = ctx.GlobalString(utils.DataDirFlag.Name)
+	case ctx.GlobalIsSet(GenesisFlag.Name):
+		cfg.DataDir = filepath.Join(defaultDataDir, genesisNetName(ctx))
 	case ctx.GlobalIsSet(FakeNetFlag.Name):
 		_, accs, err := parseFakeGen(ctx.GlobalString(FakeNetFlag.Name))
 		if err != nil {
@@ -190,12 +208,16 @@ func gossipConfigWithFlags(ctx *cli.Context, src gossip.Config) gossip.Config {
 	cfg := src
 
 	// Avoid conflicting network flags
-	utils.CheckExclusive(ctx, FakeNetFlag, utils.DeveloperFlag, utils.TestnetFlag)
+	utils.CheckExclusive(ctx, FakeNetFlag, GenesisFlag, utils.DeveloperFlag, utils.TestnetFlag)
 	utils.CheckExclusive(ctx, FakeNetFlag, utils.DeveloperFlag, utils.ExternalSignerFlag) // Can't use both ephemeral unlocked and external signer
 
 	setGPO(ctx, &cfg.GPO)
 	setTxPool(ctx, &cfg.TxPool)
 
+	if ctx.GlobalIsSet(DbEngineFlag.Name) {
+		cfg.DBEngine = ctx.GlobalString(DbEngineFlag.Name)
+	}
+
 	if ctx.GlobalIsSet(utils.NetworkIdFlag.Name) {
 		cfg.Net.NetworkID = ctx.GlobalUint64(utils.NetworkIdFlag.Name)
 	}
@@ -224,6 +246,9 @@ func gossipConfigWithFlags(ctx *cli.Context, src gossip.Config) gossip.Config {
 	if ctx.GlobalIsSet(utils.RPCGlobalGasCap.Name) {
 		cfg.RPCGasCap = new(big.Int).SetUint64(ctx.GlobalUint64(utils.RPCGlobalGasCap.Name))
 	}
+	if ctx.GlobalIsSet(RPCLogsLimitFlag.Name) {
+		cfg.RPCLogsLimit = ctx.GlobalInt(RPCLogsLimitFlag.Name)
+	}
 
 	return cfg
 }
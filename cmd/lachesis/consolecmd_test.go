@@ -17,7 +17,7 @@ import (
 )
 
 const (
-	ipcAPIs  = "admin:1.0 debug:1.0 ftm:1.0 net:1.0 personal:1.0 rpc:1.0 sfc:1.0 txpool:1.0 web3:1.0"
+	ipcAPIs  = "admin:1.0 dag:1.0 debug:1.0 ftm:1.0 net:1.0 personal:1.0 rpc:1.0 sfc:1.0 txpool:1.0 web3:1.0"
 	httpAPIs = "ftm:1.0 rpc:1.0 sfc:1.0 web3:1.0"
 )
 
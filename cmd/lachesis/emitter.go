@@ -9,11 +9,22 @@ import (
 	"github.com/Fantom-foundation/go-lachesis/gossip"
 )
 
-var validatorFlag = cli.StringFlag{
-	Name:  "validator",
-	Usage: "Address of a validator to create events from",
-	Value: "no",
-}
+var (
+	validatorFlag = cli.StringFlag{
+		Name:  "validator",
+		Usage: "Address of a validator to create events from",
+		Value: "no",
+	}
+	validatorStandbyFlag = cli.DurationFlag{
+		Name: "validator.standby",
+		Usage: "Run as a hot-standby node of the validator, which takes over the events emitting " +
+			"after the validator's events have stopped for the given time (requires the same key on the both nodes)",
+	}
+	validatorSignerFlag = cli.StringFlag{
+		Name:  "validator.signer",
+		Usage: "External signer of the validator's events (IPC path or HTTP/WS URL), the validator key isn't needed in the keystore",
+	}
+)
 
 // setValidator retrieves the validator address either from the directly specified
 // command line flags or from the keystore if CLI indexed.
@@ -37,6 +48,15 @@ func setValidator(ctx *cli.Context, ks *keystore.KeyStore, cfg *gossip.EmitterCo
 		}
 	}
 
+	if ctx.GlobalIsSet(validatorStandbyFlag.Name) {
+		cfg.HotStandby.Enabled = true
+		cfg.HotStandby.Takeover = ctx.GlobalDuration(validatorStandbyFlag.Name)
+	}
+
+	if ctx.GlobalIsSet(validatorSignerFlag.Name) {
+		cfg.ExternalSigner.Endpoint = ctx.GlobalString(validatorSignerFlag.Name)
+	}
+
 	// Convert the validator into an address and configure it
 	if validator == "" {
 		return
@@ -0,0 +1,353 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/naoina/toml"
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/inter/pos"
+	"github.com/Fantom-foundation/go-lachesis/lachesis"
+	"github.com/Fantom-foundation/go-lachesis/lachesis/genesis"
+	lutils "github.com/Fantom-foundation/go-lachesis/utils"
+)
+
+// genesisHashFile is the file in the datadir with the hash of the genesis file, which the datadir is initialized with.
+const genesisHashFile = "genesis.hash"
+
+var (
+	// GenesisFlag loads the network definition and genesis from file
+	GenesisFlag = cli.StringFlag{
+		Name:  "genesis",
+		Usage: "Network definition and genesis file (JSON or TOML), see 'lachesis genesis init'",
+	}
+	// GenesisHashFlag is the expected canonical hash of the genesis file
+	GenesisHashFlag = cli.StringFlag{
+		Name:  "genesis.hash",
+		Usage: "Expected canonical hash of the genesis file, see 'lachesis genesis hash'",
+	}
+
+	genesisNameFlag = cli.StringFlag{
+		Name:  "name",
+		Usage: "Network name",
+		Value: "private",
+	}
+	genesisNetworkIDFlag = cli.Uint64Flag{
+		Name:  "networkid",
+		Usage: "Network identifier, which is also the EVM chain ID",
+	}
+	genesisValidatorsFlag = cli.StringFlag{
+		Name:  "validators",
+		Usage: "Comma separated validator addresses or keystore files",
+	}
+	genesisStakeFlag = cli.Uint64Flag{
+		Name:  "stake",
+		Usage: "Stake of every validator, FTM",
+		Value: 3175000,
+	}
+	genesisBalanceFlag = cli.Uint64Flag{
+		Name:  "balance",
+		Usage: "Balance of every validator, FTM",
+	}
+	genesisAllocFlag = cli.StringFlag{
+		Name:  "alloc",
+		Usage: "Comma separated allocations of other accounts, 'address:FTM'",
+	}
+	genesisAdminFlag = cli.StringFlag{
+		Name:  "admin",
+		Usage: "SFC contract admin address (default = the first validator)",
+	}
+	genesisTimeFlag = cli.Uint64Flag{
+		Name:  "time",
+		Usage: "Genesis unix time, seconds (default = now)",
+	}
+	genesisSfcFlag = cli.StringFlag{
+		Name:  "sfc",
+		Usage: "SFC contract binary (" + genesis.MainSfcContract + ", " + genesis.TestSfcContract + ")",
+		Value: genesis.MainSfcContract,
+	}
+
+	genesisCommand = cli.Command{
+		Name:     "genesis",
+		Usage:    "Manage the network definition and genesis files",
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+A genesis file defines a private network: its name and ID, the genesis allocations,
+validators and stakes, SFC admin, genesis time, and the dag/economy rules with the
+scheduled upgrades. The node runs the network with the --genesis <file> flag.
+The canonical hash of the file doesn't depend on its format, it may be checked
+at the node start with the --genesis.hash flag. The hash is saved in the datadir
+at its initialization, and the node refuses to start with another genesis file.`,
+		Subcommands: []cli.Command{
+			{
+				Name:      "init",
+				Usage:     "Build a genesis file from the validator keys",
+				Action:    utils.MigrateFlags(genesisInit),
+				ArgsUsage: "<filename>",
+				Flags: []cli.Flag{
+					genesisNameFlag,
+					genesisNetworkIDFlag,
+					genesisValidatorsFlag,
+					genesisStakeFlag,
+					genesisBalanceFlag,
+					genesisAllocFlag,
+					genesisAdminFlag,
+					genesisTimeFlag,
+					genesisSfcFlag,
+				},
+				Description: `
+    lachesis genesis init --networkid <id> --validators <addr or keyfile>,... <filename>
+
+Writes the genesis file with the default rules, which may be edited before the
+network start. The file is written in TOML if its name ends with .toml, in JSON otherwise.
+The validator IDs are assigned in the order of the --validators list, starting from 1.`,
+			},
+			{
+				Name:      "hash",
+				Usage:     "Print the canonical hash of a genesis file",
+				Action:    utils.MigrateFlags(genesisHash),
+				ArgsUsage: "<filename>",
+			},
+		},
+	}
+)
+
+// loadNetworkSpec reads the network spec from JSON or TOML file, depending on the extension.
+// The fields which aren't in the file have default values.
+func loadNetworkSpec(path string) (*lachesis.NetworkSpec, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	spec := lachesis.DefaultNetworkSpec("", 0)
+	if isTomlFile(path) {
+		err = tomlSettings.NewDecoder(bufio.NewReader(f)).Decode(&spec)
+		// Add file name to errors that have a line number.
+		if _, ok := err.(*toml.LineError); ok {
+			err = errors.New(path + ", " + err.Error())
+		}
+	} else {
+		dec := json.NewDecoder(bufio.NewReader(f))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&spec)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
+// writeNetworkSpec writes the network spec into JSON or TOML file, depending on the extension.
+func writeNetworkSpec(path string, spec *lachesis.NetworkSpec) error {
+	var (
+		out []byte
+		err error
+	)
+	if isTomlFile(path) {
+		out, err = tomlSettings.Marshal(spec)
+	} else {
+		out, err = json.MarshalIndent(spec, "", "  ")
+	}
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, out, 0644)
+}
+
+func isTomlFile(path string) bool {
+	return strings.ToLower(filepath.Ext(path)) == ".toml"
+}
+
+// genesisNetConfig loads the network config from the genesis file and checks its hash.
+func genesisNetConfig(ctx *cli.Context) lachesis.Config {
+	path := ctx.GlobalString(GenesisFlag.Name)
+	spec, err := loadNetworkSpec(path)
+	if err != nil {
+		utils.Fatalf("Failed to load genesis file: %v", err)
+	}
+
+	specHash := spec.Hash()
+	if ctx.GlobalIsSet(GenesisHashFlag.Name) {
+		expected := common.HexToHash(ctx.GlobalString(GenesisHashFlag.Name))
+		if specHash != expected {
+			utils.Fatalf("Genesis file %s has hash %s, expected %s", path, specHash.Hex(), expected.Hex())
+		}
+	}
+
+	cfg, err := spec.Config()
+	if err != nil {
+		utils.Fatalf("Invalid genesis file %s: %v", path, err)
+	}
+	log.Info("Loaded genesis file", "file", path, "network", cfg.Name, "hash", specHash.Hex())
+	return cfg
+}
+
+// checkDatadirGenesis saves the hash of the genesis file into the datadir at its initialization,
+// and checks that the datadir is opened with the same genesis file afterwards.
+func checkDatadirGenesis(ctx *cli.Context, datadir string) {
+	if datadir == "inmemory" || datadir == "" {
+		return
+	}
+	path := filepath.Join(datadir, genesisHashFile)
+	saved, err := ioutil.ReadFile(path)
+	initialized := err == nil
+	if err != nil && !os.IsNotExist(err) {
+		utils.Fatalf("Failed to read %s: %v", path, err)
+	}
+
+	if !ctx.GlobalIsSet(GenesisFlag.Name) {
+		if initialized {
+			utils.Fatalf("Datadir %s is initialized with the genesis file of hash %s, use the --%s flag", datadir, strings.TrimSpace(string(saved)), GenesisFlag.Name)
+		}
+		return
+	}
+	file := ctx.GlobalString(GenesisFlag.Name)
+	spec, err := loadNetworkSpec(file)
+	if err != nil {
+		utils.Fatalf("Failed to load genesis file: %v", err)
+	}
+	specHash := spec.Hash().Hex()
+
+	if initialized {
+		if strings.TrimSpace(string(saved)) != specHash {
+			utils.Fatalf("Genesis file %s has hash %s, but datadir %s is initialized with %s", file, specHash, datadir, strings.TrimSpace(string(saved)))
+		}
+		return
+	}
+	if err := os.MkdirAll(datadir, 0700); err != nil {
+		utils.Fatalf("Failed to create datadir %s: %v", datadir, err)
+	}
+	if err := ioutil.WriteFile(path, []byte(specHash+"\n"), 0644); err != nil {
+		utils.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
+
+// genesisNetName returns the network name from the genesis file.
+func genesisNetName(ctx *cli.Context) string {
+	spec, err := loadNetworkSpec(ctx.GlobalString(GenesisFlag.Name))
+	if err != nil {
+		utils.Fatalf("Failed to load genesis file: %v", err)
+	}
+	return spec.Name
+}
+
+func genesisInit(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+	path := ctx.Args().First()
+
+	spec := lachesis.DefaultNetworkSpec(ctx.String(genesisNameFlag.Name), ctx.Uint64(genesisNetworkIDFlag.Name))
+	spec.Genesis.SfcContract = ctx.String(genesisSfcFlag.Name)
+	spec.Genesis.Time = inter.Timestamp(time.Now().Unix()) * inter.Timestamp(time.Second)
+	if ctx.IsSet(genesisTimeFlag.Name) {
+		spec.Genesis.Time = inter.Timestamp(ctx.Uint64(genesisTimeFlag.Name)) * inter.Timestamp(time.Second)
+	}
+
+	stake := lutils.ToFtm(ctx.Uint64(genesisStakeFlag.Name))
+	balance := lutils.ToFtm(ctx.Uint64(genesisBalanceFlag.Name))
+	for i, s := range splitList(ctx.String(genesisValidatorsFlag.Name)) {
+		addr, err := parseKeyAddress(s)
+		if err != nil {
+			return err
+		}
+		spec.Genesis.Validators = append(spec.Genesis.Validators, pos.GenesisValidator{
+			ID:      idx.StakerID(i + 1),
+			Address: addr,
+			Stake:   stake,
+		})
+		spec.Genesis.Accounts[addr] = genesis.Account{Balance: balance}
+	}
+	if len(spec.Genesis.Validators) == 0 {
+		utils.Fatalf("No validators, use the --%s flag", genesisValidatorsFlag.Name)
+	}
+
+	for _, s := range splitList(ctx.String(genesisAllocFlag.Name)) {
+		parts := strings.SplitN(s, ":", 2)
+		if len(parts) != 2 || !common.IsHexAddress(parts[0]) {
+			return fmt.Errorf("invalid allocation %q, use 'address:FTM' format", s)
+		}
+		ftm, ok := new(big.Int).SetString(parts[1], 10)
+		if !ok {
+			return fmt.Errorf("invalid allocation %q, use 'address:FTM' format", s)
+		}
+		spec.Genesis.Accounts[common.HexToAddress(parts[0])] = genesis.Account{Balance: ftm.Mul(ftm, lutils.ToFtm(1))}
+	}
+
+	spec.Genesis.SfcContractAdmin = spec.Genesis.Validators[0].Address
+	if ctx.IsSet(genesisAdminFlag.Name) {
+		admin := ctx.String(genesisAdminFlag.Name)
+		if !common.IsHexAddress(admin) {
+			return fmt.Errorf("invalid SFC admin address %q", admin)
+		}
+		spec.Genesis.SfcContractAdmin = common.HexToAddress(admin)
+	}
+
+	if _, err := spec.Config(); err != nil {
+		return err
+	}
+	if err := writeNetworkSpec(path, &spec); err != nil {
+		return err
+	}
+	fmt.Printf("Genesis file %s is written, hash %s\n", path, spec.Hash().Hex())
+	return nil
+}
+
+func genesisHash(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+	spec, err := loadNetworkSpec(ctx.Args().First())
+	if err != nil {
+		return err
+	}
+	if _, err := spec.Config(); err != nil {
+		return err
+	}
+	fmt.Println(spec.Hash().Hex())
+	return nil
+}
+
+// parseKeyAddress returns the address, which is either given as is, or read from the keystore file.
+func parseKeyAddress(s string) (common.Address, error) {
+	if common.IsHexAddress(s) {
+		return common.HexToAddress(s), nil
+	}
+	raw, err := ioutil.ReadFile(s)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("%q is neither address nor keystore file: %v", s, err)
+	}
+	var key struct {
+		Address string `json:"address"`
+	}
+	if err := json.Unmarshal(raw, &key); err != nil || !common.IsHexAddress(key.Address) {
+		return common.Address{}, fmt.Errorf("%q isn't a keystore file", s)
+	}
+	return common.HexToAddress(key.Address), nil
+}
+
+func splitList(s string) []string {
+	var res []string
+	for _, it := range strings.Split(s, ",") {
+		if it = strings.TrimSpace(it); it != "" {
+			res = append(res, it)
+		}
+	}
+	return res
+}
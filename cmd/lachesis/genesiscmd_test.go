@@ -0,0 +1,115 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+)
+
+func TestGenesisInit(t *testing.T) {
+	assertar := assert.New(t)
+
+	dir := tmpdir(t)
+	validators := strings.Join([]string{
+		filepath.Join("testdata", "keystore", "aaa"),
+		"0x289d485d9771714cce91d3393d764e1311907acc",
+	}, ",")
+
+	var hashes []common.Hash
+	for _, name := range []string{"net.json", "net.toml"} {
+		file := filepath.Join(dir, name)
+		cli := exec(t, "genesis", "init",
+			"--name", "private", "--networkid", "4000", "--time", "1600000000",
+			"--validators", validators,
+			"--alloc", "0xd6A37423Be930019b8CFeA57BE049329f3119a3D:1000",
+			file)
+		cli.ExpectRegexp(`Genesis file .+ is written, hash 0x[0-9a-f]{64}\n`)
+		cli.ExpectExit()
+
+		spec, err := loadNetworkSpec(file)
+		if !assertar.NoError(err) {
+			return
+		}
+		cfg, err := spec.Config()
+		if !assertar.NoError(err) {
+			return
+		}
+		assertar.Equal("private", cfg.Name)
+		assertar.Equal(uint64(4000), cfg.NetworkID)
+		assertar.Equal(2, len(cfg.Genesis.Alloc.Validators))
+		assertar.Equal(idx.StakerID(1), cfg.Genesis.Alloc.Validators[0].ID)
+		assertar.Equal(common.HexToAddress("0xf466859ead1932d743d622cb74fc058882e8648a"), cfg.Genesis.Alloc.Validators[0].Address)
+		assertar.Equal(cfg.Genesis.Alloc.Validators[0].Address, cfg.Genesis.Alloc.SfcContractAdmin)
+		hashes = append(hashes, spec.Hash())
+	}
+	// the hash doesn't depend on the file format
+	assertar.Equal(hashes[0], hashes[1])
+
+	cli := exec(t, "genesis", "hash", filepath.Join(dir, "net.toml"))
+	cli.Expect(hashes[0].Hex() + "\n")
+	cli.ExpectExit()
+
+	cli = exec(t, "--genesis", filepath.Join(dir, "net.json"), "--genesis.hash", common.Hash{}.Hex(), "dumpconfig")
+	cli.ExpectRegexp(`Fatal: Genesis file .+ has hash 0x[0-9a-f]{64}, expected 0x0{64}\n`)
+	cli.ExpectExit()
+}
+
+func TestGenesisDatadirHash(t *testing.T) {
+	assertar := assert.New(t)
+
+	dir := tmpdir(t)
+	defer os.RemoveAll(dir)
+	datadir := filepath.Join(dir, "datadir")
+	// the datadir is shared by the runs
+	run := func(args ...string) *testcli {
+		cli := exec(t, append([]string{"--datadir", datadir}, args...)...)
+		cli.Cleanup = nil
+		return cli
+	}
+
+	var (
+		files  []string
+		hashes []common.Hash
+	)
+	for _, networkID := range []string{"4000", "4001"} {
+		file := filepath.Join(dir, networkID+".json")
+		cli := exec(t, "genesis", "init",
+			"--networkid", networkID, "--time", "1600000000",
+			"--validators", "0x289d485d9771714cce91d3393d764e1311907acc",
+			file)
+		cli.ExpectRegexp(`Genesis file .+ is written, hash 0x[0-9a-f]{64}\n`)
+		cli.ExpectExit()
+
+		spec, err := loadNetworkSpec(file)
+		if !assertar.NoError(err) {
+			return
+		}
+		files = append(files, file)
+		hashes = append(hashes, spec.Hash())
+	}
+
+	// the hash is saved at the datadir initialization
+	cli := run("--genesis", files[0], "reindex")
+	cli.ExpectExit()
+	saved, err := ioutil.ReadFile(filepath.Join(datadir, genesisHashFile))
+	if assertar.NoError(err) {
+		assertar.Equal(hashes[0].Hex()+"\n", string(saved))
+	}
+	cli = run("--genesis", files[0], "reindex")
+	cli.ExpectExit()
+
+	cli = run("--genesis", files[1], "reindex")
+	cli.ExpectRegexp(`Fatal: Genesis file .+ has hash ` + hashes[1].Hex() + `, but datadir .+ is initialized with ` + hashes[0].Hex() + `\n`)
+	cli.ExpectExit()
+
+	cli = run("--fakenet", "1/1", "reindex")
+	cli.ExpectRegexp(`Fatal: Datadir .+ is initialized with the genesis file of hash ` + hashes[0].Hex() + `, use the --genesis flag\n`)
+	cli.ExpectExit()
+}
@@ -18,11 +18,13 @@ import (
 	"github.com/ethereum/go-ethereum/params"
 	"gopkg.in/urfave/cli.v1"
 
+	lachesisapp "github.com/Fantom-foundation/go-lachesis/app"
 	"github.com/Fantom-foundation/go-lachesis/cmd/lachesis/metrics"
 	"github.com/Fantom-foundation/go-lachesis/cmd/lachesis/tracing"
 	"github.com/Fantom-foundation/go-lachesis/debug"
 	"github.com/Fantom-foundation/go-lachesis/gossip"
 	"github.com/Fantom-foundation/go-lachesis/integration"
+	"github.com/Fantom-foundation/go-lachesis/poset"
 	"github.com/Fantom-foundation/go-lachesis/utils/errlock"
 	_ "github.com/Fantom-foundation/go-lachesis/version"
 )
@@ -64,6 +66,7 @@ func init() {
 		utils.BootnodesV4Flag,
 		utils.BootnodesV5Flag,
 		DataDirFlag,
+		DbEngineFlag,
 		utils.KeyStoreDirFlag,
 		utils.ExternalSignerFlag,
 		utils.NoUSBFlag,
@@ -95,6 +98,8 @@ func init() {
 		utils.NodeKeyFileFlag,
 		utils.NodeKeyHexFlag,
 		utils.TestnetFlag,
+		GenesisFlag,
+		GenesisHashFlag,
 		utils.VMEnableDebugFlag,
 		utils.NetworkIdFlag,
 		utils.EthStatsURLFlag,
@@ -106,6 +111,8 @@ func init() {
 		utils.EVMInterpreterFlag,
 		configFileFlag,
 		validatorFlag,
+		validatorStandbyFlag,
+		validatorSignerFlag,
 	}
 
 	rpcFlags = []cli.Flag{
@@ -129,6 +136,7 @@ func init() {
 		utils.IPCPathFlag,
 		utils.InsecureUnlockAllowedFlag,
 		utils.RPCGlobalGasCap,
+		RPCLogsLimitFlag,
 	}
 
 	metricsFlags = []cli.Flag{
@@ -159,6 +167,19 @@ func init() {
 		javascriptCommand,
 		// See config.go:
 		dumpConfigCommand,
+		// See chaincmd.go:
+		importEventsCommand,
+		reindexCommand,
+		replayCommand,
+		exportEventsCommand,
+		exportDagCommand,
+		importSnapshotCommand,
+		exportSnapshotCommand,
+		// See genesiscmd.go:
+		genesisCommand,
+		slashingProtectionCommand,
+		// See signercmd.go:
+		signerCommand,
 		// See misccmd.go:
 		versionCommand,
 		licenseCommand,
@@ -231,7 +252,8 @@ func makeFullNode(ctx *cli.Context) *node.Node {
 
 	stack := makeConfigNode(ctx, &cfg.Node)
 
-	engine, adb, gdb := integration.MakeEngine(cfg.Node.DataDir, &cfg.Lachesis)
+	engine, adb, gdb := makeEngine(ctx, &cfg)
+	protection := integration.MakeSlashingProtection(cfg.Node.DataDir, &cfg.Lachesis)
 	metrics.SetDataDir(cfg.Node.DataDir)
 
 	// configure emitter
@@ -240,13 +262,22 @@ func makeFullNode(ctx *cli.Context) *node.Node {
 		ks = keystores[0].(*keystore.KeyStore)
 	}
 	setValidator(ctx, ks, &cfg.Lachesis.Emitter)
+	signer := integration.MakeEventSigner(&cfg.Lachesis)
 
 	// Create and register a gossip network service. This is done through the definition
 	// of a node.ServiceConstructor that will instantiate a node.Service. The reason for
 	// the factory method approach is to support service restarts without relying on the
 	// individual implementations' support for such operations.
 	gossipService := func(ctx *node.ServiceContext) (node.Service, error) {
-		return gossip.NewService(ctx, &cfg.Lachesis, gdb, engine, adb)
+		svc, err := gossip.NewService(ctx, &cfg.Lachesis, gdb, engine, adb)
+		if err != nil {
+			return nil, err
+		}
+		svc.SetSlashingProtection(protection)
+		if signer != nil {
+			svc.SetEventSigner(signer)
+		}
+		return svc, nil
 	}
 
 	if err := stack.Register(gossipService); err != nil {
@@ -256,6 +287,12 @@ func makeFullNode(ctx *cli.Context) *node.Node {
 	return stack
 }
 
+// makeEngine opens the DBs of the datadir, after checking that it's initialized with the same genesis file.
+func makeEngine(ctx *cli.Context, cfg *config) (*poset.Poset, *lachesisapp.Store, *gossip.Store) {
+	checkDatadirGenesis(ctx, cfg.Node.DataDir)
+	return integration.MakeEngine(cfg.Node.DataDir, &cfg.Lachesis)
+}
+
 func makeConfigNode(ctx *cli.Context, cfg *node.Config) *node.Node {
 	stack, err := node.New(cfg)
 	if err != nil {
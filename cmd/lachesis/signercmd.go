@@ -0,0 +1,99 @@
+package main
+
+import (
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/Fantom-foundation/go-lachesis/gossip/extsigner"
+	"github.com/Fantom-foundation/go-lachesis/integration"
+)
+
+var signerIPCPathFlag = cli.StringFlag{
+	Name:  "signer.ipcpath",
+	Usage: "Filename for the signer IPC socket/pipe within the datadir (explicit paths escape it)",
+	Value: "signer.ipc",
+}
+
+var signerCommand = cli.Command{
+	Name:     "signer",
+	Usage:    "Run a stand-in external signer of the validators events",
+	Category: "ACCOUNT COMMANDS",
+	Action:   utils.MigrateFlags(runSigner),
+	Flags: []cli.Flag{
+		DataDirFlag,
+		DbEngineFlag,
+		FakeNetFlag,
+		utils.TestnetFlag,
+		GenesisFlag,
+		configFileFlag,
+		utils.KeyStoreDirFlag,
+		utils.UnlockedAccountFlag,
+		utils.PasswordFileFlag,
+		signerIPCPathFlag,
+	},
+	Description: `
+    lachesis signer --unlock <address> --password <file>
+
+Signs the events of the unlocked validator keys for the nodes started with
+--validator.signer <ipc path>, so the keys aren't kept on the nodes.
+The signer refuses the events which may conflict with the already signed ones,
+by the own slashing-protection DB in the datadir. So the datadir must differ
+from the datadir of a node.`,
+}
+
+func runSigner(ctx *cli.Context) error {
+	cfg := makeAllConfigs(ctx)
+
+	scryptN, scryptP, keydir, err := cfg.Node.AccountConfig()
+	if err != nil {
+		utils.Fatalf("Failed to read configuration: %v", err)
+	}
+	ks := keystore.NewKeyStore(keydir, scryptN, scryptP)
+	am := accounts.NewManager(&accounts.Config{}, ks)
+	defer am.Close()
+
+	passwords := utils.MakePasswordList(ctx)
+	i := 0
+	for _, input := range strings.Split(ctx.GlobalString(utils.UnlockedAccountFlag.Name), ",") {
+		if trimmed := strings.TrimSpace(input); trimmed != "" {
+			unlockAccount(ks, trimmed, i, passwords)
+			i++
+		}
+	}
+	if i == 0 {
+		utils.Fatalf("No validator account to unlock, use --%s", utils.UnlockedAccountFlag.Name)
+	}
+
+	protection := integration.MakeSlashingProtection(cfg.Node.DataDir, &cfg.Lachesis)
+	defer protection.Close()
+
+	endpoint := ctx.String(signerIPCPathFlag.Name)
+	if !filepath.IsAbs(endpoint) {
+		endpoint = filepath.Join(cfg.Node.DataDir, endpoint)
+	}
+	signer := extsigner.NewService(am, protection)
+	listener, handler, err := rpc.StartIPCEndpoint(endpoint, signer.APIs())
+	if err != nil {
+		utils.Fatalf("Failed to start the signer IPC endpoint: %v", err)
+	}
+	defer handler.Stop()
+	defer listener.Close()
+	log.Info("Signer started", "url", endpoint)
+
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigc)
+	<-sigc
+	log.Info("Signer stopped")
+	return nil
+}
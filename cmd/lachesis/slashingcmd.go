@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/Fantom-foundation/go-lachesis/integration"
+)
+
+var slashingProtectionCommand = cli.Command{
+	Name:     "slashing-protection",
+	Usage:    "Manage the slashing-protection DB of the validator keys",
+	Category: "ACCOUNT COMMANDS",
+	Description: `
+The slashing-protection DB keeps the highest event signed by every validator key
+of the node, and the emitter refuses to sign an event which may conflict with it.
+The DB is stored apart from the chain DBs, in the slashing-protection subdir of the datadir.
+When a validator key is moved to another node, export the DB on the old node and import
+it on the new one before the start, so the new node doesn't double-sign the events.
+The node must be stopped during the export and import.`,
+	Subcommands: []cli.Command{
+		{
+			Name:      "export",
+			Usage:     "Export the slashing-protection data into JSON file",
+			Action:    utils.MigrateFlags(slashingProtectionExport),
+			ArgsUsage: "<filename> [<address>...]",
+			Flags: []cli.Flag{
+				DataDirFlag,
+				DbEngineFlag,
+				FakeNetFlag,
+				utils.TestnetFlag,
+				GenesisFlag,
+				configFileFlag,
+			},
+			Description: `
+    lachesis slashing-protection export <filename> [<address>...]
+
+Writes the data of all the validator keys, or of the given ones only.`,
+		},
+		{
+			Name:      "import",
+			Usage:     "Import the slashing-protection data from JSON file",
+			Action:    utils.MigrateFlags(slashingProtectionImport),
+			ArgsUsage: "<filename>",
+			Flags: []cli.Flag{
+				DataDirFlag,
+				DbEngineFlag,
+				FakeNetFlag,
+				utils.TestnetFlag,
+				GenesisFlag,
+				configFileFlag,
+			},
+			Description: `
+    lachesis slashing-protection import <filename>
+
+Merges the data with the existing one, so the node is protected from conflicts
+with the events signed on the both nodes. The data of another genesis is refused.`,
+		},
+	},
+}
+
+func slashingProtectionExport(ctx *cli.Context) error {
+	if len(ctx.Args()) < 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+	var validators []common.Address
+	for _, s := range ctx.Args()[1:] {
+		if !common.IsHexAddress(s) {
+			return fmt.Errorf("invalid address %q", s)
+		}
+		validators = append(validators, common.HexToAddress(s))
+	}
+
+	cfg := makeAllConfigs(ctx)
+	protection := integration.MakeSlashingProtection(cfg.Node.DataDir, &cfg.Lachesis)
+	defer protection.Close()
+
+	fn := ctx.Args().First()
+	fh, err := os.OpenFile(fn, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	if err := protection.Export(fh, validators...); err != nil {
+		return err
+	}
+	fmt.Printf("Slashing-protection data is exported into %s\n", fn)
+	return nil
+}
+
+func slashingProtectionImport(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+
+	cfg := makeAllConfigs(ctx)
+	protection := integration.MakeSlashingProtection(cfg.Node.DataDir, &cfg.Lachesis)
+	defer protection.Close()
+
+	fn := ctx.Args().First()
+	fh, err := os.Open(fn)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	n, err := protection.Import(fh)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Slashing-protection data of %d validators is imported from %s\n", n, fn)
+	return nil
+}
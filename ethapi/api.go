@@ -1815,6 +1815,20 @@ func (api *PrivateDebugAPI) SetHead(number hexutil.Uint64) error {
 	return errors.New("lachesis cannot rewind blocks due to the BFT algorithm")
 }
 
+// GetFinalityProof returns the proof that the block is final, which is verifiable without the full DAG
+// by the finality package: the block Atropos, the signed epoch events up to the election of the block frame,
+// and the epoch validators. It's private, because the election of the block is recalculated
+// from the epoch start for every not cached proof.
+// The proof is returned along with its RLP encoding.
+// * When blockNr is -1 the proof of latest block is returned.
+func (api *PrivateDebugAPI) GetFinalityProof(ctx context.Context, blockNr rpc.BlockNumber) (map[string]interface{}, error) {
+	proof, err := api.b.GetFinalityProof(ctx, blockNr)
+	if err != nil {
+		return nil, err
+	}
+	return RPCMarshalFinalityProof(proof)
+}
+
 // PublicNetAPI offers network related RPC methods
 type PublicNetAPI struct {
 	net            *p2p.Server
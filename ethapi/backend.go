@@ -33,11 +33,14 @@ import (
 	"github.com/ethereum/go-ethereum/rpc"
 
 	"github.com/Fantom-foundation/go-lachesis/evmcore"
+	"github.com/Fantom-foundation/go-lachesis/finality"
 	"github.com/Fantom-foundation/go-lachesis/hash"
 	"github.com/Fantom-foundation/go-lachesis/inter"
 	"github.com/Fantom-foundation/go-lachesis/inter/idx"
 	"github.com/Fantom-foundation/go-lachesis/inter/pos"
 	"github.com/Fantom-foundation/go-lachesis/inter/sfctype"
+	"github.com/Fantom-foundation/go-lachesis/light"
+	"github.com/Fantom-foundation/go-lachesis/poset/election"
 )
 
 // PeerProgress is synchronization status of a peer
@@ -84,6 +87,7 @@ type Backend interface {
 	SubscribeNewTxsNotify(chan<- evmcore.NewTxsNotify) notify.Subscription
 
 	ChainConfig() *params.ChainConfig
+	BlockChainConfig(header *evmcore.EvmHeader) *params.ChainConfig
 	CurrentBlock() *evmcore.EvmBlock
 
 	// Lachesis DAG API
@@ -96,6 +100,11 @@ type Backend interface {
 	TtfReport(ctx context.Context, untilBlock rpc.BlockNumber, maxBlocks idx.Block, mode string) (map[hash.Event]time.Duration, error)
 	ForEachEvent(ctx context.Context, epoch rpc.BlockNumber, onEvent func(event *inter.Event) bool) error
 	ValidatorTimeDrifts(ctx context.Context, epoch rpc.BlockNumber, maxEvents idx.Event) (map[idx.StakerID]map[hash.Event]time.Duration, error)
+	GetForkEvidences(ctx context.Context, stakerID idx.StakerID) ([]*inter.ForkEvidence, error)
+	GetEpochDag(ctx context.Context, epoch rpc.BlockNumber, withArrivalTime bool) (*inter.EpochDag, error)
+	GetElection(ctx context.Context, epoch rpc.BlockNumber, frame idx.Frame) (*election.Record, error)
+	GetFinalityProof(ctx context.Context, number rpc.BlockNumber) (*finality.Proof, error)
+	GetEpochProof(ctx context.Context, epoch rpc.BlockNumber) (*light.EpochProof, error)
 
 	// Lachesis SFC API
 	GetValidators(ctx context.Context) *pos.Validators
@@ -137,6 +146,11 @@ func GetAPIs(apiBackend Backend) []rpc.API {
 			Version:   "1.0",
 			Service:   NewPublicTransactionPoolAPI(apiBackend, nonceLock),
 			Public:    true,
+		}, {
+			Namespace: "dag",
+			Version:   "1.0",
+			Service:   NewPublicDAGChainAPI(apiBackend),
+			Public:    true,
 		}, {
 			Namespace: "txpool",
 			Version:   "1.0",
@@ -5,15 +5,20 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/beorn7/perks/histogram"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/rpc"
 
+	"github.com/Fantom-foundation/go-lachesis/finality"
 	"github.com/Fantom-foundation/go-lachesis/hash"
 	"github.com/Fantom-foundation/go-lachesis/inter"
 	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/light"
+	"github.com/Fantom-foundation/go-lachesis/poset/election"
 )
 
 // PublicDAGChainAPI provides an API to access the directed acyclic graph chain.
@@ -95,6 +100,216 @@ func (s *PublicDAGChainAPI) GetEpochStats(ctx context.Context, requestedEpoch rp
 	}, nil
 }
 
+// GetForkEvidence returns the proofs of the staker's forks (double-signing), ordered by epoch.
+// Each proof has two signed events of the staker with the same sequence number,
+// and its RLP encoding.
+func (s *PublicDAGChainAPI) GetForkEvidence(ctx context.Context, stakerID hexutil.Uint64) ([]map[string]interface{}, error) {
+	evidences, err := s.b.GetForkEvidences(ctx, idx.StakerID(stakerID))
+	if err != nil {
+		return nil, err
+	}
+	res := make([]map[string]interface{}, len(evidences))
+	for i, f := range evidences {
+		res[i], err = RPCMarshalForkEvidence(f)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// ExportEpoch returns the epoch events with their frames, Atropos marks, confirming blocks and cheaters branches.
+// Format is one of {json, dot}, the DOT format is returned as a Graphviz source string.
+// If arrivalTime, then the local arrival time of events is included.
+// * When epoch is -2 the DAG of latest epoch is returned.
+// * When epoch is -1 the DAG of latest sealed epoch is returned.
+func (s *PublicDAGChainAPI) ExportEpoch(ctx context.Context, epoch rpc.BlockNumber, format string, arrivalTime bool) (interface{}, error) {
+	if format != "json" && format != "dot" {
+		return nil, errors.New("format must be one of {json, dot}")
+	}
+
+	dag, err := s.b.GetEpochDag(ctx, epoch, arrivalTime)
+	if err != nil {
+		return nil, err
+	}
+	if format == "json" {
+		return dag, nil
+	}
+	dot := &strings.Builder{}
+	err = inter.WriteDagDOT(dot, dag)
+	return dot.String(), err
+}
+
+// GetElection returns the votes of the decided election of the frame, if ElectionsIndex is enabled:
+// the candidate roots of the frame, the voting roots, each root's vote for each validator slot,
+// the round where each slot was decided, and the chosen Atropos.
+// * When epoch is omitted or -2 the election of latest epoch is returned.
+// * When epoch is -1 the election of latest sealed epoch is returned.
+func (s *PublicDAGChainAPI) GetElection(ctx context.Context, frame hexutil.Uint64, epoch *rpc.BlockNumber) (map[string]interface{}, error) {
+	requested := rpc.PendingBlockNumber
+	if epoch != nil {
+		requested = *epoch
+	}
+	record, err := s.b.GetElection(ctx, requested, idx.Frame(frame))
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, fmt.Errorf("election of frame %d not found", frame)
+	}
+	return RPCMarshalElection(record), nil
+}
+
+// RPCMarshalFinalityProof converts the finality proof to the RPC output.
+func RPCMarshalFinalityProof(p *finality.Proof) (map[string]interface{}, error) {
+	raw, err := rlp.EncodeToBytes(p)
+	if err != nil {
+		return nil, err
+	}
+	validators := make([]map[string]interface{}, len(p.Addresses))
+	for i, id := range p.Validators.SortedIDs() {
+		validators[i] = map[string]interface{}{
+			"stakerID": hexutil.Uint64(id),
+			"address":  p.Addresses[i],
+			"stake":    hexutil.Uint64(p.Validators.Get(id)),
+		}
+	}
+	return map[string]interface{}{
+		"block":         hexutil.Uint64(p.Block),
+		"epochStart":    hexutil.Uint64(p.EpochStart),
+		"atropos":       hexutil.Bytes(p.Atropos.Bytes()),
+		"events":        hexutil.Uint64(len(p.Events)),
+		"validators":    validators,
+		"evictCheaters": p.EvictCheaters,
+		"rlp":           hexutil.Bytes(raw),
+	}, nil
+}
+
+// GetEpochProof returns the proof of the epoch sealing for the light client: the results of the epoch blocks,
+// the validators of the next epoch, and the first events of the next epoch which sign the epoch state.
+// The proof is returned along with its RLP encoding.
+// * When epoch is -1 the proof of the last sealed epoch is returned.
+func (s *PublicDAGChainAPI) GetEpochProof(ctx context.Context, epoch rpc.BlockNumber) (map[string]interface{}, error) {
+	proof, err := s.b.GetEpochProof(ctx, epoch)
+	if err != nil {
+		return nil, err
+	}
+	return RPCMarshalEpochProof(proof)
+}
+
+// RPCMarshalEpochProof converts the epoch proof to the RPC output.
+func RPCMarshalEpochProof(p *light.EpochProof) (map[string]interface{}, error) {
+	raw, err := rlp.EncodeToBytes(p)
+	if err != nil {
+		return nil, err
+	}
+	validators := make([]map[string]interface{}, len(p.NextAddresses))
+	for i, id := range p.NextValidators.SortedIDs() {
+		validators[i] = map[string]interface{}{
+			"stakerID": hexutil.Uint64(id),
+			"address":  p.NextAddresses[i],
+			"stake":    hexutil.Uint64(p.NextValidators.Get(id)),
+		}
+	}
+	return map[string]interface{}{
+		"epoch":          hexutil.Uint64(p.Epoch),
+		"blocks":         hexutil.Uint64(len(p.Blocks)),
+		"sealingAtropos": hexutil.Bytes(p.Blocks[len(p.Blocks)-1].Atropos.Bytes()),
+		"events":         hexutil.Uint64(len(p.Events)),
+		"nextValidators": validators,
+		"rlp":            hexutil.Bytes(raw),
+	}, nil
+}
+
+// GetReceiptProof returns the proof of the block receipt by its index, which the light client verifies
+// against the block receipts root.
+func (s *PublicDAGChainAPI) GetReceiptProof(ctx context.Context, blockNr rpc.BlockNumber, index hexutil.Uint64) ([]hexutil.Bytes, error) {
+	receipts, err := s.b.GetReceiptsByNumber(ctx, blockNr)
+	if err != nil {
+		return nil, err
+	}
+	proof, err := light.ReceiptProof(receipts, uint(index))
+	if err != nil {
+		return nil, err
+	}
+	res := make([]hexutil.Bytes, len(proof))
+	for i, node := range proof {
+		res[i] = node
+	}
+	return res, nil
+}
+
+// RPCMarshalElection converts the election record to the RPC output.
+func RPCMarshalElection(r *election.Record) map[string]interface{} {
+	marshalRoots := func(roots []election.RootAndSlot) []map[string]interface{} {
+		res := make([]map[string]interface{}, len(roots))
+		for i, root := range roots {
+			res[i] = map[string]interface{}{
+				"id":        hexutil.Bytes(root.ID.Bytes()),
+				"frame":     hexutil.Uint64(root.Slot.Frame),
+				"validator": hexutil.Uint64(root.Slot.Validator),
+			}
+		}
+		return res
+	}
+	marshalRoot := func(root hash.Event) interface{} {
+		if root.IsZero() {
+			return nil
+		}
+		return hexutil.Bytes(root.Bytes())
+	}
+
+	votes := make([]map[string]interface{}, len(r.Votes))
+	for i, v := range r.Votes {
+		votes[i] = map[string]interface{}{
+			"voter":        hexutil.Bytes(v.Voter.Bytes()),
+			"subject":      hexutil.Uint64(v.Subject),
+			"yes":          v.Yes,
+			"decided":      v.Decided,
+			"observedRoot": marshalRoot(v.ObservedRoot),
+		}
+	}
+	decisions := make([]map[string]interface{}, len(r.Decisions))
+	for i, d := range r.Decisions {
+		decisions[i] = map[string]interface{}{
+			"subject": hexutil.Uint64(d.Subject),
+			"round":   hexutil.Uint64(d.Round),
+			"yes":     d.Yes,
+			"root":    marshalRoot(d.Root),
+		}
+	}
+
+	return map[string]interface{}{
+		"frame":      hexutil.Uint64(r.Frame),
+		"atropos":    hexutil.Bytes(r.Atropos.Bytes()),
+		"candidates": marshalRoots(r.Candidates),
+		"voters":     marshalRoots(r.Voters),
+		"votes":      votes,
+		"decisions":  decisions,
+	}
+}
+
+// RPCMarshalForkEvidence converts the fork evidence to the RPC output.
+func RPCMarshalForkEvidence(f *inter.ForkEvidence) (map[string]interface{}, error) {
+	raw, err := rlp.EncodeToBytes(f)
+	if err != nil {
+		return nil, err
+	}
+	marshalHeader := func(header *inter.EventHeader) map[string]interface{} {
+		fields := RPCMarshalEventHeader(&header.EventHeaderData)
+		fields["sig"] = hexutil.Bytes(header.Sig)
+		return fields
+	}
+	return map[string]interface{}{
+		"stakerID": hexutil.Uint64(f.Creator()),
+		"epoch":    hexutil.Uint64(f.Epoch),
+		"block":    hexutil.Uint64(f.Block),
+		"eventA":   marshalHeader(f.A),
+		"eventB":   marshalHeader(f.B),
+		"rlp":      hexutil.Bytes(raw),
+	}, nil
+}
+
 func durationToRPC(t time.Duration) string {
 	/*if t < 0 {
 		t = -t
@@ -0,0 +1,224 @@
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/Fantom-foundation/go-lachesis/evmcore"
+)
+
+const (
+	// defaultTraceTimeout is the amount of time a single transaction can execute
+	// by default before being forcefully aborted.
+	defaultTraceTimeout = 5 * time.Second
+)
+
+// TraceConfig holds extra parameters to trace functions.
+type TraceConfig struct {
+	*vm.LogConfig
+	Tracer  *string
+	Timeout *string
+}
+
+// txTraceResult is the result of a single transaction trace.
+type txTraceResult struct {
+	Result interface{} `json:"result,omitempty"` // Trace results produced by the tracer
+	Error  string      `json:"error,omitempty"`  // Trace failure produced by the tracer
+}
+
+// TraceTransaction returns the structured logs created during the execution of EVM
+// and returns them as a JSON object.
+func (api *PrivateDebugAPI) TraceTransaction(ctx context.Context, hash common.Hash, config *TraceConfig) (interface{}, error) {
+	tx, blockNumber, index, err := api.b.GetTransaction(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if tx == nil {
+		return nil, fmt.Errorf("transaction %#x not found", hash)
+	}
+	block, err := api.b.BlockByNumber(ctx, rpc.BlockNumber(blockNumber))
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block #%d not found", blockNumber)
+	}
+	if index >= uint64(len(block.Transactions)) || block.Transactions[index].Hash() != hash {
+		return nil, fmt.Errorf("transaction %#x isn't found in block #%d", hash, blockNumber)
+	}
+
+	results, err := api.traceBlock(ctx, block, config, int(index))
+	if err != nil {
+		return nil, err
+	}
+	if results[0].Error != "" {
+		return nil, errors.New(results[0].Error)
+	}
+	return results[0].Result, nil
+}
+
+// TraceBlockByNumber returns the structured logs created during the execution of
+// EVM and returns them as a JSON object.
+func (api *PrivateDebugAPI) TraceBlockByNumber(ctx context.Context, number rpc.BlockNumber, config *TraceConfig) ([]*txTraceResult, error) {
+	block, err := api.b.BlockByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block #%d not found", number)
+	}
+	return api.traceBlock(ctx, block, config, -1)
+}
+
+// TraceBlockByHash returns the structured logs created during the execution of
+// EVM and returns them as a JSON object.
+func (api *PrivateDebugAPI) TraceBlockByHash(ctx context.Context, hash common.Hash, config *TraceConfig) ([]*txTraceResult, error) {
+	block, err := api.b.GetBlock(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block %#x not found", hash)
+	}
+	return api.traceBlock(ctx, block, config, -1)
+}
+
+// traceBlock replays the block transactions on top of the parent state.
+// If target is negative, all the transactions are traced,
+// otherwise only the target transaction is traced and the replay stops after it.
+// Skipped transactions aren't replayed, because they are already filtered out of the block
+// in the same way as on the block processing.
+func (api *PrivateDebugAPI) traceBlock(ctx context.Context, block *evmcore.EvmBlock, config *TraceConfig, target int) ([]*txTraceResult, error) {
+	if block.NumberU64() == 0 {
+		return nil, errors.New("genesis is not traceable")
+	}
+	statedb, _, err := api.b.StateAndHeaderByNumber(ctx, rpc.BlockNumber(block.NumberU64()-1))
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		header      = block.Header()
+		chainConfig = api.b.BlockChainConfig(header)
+		chain       = &chainReader{ctx: ctx, b: api.b}
+		gp          = new(evmcore.GasPool).AddGas(block.GasLimit)
+		usedGas     = new(uint64)
+		results     = make([]*txTraceResult, 0, len(block.Transactions))
+	)
+	for i, tx := range block.Transactions {
+		if target >= 0 && i != target {
+			// not yet the target transaction, execute without tracing
+			statedb.Prepare(tx.Hash(), block.Hash, i)
+			_, _, _, skip, err := evmcore.ApplyTransaction(chainConfig, chain, nil, gp, statedb, header, tx, usedGas, vm.Config{}, false)
+			if skip || err != nil {
+				return nil, fmt.Errorf("transaction %#x failed: %v", tx.Hash(), err)
+			}
+			continue
+		}
+
+		tracer, stop, err := newTracer(ctx, config)
+		if err != nil {
+			return nil, err
+		}
+		statedb.Prepare(tx.Hash(), block.Hash, i)
+		receipt, gas, _, skip, err := evmcore.ApplyTransaction(chainConfig, chain, nil, gp, statedb, header, tx, usedGas, vm.Config{Debug: true, Tracer: tracer}, false)
+		stop()
+		if skip && err == nil {
+			err = errors.New("transaction is skipped")
+		}
+		if err != nil {
+			results = append(results, &txTraceResult{Error: fmt.Sprintf("tracing failed: %v", err)})
+		} else {
+			res, err := tracerResult(tracer, receipt.Status == 0, gas)
+			if err != nil {
+				results = append(results, &txTraceResult{Error: err.Error()})
+			} else {
+				results = append(results, &txTraceResult{Result: res})
+			}
+		}
+
+		if target >= 0 {
+			break
+		}
+	}
+	return results, nil
+}
+
+// newTracer constructs the structured logger or the JavaScript tracer according to the config.
+// The returned stop func releases the tracer's timeout.
+func newTracer(ctx context.Context, config *TraceConfig) (vm.Tracer, func(), error) {
+	if config == nil {
+		return vm.NewStructLogger(nil), func() {}, nil
+	}
+	if config.Tracer == nil {
+		return vm.NewStructLogger(config.LogConfig), func() {}, nil
+	}
+
+	// Define a meaningful timeout of a single transaction trace
+	timeout := defaultTraceTimeout
+	if config.Timeout != nil {
+		var err error
+		if timeout, err = time.ParseDuration(*config.Timeout); err != nil {
+			return nil, nil, err
+		}
+	}
+	// Construct the JavaScript tracer to execute with, it may be a name of the built-in one
+	tracer, err := tracers.New(*config.Tracer)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Handle timeouts and RPC cancellations
+	deadlineCtx, cancel := context.WithTimeout(ctx, timeout)
+	go func() {
+		<-deadlineCtx.Done()
+		if deadlineCtx.Err() == context.DeadlineExceeded {
+			tracer.Stop(errors.New("execution timeout"))
+		}
+	}()
+	return tracer, cancel, nil
+}
+
+// tracerResult formats the output of the tracer.
+func tracerResult(tracer vm.Tracer, failed bool, gas uint64) (interface{}, error) {
+	switch tracer := tracer.(type) {
+	case *vm.StructLogger:
+		return &ExecutionResult{
+			Gas:         gas,
+			Failed:      failed,
+			ReturnValue: fmt.Sprintf("%x", tracer.Output()),
+			StructLogs:  FormatLogs(tracer.StructLogs()),
+		}, nil
+
+	case *tracers.Tracer:
+		return tracer.GetResult()
+
+	default:
+		panic(fmt.Sprintf("bad tracer type %T", tracer))
+	}
+}
+
+// chainReader provides the block headers for the BLOCKHASH opcode.
+type chainReader struct {
+	ctx context.Context
+	b   Backend
+}
+
+// GetHeader returns the block header by its number, if the hash matches.
+func (r *chainReader) GetHeader(h common.Hash, n uint64) *evmcore.EvmHeader {
+	if rpc.BlockNumber(n) < 0 {
+		// out of range, don't confuse with the special block numbers
+		return nil
+	}
+	header, err := r.b.HeaderByNumber(r.ctx, rpc.BlockNumber(n))
+	if err != nil || header == nil || header.Hash != h {
+		return nil
+	}
+	return header
+}
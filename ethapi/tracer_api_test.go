@@ -0,0 +1,172 @@
+package ethapi
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/evmcore"
+	"github.com/Fantom-foundation/go-lachesis/logger"
+)
+
+// tracerTestBackend serves a genesis state and a single block of transactions.
+type tracerTestBackend struct {
+	Backend
+
+	db     state.Database
+	root   common.Hash
+	blocks []*evmcore.EvmBlock
+}
+
+func (b *tracerTestBackend) ChainConfig() *params.ChainConfig {
+	return params.AllEthashProtocolChanges
+}
+
+func (b *tracerTestBackend) BlockChainConfig(header *evmcore.EvmHeader) *params.ChainConfig {
+	return b.ChainConfig()
+}
+
+func (b *tracerTestBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*evmcore.EvmBlock, error) {
+	if number == rpc.LatestBlockNumber {
+		number = rpc.BlockNumber(len(b.blocks) - 1)
+	}
+	if number < 0 || int(number) >= len(b.blocks) {
+		return nil, nil
+	}
+	return b.blocks[number], nil
+}
+
+func (b *tracerTestBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*evmcore.EvmHeader, error) {
+	block, err := b.BlockByNumber(ctx, number)
+	return block.Header(), err
+}
+
+func (b *tracerTestBackend) StateAndHeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*state.StateDB, *evmcore.EvmHeader, error) {
+	header, _ := b.HeaderByNumber(ctx, number)
+	statedb, err := state.New(header.Root, b.db)
+	return statedb, header, err
+}
+
+func (b *tracerTestBackend) GetTransaction(ctx context.Context, txHash common.Hash) (*types.Transaction, uint64, uint64, error) {
+	for n, block := range b.blocks {
+		for i, tx := range block.Transactions {
+			if tx.Hash() == txHash {
+				return tx, uint64(n), uint64(i), nil
+			}
+		}
+	}
+	return nil, 0, 0, nil
+}
+
+func TestTraceBlock(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	key, err := crypto.GenerateKey()
+	if !assertar.NoError(err) {
+		return
+	}
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	to := common.HexToAddress("0x1000")
+
+	b := &tracerTestBackend{
+		db: state.NewDatabase(rawdb.NewMemoryDatabase()),
+	}
+	statedb, _ := state.New(common.Hash{}, b.db)
+	statedb.SetBalance(from, big.NewInt(1e18))
+	// PUSH1 0x2a PUSH1 0 MSTORE PUSH1 32 PUSH1 0 RETURN
+	statedb.SetCode(to, common.FromHex("0x602a60005260206000f3"))
+	b.root, err = statedb.Commit(true)
+	if !assertar.NoError(err) {
+		return
+	}
+
+	signer := types.NewEIP155Signer(b.ChainConfig().ChainID)
+	txs := types.Transactions{}
+	for i := uint64(0); i < 2; i++ {
+		tx, err := types.SignTx(types.NewTransaction(i, to, big.NewInt(1), 100000, big.NewInt(1), nil), signer, key)
+		if !assertar.NoError(err) {
+			return
+		}
+		txs = append(txs, tx)
+	}
+
+	genesis := &evmcore.EvmBlock{
+		EvmHeader: evmcore.EvmHeader{
+			Number:   big.NewInt(0),
+			Hash:     common.HexToHash("0x01"),
+			Root:     b.root,
+			GasLimit: 1e9,
+		},
+	}
+	block := &evmcore.EvmBlock{
+		EvmHeader: evmcore.EvmHeader{
+			Number:     big.NewInt(1),
+			Hash:       common.HexToHash("0x02"),
+			ParentHash: genesis.Hash,
+			GasLimit:   1e9,
+		},
+		Transactions: txs,
+	}
+	b.blocks = []*evmcore.EvmBlock{genesis, block}
+
+	api := NewPrivateDebugAPI(b)
+	ctx := context.Background()
+
+	_, err = api.TraceBlockByNumber(ctx, 0, nil)
+	assertar.Error(err)
+	_, err = api.TraceBlockByNumber(ctx, 2, nil)
+	assertar.Error(err)
+
+	// struct logger
+	results, err := api.TraceBlockByNumber(ctx, 1, nil)
+	if !assertar.NoError(err) || !assertar.Len(results, len(txs)) {
+		return
+	}
+	for _, res := range results {
+		if !assertar.Empty(res.Error) {
+			return
+		}
+		exec := res.Result.(*ExecutionResult)
+		assertar.False(exec.Failed)
+		assertar.Equal(common.BigToHash(big.NewInt(42)).Hex()[2:], exec.ReturnValue)
+		assertar.Len(exec.StructLogs, 6)
+	}
+
+	// call tracer
+	tracer := "callTracer"
+	res, err := api.TraceTransaction(ctx, txs[1].Hash(), &TraceConfig{Tracer: &tracer})
+	if !assertar.NoError(err) {
+		return
+	}
+	var call struct {
+		From   common.Address
+		To     common.Address
+		Output string
+	}
+	if !assertar.NoError(json.Unmarshal(res.(json.RawMessage), &call)) {
+		return
+	}
+	assertar.Equal(from, call.From)
+	assertar.Equal(to, call.To)
+	assertar.Equal(common.BigToHash(big.NewInt(42)).Hex(), call.Output)
+
+	_, err = api.TraceTransaction(ctx, common.Hash{}, nil)
+	assertar.Error(err)
+
+	// the nonce is too high if the previous transaction isn't replayed
+	res, err = api.TraceTransaction(ctx, txs[1].Hash(), nil)
+	if assertar.NoError(err) {
+		assertar.False(res.(*ExecutionResult).Failed)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/Fantom-foundation/go-lachesis/eventcheck/gaspowercheck"
 	"github.com/Fantom-foundation/go-lachesis/eventcheck/heavycheck"
 	"github.com/Fantom-foundation/go-lachesis/eventcheck/parentscheck"
+	"github.com/Fantom-foundation/go-lachesis/eventcheck/timecheck"
 	"github.com/Fantom-foundation/go-lachesis/inter"
 )
 
@@ -16,11 +17,25 @@ type Checkers struct {
 	Parentscheck  *parentscheck.Checker
 	Gaspowercheck *gaspowercheck.Checker
 	Heavycheck    *heavycheck.Checker
+	Timecheck     *timecheck.Checker
 }
 
-// Validate runs all the checks except Poset-related. intended only for tests
+// Validate runs all the checks except Poset-related and the local clock related. intended only for tests
 func (v *Checkers) Validate(e *inter.Event, parents []*inter.EventHeaderData) error {
-	if err := v.Basiccheck.Validate(e); err != nil {
+	return v.validate(e, parents, true)
+}
+
+// ValidateUnsigned runs the same checks as Validate, except the event signature, e.g. before the event is signed.
+func (v *Checkers) ValidateUnsigned(e *inter.Event, parents []*inter.EventHeaderData) error {
+	return v.validate(e, parents, false)
+}
+
+func (v *Checkers) validate(e *inter.Event, parents []*inter.EventHeaderData, signed bool) error {
+	basicValidate, heavyValidate := v.Basiccheck.Validate, v.Heavycheck.Validate
+	if !signed {
+		basicValidate, heavyValidate = v.Basiccheck.ValidateUnsigned, v.Heavycheck.ValidateUnsigned
+	}
+	if err := basicValidate(e); err != nil {
 		return err
 	}
 	if err := v.Epochcheck.Validate(e); err != nil {
@@ -36,8 +51,5 @@ func (v *Checkers) Validate(e *inter.Event, parents []*inter.EventHeaderData) er
 	if err := v.Gaspowercheck.Validate(e, selfParent); err != nil {
 		return err
 	}
-	if err := v.Heavycheck.Validate(e); err != nil {
-		return err
-	}
-	return nil
+	return heavyValidate(e)
 }
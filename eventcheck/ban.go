@@ -4,6 +4,7 @@ import (
 	"errors"
 
 	"github.com/Fantom-foundation/go-lachesis/eventcheck/epochcheck"
+	"github.com/Fantom-foundation/go-lachesis/eventcheck/timecheck"
 )
 
 var (
@@ -12,6 +13,7 @@ var (
 
 func IsBan(err error) bool {
 	if err == epochcheck.ErrNotRelevant ||
+		err == timecheck.ErrTooEarly ||
 		err == ErrAlreadyConnectedEvent {
 		return false
 	}
@@ -29,11 +29,12 @@ var (
 )
 
 type Checker struct {
-	config *lachesis.DagConfig
+	config *lachesis.Config
 }
 
-// New validator which performs checks which don't require anything except event
-func New(config *lachesis.DagConfig) *Checker {
+// New validator which performs checks which don't require anything except event.
+// The DAG rules of the event's epoch are applied.
+func New(config *lachesis.Config) *Checker {
 	return &Checker{
 		config: config,
 	}
@@ -85,22 +86,22 @@ func CalcGasPowerUsed(e *inter.Event, config *lachesis.DagConfig) uint64 {
 	return txsGas + parentsGas + extraGas + params.EventGas
 }
 
-func (v *Checker) checkGas(e *inter.Event) error {
+func (v *Checker) checkGas(e *inter.Event, dag *lachesis.DagConfig) error {
 	if e.GasPowerUsed > params.MaxGasPowerUsed {
 		return ErrTooBigGasUsed
 	}
-	if e.GasPowerUsed != CalcGasPowerUsed(e, v.config) {
+	if e.GasPowerUsed != CalcGasPowerUsed(e, dag) {
 		return ErrWrongGasUsed
 	}
 
 	return nil
 }
 
-func (v *Checker) checkLimits(e *inter.Event) error {
+func (v *Checker) checkLimits(e *inter.Event, dag *lachesis.DagConfig) error {
 	if len(e.Extra) > params.MaxExtraData {
 		return ErrExtraTooLarge
 	}
-	if len(e.Parents) > v.config.MaxParents {
+	if len(e.Parents) > dag.MaxParents {
 		return ErrTooManyParents
 	}
 	if e.Seq >= math.MaxInt32/2 || e.Epoch >= math.MaxInt32/2 || e.Frame >= math.MaxInt32/2 ||
@@ -111,7 +112,7 @@ func (v *Checker) checkLimits(e *inter.Event) error {
 	return nil
 }
 
-func (v *Checker) checkInited(e *inter.Event) error {
+func (v *Checker) checkInited(e *inter.Event, signed bool) error {
 	if e.Seq <= 0 || e.Epoch <= 0 || e.Frame <= 0 || e.Lamport <= 0 {
 		return ErrNotInited // it's unsigned, but check for negative in a case if type will change
 	}
@@ -122,7 +123,7 @@ func (v *Checker) checkInited(e *inter.Event) error {
 	if e.Seq > 1 && len(e.Parents) == 0 {
 		return ErrNoParents
 	}
-	if len(e.Sig) != 65 {
+	if signed && len(e.Sig) != 65 {
 		return ErrSigMalformed
 	}
 
@@ -131,16 +132,26 @@ func (v *Checker) checkInited(e *inter.Event) error {
 
 // Validate event
 func (v *Checker) Validate(e *inter.Event) error {
+	return v.validate(e, true)
+}
+
+// ValidateUnsigned runs all the checks except the signature format, e.g. before the event is signed.
+func (v *Checker) ValidateUnsigned(e *inter.Event) error {
+	return v.validate(e, false)
+}
+
+func (v *Checker) validate(e *inter.Event, signed bool) error {
 	if e.Version != 0 {
 		return ErrVersion
 	}
-	if err := v.checkLimits(e); err != nil {
+	rules := v.config.Rules(e.Epoch)
+	if err := v.checkLimits(e, &rules.Dag); err != nil {
 		return err
 	}
-	if err := v.checkInited(e); err != nil {
+	if err := v.checkInited(e, signed); err != nil {
 		return err
 	}
-	if err := v.checkGas(e); err != nil {
+	if err := v.checkGas(e, &rules.Dag); err != nil {
 		return err
 	}
 	if err := v.checkTxs(e); err != nil {
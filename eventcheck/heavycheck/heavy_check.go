@@ -116,8 +116,33 @@ func (v *Checker) Enqueue(events inter.Events, onValidated OnValidatedFn) error
 	return nil
 }
 
+// ValidateForkEvidence checks the fork evidence of the current epoch.
+func (v *Checker) ValidateForkEvidence(f *inter.ForkEvidence) error {
+	addrs, epoch := v.reader.GetEpochPubKeys()
+	if f.Epoch != epoch {
+		return epochcheck.ErrNotRelevant
+	}
+	if f.A == nil || f.B == nil {
+		return inter.ErrForkEvidenceNotFork
+	}
+	addr, ok := addrs[f.Creator()]
+	if !ok {
+		return epochcheck.ErrAuth
+	}
+	return f.Verify(addr)
+}
+
 // Validate event
 func (v *Checker) Validate(e *inter.Event) error {
+	return v.validate(e, true)
+}
+
+// ValidateUnsigned runs all the checks except the event signature, e.g. before the event is signed.
+func (v *Checker) ValidateUnsigned(e *inter.Event) error {
+	return v.validate(e, false)
+}
+
+func (v *Checker) validate(e *inter.Event, signed bool) error {
 	addrs, epoch := v.reader.GetEpochPubKeys()
 	if e.Epoch != epoch {
 		return epochcheck.ErrNotRelevant
@@ -128,7 +153,7 @@ func (v *Checker) Validate(e *inter.Event) error {
 		return epochcheck.ErrAuth
 	}
 	// event sig
-	if !e.VerifySignature(addr) {
+	if signed && !e.VerifySignature(addr) {
 		return ErrWrongEventSig
 	}
 	// pre-cache tx sig
@@ -0,0 +1,73 @@
+package timecheck
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Fantom-foundation/go-lachesis/inter"
+)
+
+var (
+	// ErrFutureTime indicates the event's claimed time is too far in the future, the event is rejected.
+	ErrFutureTime = errors.New("event has claimed time too far in the future")
+	// ErrTooEarly indicates the event's claimed time is in the future, the event may be accepted after a delay.
+	ErrTooEarly = errors.New("event has claimed time in the future")
+)
+
+// Config of the claimed time check.
+// Unlike other checks, the check depends on the local clock, so it's a config of the node rather than of the network.
+// The claimed time lower than the self-parent's one is rejected by parentscheck.
+type Config struct {
+	// MaxFutureDrift is the max difference by which the claimed time may be ahead of the local time.
+	MaxFutureDrift time.Duration
+	// MaxDelay is the max time an event ahead of MaxFutureDrift is delayed for, more distant events are rejected.
+	MaxDelay time.Duration
+}
+
+// DefaultConfig returns the default claimed time check config.
+func DefaultConfig() Config {
+	return Config{
+		MaxFutureDrift: 5 * time.Second,
+		MaxDelay:       time.Minute,
+	}
+}
+
+// Checker which requires only the local clock
+type Checker struct {
+	config *Config
+	now    func() time.Time
+}
+
+// New validator which checks the claimed time with the now clock
+func New(config *Config, now func() time.Time) *Checker {
+	return &Checker{
+		config: config,
+		now:    now,
+	}
+}
+
+// Drift returns the difference between the local time and the claimed time, it's negative for the future events.
+func (v *Checker) Drift(e *inter.Event) time.Duration {
+	return v.now().Sub(e.ClaimedTime.Time())
+}
+
+// Delay returns the time after which the event won't be too early.
+func (v *Checker) Delay(e *inter.Event) time.Duration {
+	delay := -v.Drift(e) - v.config.MaxFutureDrift
+	if delay < 0 {
+		return 0
+	}
+	return delay
+}
+
+// Validate event
+func (v *Checker) Validate(e *inter.Event) error {
+	delay := v.Delay(e)
+	if delay > v.config.MaxDelay {
+		return ErrFutureTime
+	}
+	if delay > 0 {
+		return ErrTooEarly
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package timecheck
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/inter"
+)
+
+func TestChecker(t *testing.T) {
+	assertar := assert.New(t)
+
+	now := time.Unix(1000, 0)
+	config := Config{
+		MaxFutureDrift: 5 * time.Second,
+		MaxDelay:       time.Minute,
+	}
+	checker := New(&config, func() time.Time {
+		return now
+	})
+
+	event := func(claimed time.Time) *inter.Event {
+		e := inter.NewEvent()
+		e.ClaimedTime = inter.Timestamp(claimed.UnixNano())
+		return e
+	}
+
+	for _, it := range []struct {
+		claimed time.Time
+		drift   time.Duration
+		delay   time.Duration
+		err     error
+	}{
+		{now.Add(-time.Hour), time.Hour, 0, nil},
+		{now, 0, 0, nil},
+		{now.Add(5 * time.Second), -5 * time.Second, 0, nil},
+		{now.Add(6 * time.Second), -6 * time.Second, time.Second, ErrTooEarly},
+		{now.Add(65 * time.Second), -65 * time.Second, time.Minute, ErrTooEarly},
+		{now.Add(66 * time.Second), -66 * time.Second, 61 * time.Second, ErrFutureTime},
+	} {
+		e := event(it.claimed)
+		assertar.Equal(it.drift, checker.Drift(e))
+		assertar.Equal(it.delay, checker.Delay(e))
+		assertar.Equal(it.err, checker.Validate(e))
+	}
+}
@@ -79,6 +79,9 @@ var (
 	// than some meaningful limit a user might use. This is not a consensus error
 	// making the transaction invalid, rather a DOS protection.
 	ErrOversizedData = errors.New("oversized data")
+
+	// ErrChainIDChanged is returned if the chain config is switched to another chain ID.
+	ErrChainIDChanged = errors.New("chain ID can't be changed")
 )
 
 var (
@@ -289,7 +292,8 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain state
 		pool.locals.add(addr)
 	}
 	pool.priced = newTxPricedList(pool.all)
-	pool.reset(nil, chain.CurrentBlock().Header())
+	head := chain.CurrentBlock()
+	pool.reset(nil, head.Header())
 
 	// Start the reorg loop early so it can handle requests generated during journal loading.
 	pool.wg.Add(1)
@@ -310,7 +314,7 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain state
 	// Subscribe events from blockchain and start the main event loop.
 	pool.chainHeadSub = pool.chain.SubscribeNewBlock(pool.chainHeadCh)
 	pool.wg.Add(1)
-	go pool.loop()
+	go pool.loop(head)
 
 	return pool
 }
@@ -318,7 +322,8 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain state
 // loop is the transaction pool's main event loop, waiting for and reacting to
 // outside blockchain events as well as for various reporting and transaction
 // eviction events.
-func (pool *TxPool) loop() {
+// The head is read by the caller, because the chain may be locked by the head notifier, which waits for this loop.
+func (pool *TxPool) loop(head *EvmBlock) {
 	defer pool.wg.Done()
 
 	var (
@@ -327,8 +332,6 @@ func (pool *TxPool) loop() {
 		report  = time.NewTicker(statsReportInterval)
 		evict   = time.NewTicker(evictionInterval)
 		journal = time.NewTicker(pool.config.Rejournal)
-		// Track the previous head headers for transaction reorgs
-		head = pool.chain.CurrentBlock()
 	)
 	defer report.Stop()
 	defer evict.Stop()
@@ -405,6 +408,27 @@ func (pool *TxPool) Stop() {
 	log.Info("Transaction pool stopped")
 }
 
+// ChainConfig returns the chain config, which the transactions are validated with.
+func (pool *TxPool) ChainConfig() *params.ChainConfig {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	return pool.chainconfig
+}
+
+// SetChainConfig switches the chain config, e.g. to the rules of a new epoch.
+// The signer depends only on the chain ID, which mustn't be changed, so the signer is kept.
+func (pool *TxPool) SetChainConfig(chainconfig *params.ChainConfig) error {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	if chainconfig.ChainID.Cmp(pool.chainconfig.ChainID) != 0 {
+		return ErrChainIDChanged
+	}
+	pool.chainconfig = chainconfig
+	return nil
+}
+
 // SubscribeNewTxsNotify registers a subscription of NewTxsNotify and
 // starts sending event to the given channel.
 func (pool *TxPool) SubscribeNewTxsNotify(ch chan<- NewTxsNotify) notify.Subscription {
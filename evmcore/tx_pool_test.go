@@ -254,6 +254,53 @@ func TestStateChangeDuringTransactionPoolReset(t *testing.T) {
 	}
 }
 
+// testLockingChain locks the chain right after the subscription, like the consensus engine
+// which processes an event right after the node is created, and notifies about the new blocks.
+type testLockingChain struct {
+	*testBlockChain
+}
+
+func (c *testLockingChain) SubscribeNewBlock(ch chan<- ChainHeadNotify) notify.Subscription {
+	sub := c.testBlockChain.SubscribeNewBlock(ch)
+	c.Lock()
+	return sub
+}
+
+// This test checks that the pool doesn't read the head from the chain which is locked
+// by the head notifier, because the notifier waits for the pool to receive the heads.
+func TestChainHeadNotifyDuringPoolCreation(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	blockchain := &testLockingChain{&testBlockChain{
+		statedb:       statedb,
+		gasLimit:      1000000,
+		chainHeadFeed: new(notify.Feed),
+	}}
+
+	pool := NewTxPool(testTxPoolConfig, params.TestChainConfig, blockchain)
+	defer pool.Stop()
+	defer blockchain.Unlock()
+
+	notified := make(chan struct{})
+	go func() {
+		defer close(notified)
+		// more heads than the pool channel may buffer
+		for i := 1; i <= chainHeadChanSize+1; i++ {
+			block := &EvmBlock{}
+			block.Number = big.NewInt(int64(i))
+			block.GasLimit = blockchain.gasLimit
+			blockchain.chainHeadFeed.Send(ChainHeadNotify{block})
+		}
+	}()
+
+	select {
+	case <-notified:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the pool doesn't receive the new heads while the chain is locked")
+	}
+}
+
 func TestInvalidTransactions(t *testing.T) {
 	t.Parallel()
 
@@ -366,6 +413,32 @@ func TestTransactionNegativeValue(t *testing.T) {
 	}
 }
 
+// Tests that the chain config may be switched, but not to another chain ID.
+func TestTransactionSetChainConfig(t *testing.T) {
+	t.Parallel()
+
+	pool := setupTxPool()
+	defer pool.Stop()
+
+	upgraded := *params.TestChainConfig
+	upgraded.IstanbulBlock = nil
+	if err := pool.SetChainConfig(&upgraded); err != nil {
+		t.Fatalf("failed to switch chain config: %v", err)
+	}
+	if pool.ChainConfig() != &upgraded {
+		t.Error("chain config isn't switched")
+	}
+
+	forked := upgraded
+	forked.ChainID = new(big.Int).Add(upgraded.ChainID, common.Big1)
+	if err := pool.SetChainConfig(&forked); err != ErrChainIDChanged {
+		t.Error("expected", ErrChainIDChanged, "got", err)
+	}
+	if pool.ChainConfig() != &upgraded {
+		t.Error("chain config is switched to another chain ID")
+	}
+}
+
 func TestTransactionChainFork(t *testing.T) {
 	t.Parallel()
 
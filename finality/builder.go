@@ -0,0 +1,78 @@
+package finality
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/inter/pos"
+)
+
+// Builder builds the finality proof of the block from the events of its epoch.
+type Builder struct {
+	proof  Proof
+	replay *replay
+}
+
+// NewBuilder returns the builder of the block proof.
+func NewBuilder(epoch idx.Epoch, block, epochStart idx.Block, validators *pos.Validators, addresses []common.Address, evictCheaters bool) (*Builder, error) {
+	frame := idx.Frame(block - epochStart)
+	if block <= epochStart || idx.Block(frame) != block-epochStart {
+		return nil, ErrMalformed
+	}
+	r, err := newReplay(epoch, frame, validators, addresses, evictCheaters)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Builder{
+		proof: Proof{
+			Block:         block,
+			EpochStart:    epochStart,
+			Validators:    validators,
+			Addresses:     addresses,
+			EvictCheaters: evictCheaters,
+		},
+		replay: r,
+	}, nil
+}
+
+// Add adds the next event of the epoch, the events are added in topological order.
+// Returns true once the block frame is decided, the next events aren't needed then.
+func (b *Builder) Add(e *inter.EventHeader) (decided bool, err error) {
+	if b.replay.decided != nil {
+		return true, nil
+	}
+	err = b.replay.add(e)
+	return b.replay.decided != nil, err
+}
+
+// Proof returns the proof with the events which are observed by the roots which have voted in the election.
+func (b *Builder) Proof() (*Proof, error) {
+	if b.replay.decided == nil {
+		return nil, ErrNotDecided
+	}
+
+	needed := make(map[hash.Event]bool, len(b.replay.order))
+	queue := b.replay.voters.Copy()
+	for len(queue) != 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if needed[id] {
+			continue
+		}
+		needed[id] = true
+		queue = append(queue, b.replay.headers[id].Parents...)
+	}
+
+	proof := b.proof
+	proof.Atropos = b.replay.decided.Atropos
+	proof.Events = make([]*inter.EventHeader, 0, len(needed))
+	for _, id := range b.replay.order {
+		if needed[id] {
+			proof.Events = append(proof.Events, b.replay.headers[id])
+		}
+	}
+	return &proof, nil
+}
@@ -0,0 +1,75 @@
+// Package finality verifies the proofs of blocks finality without the full DAG.
+package finality
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/inter/pos"
+)
+
+var (
+	ErrMalformed  = errors.New("finality proof is malformed")
+	ErrNotDecided = errors.New("block frame isn't decided by the proof events")
+	ErrNotElected = errors.New("another root is elected as the block Atropos")
+)
+
+// Proof is a self-contained proof that the block is final: the Atropos of the block frame is elected
+// by the roots of the next frames, the same as by the consensus.
+//
+// Events contain the epoch events from the epoch start up to the root which decides the election,
+// along with all their parents. So the frames of the events and the election are recalculated
+// with the signed events headers only.
+type Proof struct {
+	Block idx.Block
+	// EpochStart is the last block of the previous epoch.
+	EpochStart idx.Block
+	Atropos    hash.Event
+
+	// Events are the signed headers in topological order.
+	Events []*inter.EventHeader
+
+	// Validators of the epoch, Addresses are their events signers in the order of Validators.SortedIDs().
+	Validators *pos.Validators
+	Addresses  []common.Address
+	// EvictCheaters is the vector clock rule of the epoch, see vector.IndexConfig.
+	EvictCheaters bool
+}
+
+// Verify checks the proof with its validators.
+// The caller must make sure that the proof's validators and EvictCheaters are the ones of the Atropos epoch,
+// e.g. compare them with the ones it trusts, and that EpochStart is the last block of the previous epoch,
+// e.g. with the light client checkpoint of the epoch.
+//
+// Every frame of the epoch is decided into one block, so the block frame is Block-EpochStart
+// and the block Atropos is the root elected for the frame. The frames, the roots and the election are checked
+// by the consensus rules, so the check is not weaker than the consensus one.
+func (p *Proof) Verify() error {
+	frame := idx.Frame(p.Block - p.EpochStart)
+	if p.Block <= p.EpochStart || idx.Block(frame) != p.Block-p.EpochStart {
+		return ErrMalformed
+	}
+	r, err := newReplay(p.Atropos.Epoch(), frame, p.Validators, p.Addresses, p.EvictCheaters)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range p.Events {
+		err = r.add(e)
+		if err != nil {
+			return err
+		}
+	}
+
+	if r.decided == nil {
+		return ErrNotDecided
+	}
+	if r.decided.Atropos != p.Atropos {
+		return ErrNotElected
+	}
+	return nil
+}
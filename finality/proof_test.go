@@ -0,0 +1,157 @@
+package finality
+
+import (
+	"crypto/ecdsa"
+	"math/rand"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/crypto"
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/inter/pos"
+	"github.com/Fantom-foundation/go-lachesis/logger"
+)
+
+func TestProofVerify(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	nodes := []idx.StakerID{1, 2, 3, 4}
+	validators := pos.EqualStakeValidators(nodes, 1)
+	keys := make(map[idx.StakerID]*ecdsa.PrivateKey)
+	addresses := make([]common.Address, 0, validators.Len())
+	for _, id := range validators.SortedIDs() {
+		keys[id] = crypto.FakeKey(int(id))
+		addresses = append(addresses, ethcrypto.PubkeyToAddress(keys[id].PublicKey))
+	}
+	sign := func(e *inter.Event) {
+		if !assertar.NoError(e.SignBy(keys[e.Creator])) {
+			t.FailNow()
+		}
+		e.RecacheHash()
+	}
+
+	// the proof of the frame 2 block
+	builder, err := NewBuilder(1, 12, 10, validators, addresses, false)
+	if !assertar.NoError(err) {
+		return
+	}
+
+	// the events get the frames which the builder accepts, the same as the poset calculates them
+	frames := make(map[hash.Event]idx.Frame)
+	decided := false
+	inter.ForEachRandEvent(nodes, 50, 3, rand.New(rand.NewSource(0)), inter.ForEachEvent{
+		Build: func(e *inter.Event, name string) *inter.Event {
+			if decided {
+				return nil
+			}
+			e.Epoch = 1
+			e.ClaimedTime = inter.Timestamp(e.Lamport)
+			e.Frame = 1
+			if e.SelfParent() != nil {
+				e.Frame = frames[*e.SelfParent()] + 1
+			}
+			e.IsRoot = true
+			sign(e)
+			decided, err = builder.Add(&e.EventHeader)
+			if err != nil {
+				e.Frame--
+				e.IsRoot = false
+				sign(e)
+				decided, err = builder.Add(&e.EventHeader)
+			}
+			if !assertar.NoError(err) {
+				t.FailNow()
+			}
+			frames[e.Hash()] = e.Frame
+			return e
+		},
+	})
+	if !assertar.True(decided) {
+		return
+	}
+
+	p, err := builder.Proof()
+	if !assertar.NoError(err) {
+		return
+	}
+	assertar.NoError(p.Verify())
+	assertar.Equal(idx.Frame(2), frames[p.Atropos])
+
+	// RLP round trip
+	raw, err := rlp.EncodeToBytes(p)
+	if !assertar.NoError(err) {
+		return
+	}
+	decoded := &Proof{}
+	if !assertar.NoError(rlp.DecodeBytes(raw, decoded)) {
+		return
+	}
+	assertar.NoError(decoded.Verify())
+	assertar.Equal(p.Atropos, decoded.Atropos)
+
+	cp := func() *Proof {
+		c := *p
+		c.Events = append([]*inter.EventHeader{}, p.Events...)
+		c.Addresses = append([]common.Address{}, p.Addresses...)
+		return &c
+	}
+
+	// without the deciding root
+	c := cp()
+	c.Events = c.Events[:len(c.Events)-1]
+	assertar.Equal(ErrNotDecided, c.Verify())
+
+	// another root of the frame isn't the Atropos
+	for _, e := range p.Events {
+		if e.IsRoot && e.Frame == 2 && e.CalcHash() != p.Atropos {
+			c = cp()
+			c.Atropos = e.CalcHash()
+			assertar.Equal(ErrNotElected, c.Verify())
+			break
+		}
+	}
+
+	// the Atropos isn't of the block frame
+	c = cp()
+	c.EpochStart = 11
+	assertar.Error(c.Verify())
+	c.EpochStart = 12
+	assertar.Equal(ErrMalformed, c.Verify())
+
+	// a missing parent
+	c = cp()
+	c.Events = append(c.Events[:1], c.Events[2:]...)
+	assertar.Error(c.Verify())
+
+	// not topological order
+	c = cp()
+	c.Events[0], c.Events[len(c.Events)-1] = c.Events[len(c.Events)-1], c.Events[0]
+	assertar.Error(c.Verify())
+
+	// wrong signature
+	c = cp()
+	c.Events[1] = &inter.EventHeader{EventHeaderData: c.Events[1].EventHeaderData, Sig: c.Events[2].Sig}
+	assertar.Error(c.Verify())
+
+	// a wrong frame of the deciding root
+	c = cp()
+	last := &inter.Event{EventHeader: inter.EventHeader{EventHeaderData: c.Events[len(c.Events)-1].EventHeaderData}}
+	last.IsRoot = false
+	sign(last)
+	c.Events[len(c.Events)-1] = &last.EventHeader
+	assertar.Error(c.Verify())
+
+	// other validators
+	c = cp()
+	c.Addresses[0] = common.Address{1}
+	assertar.Error(c.Verify())
+	c.Addresses = addresses[:3]
+	assertar.Equal(ErrMalformed, c.Verify())
+}
@@ -0,0 +1,167 @@
+package finality
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/Fantom-foundation/go-lachesis/eventcheck/parentscheck"
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/inter/pos"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/memorydb"
+	"github.com/Fantom-foundation/go-lachesis/poset/election"
+	"github.com/Fantom-foundation/go-lachesis/vector"
+)
+
+// replay recalculates the frames of the epoch events and the election of the frame, the same as the poset does.
+// The events must be added in topological order, all the parents first.
+type replay struct {
+	epoch     idx.Epoch
+	frame     idx.Frame
+	addresses map[idx.StakerID]common.Address
+
+	headers  map[hash.Event]*inter.EventHeader
+	order    hash.Events
+	roots    map[idx.Frame][]election.RootAndSlot
+	vecClock *vector.Index
+	election *election.Election
+
+	// voters are the roots which have voted until the election is decided
+	voters  hash.Events
+	decided *election.Res
+}
+
+func newReplay(epoch idx.Epoch, frame idx.Frame, validators *pos.Validators, addresses []common.Address, evictCheaters bool) (*replay, error) {
+	if frame == 0 || validators == nil || len(addresses) != validators.Len() {
+		return nil, ErrMalformed
+	}
+
+	r := &replay{
+		epoch:     epoch,
+		frame:     frame,
+		addresses: make(map[idx.StakerID]common.Address, len(addresses)),
+		headers:   make(map[hash.Event]*inter.EventHeader),
+		roots:     make(map[idx.Frame][]election.RootAndSlot),
+	}
+	for i, id := range validators.SortedIDs() {
+		r.addresses[id] = addresses[i]
+	}
+
+	config := vector.DefaultIndexConfig()
+	config.EvictCheaters = evictCheaters
+	r.vecClock = vector.NewIndex(config, validators, memorydb.New(), r.getHeader)
+	r.election = election.New(validators, frame, r.vecClock.ForklessCause, r.getFrameRoots, r.vecClock.NewQuorumCounter)
+
+	return r, nil
+}
+
+func (r *replay) getHeader(id hash.Event) *inter.EventHeaderData {
+	e := r.headers[id]
+	if e == nil {
+		return nil
+	}
+	return &e.EventHeaderData
+}
+
+func (r *replay) getFrameRoots(f idx.Frame) []election.RootAndSlot {
+	return r.roots[f]
+}
+
+// add checks the signature, the parents and the frame of the event, and votes by it if it's a root.
+func (r *replay) add(e *inter.EventHeader) error {
+	id := e.CalcHash()
+	if _, ok := r.headers[id]; ok {
+		return fmt.Errorf("%v: event %s is duplicated", ErrMalformed, id.String())
+	}
+	if e.Epoch != r.epoch {
+		return fmt.Errorf("%v: event %s isn't of epoch %d", ErrMalformed, id.String(), r.epoch)
+	}
+	address, ok := r.addresses[e.Creator]
+	if !ok {
+		return fmt.Errorf("%v: creator of event %s isn't a validator", ErrMalformed, id.String())
+	}
+	if !e.VerifySignature(address) {
+		return fmt.Errorf("%v: event %s has wrong signature", ErrMalformed, id.String())
+	}
+
+	parents := make([]*inter.EventHeaderData, len(e.Parents))
+	for i, parent := range e.Parents {
+		parents[i] = r.getHeader(parent)
+		if parents[i] == nil {
+			return fmt.Errorf("%v: parent %s of event %s isn't before it", ErrMalformed, parent.String(), id.String())
+		}
+	}
+	err := parentscheck.New(nil).Validate(&inter.Event{EventHeader: *e}, parents)
+	if err != nil {
+		return fmt.Errorf("%v: event %s: %v", ErrMalformed, id.String(), err)
+	}
+	// don't link to known cheaters
+	if len(r.vecClock.NoCheaters(e.SelfParent(), e.Parents)) != len(e.Parents) {
+		return fmt.Errorf("%v: event %s observes cheaters", ErrMalformed, id.String())
+	}
+
+	r.headers[id] = e
+	r.vecClock.Add(&e.EventHeaderData)
+	frame, isRoot := r.calcFrame(id, &e.EventHeaderData, parents)
+	if e.Frame != frame || e.IsRoot != isRoot {
+		r.vecClock.DropNotFlushed()
+		delete(r.headers, id)
+		return fmt.Errorf("%v: event %s has wrong frame", ErrMalformed, id.String())
+	}
+	r.vecClock.Flush()
+	r.order.Add(id)
+
+	if !e.IsRoot {
+		return nil
+	}
+	root := election.RootAndSlot{
+		ID: id,
+		Slot: election.Slot{
+			Frame:     e.Frame,
+			Validator: e.Creator,
+		},
+	}
+	r.roots[e.Frame] = append(r.roots[e.Frame], root)
+	if r.decided != nil {
+		return nil
+	}
+	if e.Frame > r.frame {
+		r.voters.Add(id)
+	}
+	r.decided, err = r.election.ProcessRoot(root)
+	return err
+}
+
+// calcFrame returns the frame and isRoot of the event, the same as poset.calcFrameIdx checks them.
+func (r *replay) calcFrame(id hash.Event, e *inter.EventHeaderData, parents []*inter.EventHeaderData) (idx.Frame, bool) {
+	if len(e.Parents) == 0 {
+		// special case for very first events in the epoch
+		return 1, true
+	}
+
+	selfParentFrame := idx.Frame(0)
+	if e.SelfParent() != nil {
+		selfParentFrame = parents[0].Frame
+	}
+	if !e.IsRoot {
+		return selfParentFrame, false
+	}
+	isRoot := e.Frame == selfParentFrame+1 && (e.Frame <= 1 || r.forklessCausedByQuorumOn(id, e.Frame-1))
+	return selfParentFrame + 1, isRoot
+}
+
+// forklessCausedByQuorumOn returns true if event is forkless caused by 2/3W roots on specified frame
+func (r *replay) forklessCausedByQuorumOn(id hash.Event, f idx.Frame) bool {
+	observedCounter := r.vecClock.NewQuorumCounter(id)
+	for _, it := range r.roots[f] {
+		if r.vecClock.ForklessCause(id, it.ID) {
+			observedCounter.Count(it.Slot.Validator)
+		}
+		if observedCounter.HasQuorum() {
+			break
+		}
+	}
+	return observedCounter.HasQuorum()
+}
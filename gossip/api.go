@@ -28,7 +28,11 @@ func (api *PublicEthereumAPI) Coinbase() (common.Address, error) {
 
 // Validator is the validator address
 func (api *PublicEthereumAPI) Validator() (common.Address, error) {
-	_, addr := api.s.emitter.GetValidator()
+	em := api.s.getEmitter()
+	if em == nil {
+		return common.Address{}, errEmitterNotStarted
+	}
+	_, addr := em.GetValidator()
 	return addr, nil
 }
 
@@ -0,0 +1,215 @@
+package gossip
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/Fantom-foundation/go-lachesis/ethapi"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+)
+
+var errEmitterNotStarted = errors.New("emitter isn't started")
+
+// PrivateEmitterAPI provides the runtime control of the events emitter, e.g. to drain a validator before a maintenance.
+// It's exposed in the admin namespace.
+type PrivateEmitterAPI struct {
+	s *Service
+}
+
+// NewPrivateEmitterAPI creates a new emitter control API.
+func NewPrivateEmitterAPI(s *Service) *PrivateEmitterAPI {
+	return &PrivateEmitterAPI{s}
+}
+
+// EmitIntervalsArgs are the emit intervals to change, e.g. "200ms" or "10m". The missing ones aren't changed.
+type EmitIntervalsArgs struct {
+	Min                *string `json:"min"`
+	Max                *string `json:"max"`
+	Confirming         *string `json:"confirming"`
+	SelfForkProtection *string `json:"selfForkProtection"`
+}
+
+// GasPowerThresholdsArgs are the gas power thresholds to change. The missing ones aren't changed.
+type GasPowerThresholdsArgs struct {
+	SmoothTps *hexutil.Uint64 `json:"smoothTps"`
+	NoTxs     *hexutil.Uint64 `json:"noTxs"`
+	Emergency *hexutil.Uint64 `json:"emergency"`
+}
+
+func (api *PrivateEmitterAPI) emitter() (*Emitter, error) {
+	em := api.s.getEmitter()
+	if em == nil {
+		return nil, errEmitterNotStarted
+	}
+	return em, nil
+}
+
+// EmitterPause stops the events emission, the node keeps following the DAG.
+func (api *PrivateEmitterAPI) EmitterPause() (bool, error) {
+	em, err := api.emitter()
+	if err != nil {
+		return false, err
+	}
+	em.StopEventEmission()
+	api.s.Log.Warn("Events emission is paused")
+	return true, nil
+}
+
+// EmitterResume starts the events emission after the pause.
+func (api *PrivateEmitterAPI) EmitterResume() (bool, error) {
+	em, err := api.emitter()
+	if err != nil {
+		return false, err
+	}
+	em.StartEventEmission()
+	api.s.Log.Info("Events emission is resumed")
+	return true, nil
+}
+
+// EmitterSetValidator switches the validator address, the zero address turns the node into a non-validator.
+// The key is unlocked with the password if it's given. The key isn't checked if the events are signed by an external signer.
+func (api *PrivateEmitterAPI) EmitterSetValidator(addr common.Address, password *string) (bool, error) {
+	em, err := api.emitter()
+	if err != nil {
+		return false, err
+	}
+	if api.s.eventSigner != nil {
+		if password != nil {
+			return false, errors.New("the key is kept by the external signer")
+		}
+	} else if addr != (common.Address{}) {
+		am := api.s.AccountManager()
+		acc := accounts.Account{Address: addr}
+		if password != nil {
+			if api.s.config.ExtRPCEnabled && !am.Config().InsecureUnlockAllowed {
+				return false, errors.New("account unlock with HTTP access is forbidden")
+			}
+			kss := am.Backends(keystore.KeyStoreType)
+			if len(kss) == 0 {
+				return false, errors.New("keystore isn't found")
+			}
+			if err := kss[0].(*keystore.KeyStore).Unlock(acc, *password); err != nil {
+				return false, err
+			}
+		} else if _, err := am.Find(acc); err != nil {
+			return false, err
+		}
+	}
+	em.SetValidator(addr)
+	api.s.Log.Warn("Validator is switched", "address", addr)
+	return true, nil
+}
+
+// EmitterSetIntervals changes the emit intervals.
+func (api *PrivateEmitterAPI) EmitterSetIntervals(args EmitIntervalsArgs) (map[string]interface{}, error) {
+	em, err := api.emitter()
+	if err != nil {
+		return nil, err
+	}
+	intervals := em.GetIntervals()
+	for _, it := range []struct {
+		arg *string
+		val *time.Duration
+	}{
+		{args.Min, &intervals.Min},
+		{args.Max, &intervals.Max},
+		{args.Confirming, &intervals.Confirming},
+		{args.SelfForkProtection, &intervals.SelfForkProtection},
+	} {
+		if it.arg == nil {
+			continue
+		}
+		d, err := time.ParseDuration(*it.arg)
+		if err != nil {
+			return nil, err
+		}
+		if d < 0 {
+			return nil, fmt.Errorf("negative interval %s", *it.arg)
+		}
+		*it.val = d
+	}
+	if intervals.Max == 0 || intervals.Min > intervals.Max {
+		return nil, fmt.Errorf("invalid intervals: min %s, max %s", intervals.Min, intervals.Max)
+	}
+	em.SetIntervals(intervals)
+	return marshalEmitIntervals(intervals), nil
+}
+
+// EmitterSetGasPowerThresholds changes the thresholds on the gas power left.
+func (api *PrivateEmitterAPI) EmitterSetGasPowerThresholds(args GasPowerThresholdsArgs) (map[string]interface{}, error) {
+	em, err := api.emitter()
+	if err != nil {
+		return nil, err
+	}
+	thresholds := em.GetGasPowerThresholds()
+	if args.SmoothTps != nil {
+		thresholds.SmoothTps = uint64(*args.SmoothTps)
+	}
+	if args.NoTxs != nil {
+		thresholds.NoTxs = uint64(*args.NoTxs)
+	}
+	if args.Emergency != nil {
+		thresholds.Emergency = uint64(*args.Emergency)
+	}
+	em.SetGasPowerThresholds(thresholds)
+	return marshalGasPowerThresholds(thresholds), nil
+}
+
+// EmitterStatus returns the current state of the emitter.
+func (api *PrivateEmitterAPI) EmitterStatus() (map[string]interface{}, error) {
+	em, err := api.emitter()
+	if err != nil {
+		return nil, err
+	}
+	status := em.Status()
+
+	res := map[string]interface{}{
+		"emitting":   status.Emitting,
+		"validator":  status.Validator,
+		"stakerID":   status.StakerID,
+		"synced":     status.Synced,
+		"hotStandby": status.HotStandby,
+		"tookOver":   status.TookOver,
+		"intervals":  marshalEmitIntervals(status.Intervals),
+		"thresholds": marshalGasPowerThresholds(status.Thresholds),
+	}
+	if !status.Synced {
+		res["syncReason"] = status.SyncReason
+		res["syncWait"] = status.SyncWait.String()
+	}
+	if status.LastEvent != nil {
+		res["lastEvent"] = ethapi.RPCMarshalEventHeader(status.LastEvent)
+		res["gasPowerLeft"] = map[string]interface{}{
+			"shortTerm": status.LastEvent.GasPowerLeft.Gas[idx.ShortTermGas],
+			"longTerm":  status.LastEvent.GasPowerLeft.Gas[idx.LongTermGas],
+		}
+	}
+	if !status.LastEmittedTime.IsZero() {
+		res["lastEmitted"] = status.LastEmitted
+		res["lastEmittedTime"] = status.LastEmittedTime
+	}
+	return res, nil
+}
+
+func marshalEmitIntervals(intervals EmitIntervals) map[string]interface{} {
+	return map[string]interface{}{
+		"min":                intervals.Min.String(),
+		"max":                intervals.Max.String(),
+		"confirming":         intervals.Confirming.String(),
+		"selfForkProtection": intervals.SelfForkProtection.String(),
+	}
+}
+
+func marshalGasPowerThresholds(thresholds GasPowerThresholds) map[string]interface{} {
+	return map[string]interface{}{
+		"smoothTps": hexutil.Uint64(thresholds.SmoothTps),
+		"noTxs":     hexutil.Uint64(thresholds.NoTxs),
+		"emergency": hexutil.Uint64(thresholds.Emergency),
+	}
+}
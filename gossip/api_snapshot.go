@@ -0,0 +1,44 @@
+package gossip
+
+import (
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// PrivateSnapshotAPI provides the capturing of the state for export-snapshot, if EpochSnapshot option is disabled.
+// It's exposed in the admin namespace.
+type PrivateSnapshotAPI struct {
+	s *Service
+}
+
+// NewPrivateSnapshotAPI creates a new snapshot API.
+func NewPrivateSnapshotAPI(s *Service) *PrivateSnapshotAPI {
+	return &PrivateSnapshotAPI{s}
+}
+
+// RequestSnapshot makes the node capture the state at the beginning of the next epoch.
+// It returns the epoch to be captured.
+func (api *PrivateSnapshotAPI) RequestSnapshot() hexutil.Uint64 {
+	api.s.engineMu.RLock()
+	defer api.s.engineMu.RUnlock()
+
+	atomic.StoreUint32(&api.s.snapshotRequested, 1)
+	epoch := api.s.engine.GetEpoch() + 1
+	api.s.Log.Info("Snapshot is requested", "epoch", epoch)
+	return hexutil.Uint64(epoch)
+}
+
+// SnapshotStatus returns the epoch of the last captured snapshot, and whether the next one is requested.
+func (api *PrivateSnapshotAPI) SnapshotStatus() map[string]interface{} {
+	api.s.engineMu.RLock()
+	defer api.s.engineMu.RUnlock()
+
+	res := map[string]interface{}{
+		"requested": api.s.config.EpochSnapshot || atomic.LoadUint32(&api.s.snapshotRequested) != 0,
+	}
+	if snap := api.s.store.GetSnapshot(); snap != nil {
+		res["epoch"] = hexutil.Uint64(snap.Epoch)
+	}
+	return res
+}
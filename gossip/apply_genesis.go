@@ -9,7 +9,6 @@ import (
 	"github.com/Fantom-foundation/go-lachesis/evmcore"
 	"github.com/Fantom-foundation/go-lachesis/hash"
 	"github.com/Fantom-foundation/go-lachesis/inter"
-	"github.com/Fantom-foundation/go-lachesis/inter/idx"
 	"github.com/Fantom-foundation/go-lachesis/inter/sfctype"
 	"github.com/Fantom-foundation/go-lachesis/lachesis"
 )
@@ -58,19 +57,7 @@ func calcGenesisHash(net *lachesis.Config, state *evmcore.EvmBlock) hash.Event {
 }
 
 func (s *Store) applyGenesis(net *lachesis.Config, state *evmcore.EvmBlock) (genesisAtropos hash.Event, genesisState common.Hash, err error) {
-	prettyHash := func(net *lachesis.Config) hash.Event {
-		e := inter.NewEvent()
-		// for nice-looking ID
-		e.Epoch = 0
-		e.Lamport = idx.Lamport(net.Dag.MaxEpochBlocks)
-		// actual data hashed
-		e.Extra = net.Genesis.ExtraData
-		e.ClaimedTime = net.Genesis.Time
-		e.TxHash = net.Genesis.Alloc.Accounts.Hash()
-
-		return e.CalcHash()
-	}
-	genesisAtropos = prettyHash(net)
+	genesisAtropos = net.GenesisAtropos()
 	genesisState = common.Hash(genesisAtropos)
 
 	block := inter.NewBlock(0,
@@ -38,9 +38,11 @@ func gasPowerBounds(initialAlloc, minAlloc, maxAlloc, customAlloc uint64) uint64
 }
 
 // ReadGasPowerContext reads current validation context for gaspowercheck
-func ReadGasPowerContext(s *Store, a *app.Store, validators *pos.Validators, epoch idx.Epoch, cfg *lachesis.EconomyConfig) *gaspowercheck.ValidationContext {
+// The gas power configs of the epoch's rules are applied.
+func ReadGasPowerContext(s *Store, a *app.Store, validators *pos.Validators, epoch idx.Epoch, net *lachesis.Config) *gaspowercheck.ValidationContext {
 	// engineMu is locked here
 	sfcConstants := a.GetSfcConstants(epoch - 1)
+	cfg := net.Rules(epoch).Economy
 
 	short := cfg.ShortGasPower
 	shortAllocPerSec := gasPowerBounds(short.InitialAllocPerSec, short.MinAllocPerSec, short.MaxAllocPerSec, sfcConstants.ShortGasPowerAllocPerSec)
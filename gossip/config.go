@@ -2,9 +2,12 @@ package gossip
 
 import (
 	"math/big"
+	"time"
 
+	"github.com/Fantom-foundation/go-lachesis/eventcheck/timecheck"
 	"github.com/Fantom-foundation/go-lachesis/evmcore"
 	"github.com/Fantom-foundation/go-lachesis/gossip/gasprice"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
 	"github.com/Fantom-foundation/go-lachesis/lachesis"
 	"github.com/Fantom-foundation/go-lachesis/lachesis/params"
 )
@@ -16,6 +19,12 @@ type (
 
 		LatencyImportance    int
 		ThroughputImportance int
+
+		// MaxTimePenalties is the number of the events received from the peer and rejected by the claimed time check,
+		// after which the peer is dropped
+		MaxTimePenalties int
+		// TimePenaltyDecay is the period after which one penalty of the claimed time check is forgiven
+		TimePenaltyDecay time.Duration
 	}
 	// Config for the gossip service.
 	Config struct {
@@ -24,9 +33,17 @@ type (
 		TxPool  evmcore.TxPoolConfig
 		StoreConfig
 
+		// Claimed time check options
+		TimeCheck timecheck.Config
+
 		TxIndex             bool // Whether to enable indexing transactions and receipts or not
 		DecisiveEventsIndex bool // Whether to enable indexing events which decide blocks or not
 		EventLocalTimeIndex bool // Whether to enable indexing arrival time of events or not
+		ElectionsIndex      bool // Whether to enable indexing votes of the decided elections or not
+		EpochSnapshot       bool // Whether to capture the state at the beginning of each epoch for snapshots, or only on admin_requestSnapshot
+
+		// History pruning options
+		Pruning PruningConfig
 
 		// Protocol options
 		Protocol ProtocolConfig
@@ -45,12 +62,28 @@ type (
 
 		// RPCGasCap is the global gas cap for eth-call variants.
 		RPCGasCap *big.Int `toml:",omitempty"`
+		// RPCLogsLimit is the max number of the logs which eth_getLogs and the logs filters return, 0 means no limit.
+		// The search stops and fails once more logs match.
+		RPCLogsLimit int
 
 		ExtRPCEnabled bool
 	}
 
+	// PruningConfig is a config for history pruning.
+	PruningConfig struct {
+		// Number of the last epochs to keep events, packs and tx positions of, 0 to keep all.
+		Epochs idx.Epoch
+		// Number of the last blocks to keep receipts and EVM states of, 0 to keep all.
+		Blocks idx.Block
+		// Interval between pruning passes.
+		Interval time.Duration
+	}
+
 	// StoreConfig is a config for store db.
 	StoreConfig struct {
+		// Engine of the on-disk databases: leveldb or badger.
+		DBEngine string
+
 		// Cache size for Events.
 		EventsCacheSize int
 		// Cache size for EventHeaderData (Epoch db).
@@ -81,13 +114,21 @@ func DefaultConfig(network lachesis.Config) Config {
 		Emitter:     DefaultEmitterConfig(),
 		TxPool:      evmcore.DefaultTxPoolConfig(),
 		StoreConfig: DefaultStoreConfig(),
+		TimeCheck:   timecheck.DefaultConfig(),
 
 		TxIndex:             true,
 		DecisiveEventsIndex: false,
+		EpochSnapshot:       false,
+
+		Pruning: PruningConfig{
+			Interval: defaultPruningInterval,
+		},
 
 		Protocol: ProtocolConfig{
 			LatencyImportance:    60,
 			ThroughputImportance: 40,
+			MaxTimePenalties:     3,
+			TimePenaltyDecay:     10 * time.Minute,
 		},
 
 		GPO: gasprice.Config{
@@ -115,6 +156,7 @@ func DefaultConfig(network lachesis.Config) Config {
 // DefaultStoreConfig for product.
 func DefaultStoreConfig() StoreConfig {
 	return StoreConfig{
+		DBEngine:               "leveldb",
 		EventsCacheSize:        500,
 		EventsHeadersCacheSize: 10000,
 		BlockCacheSize:         100,
@@ -1,7 +1,9 @@
 package gossip
 
 import (
+	"fmt"
 	"math/rand"
+	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -11,6 +13,29 @@ import (
 	"github.com/Fantom-foundation/go-lachesis/lachesis/params"
 )
 
+// Parent-selection strategies of the emitter.
+const (
+	// CasualityParentsStrategy chooses the parents which observe more validators, it's the default one
+	CasualityParentsStrategy = "casuality"
+	// RandomParentsStrategy chooses random parents
+	RandomParentsStrategy = "random"
+	// StakeParentsStrategy chooses random parents, the probability is proportional to the creator's stake
+	StakeParentsStrategy = "stake"
+	// LatencyParentsStrategy chooses the parents of the creators with the lowest observed latency
+	LatencyParentsStrategy = "latency"
+	// RootsParentsStrategy chooses the parents which make the event forkless-cause the most roots
+	RootsParentsStrategy = "roots"
+)
+
+// ParentsStrategies lists the supported parent-selection strategies.
+var ParentsStrategies = []string{
+	CasualityParentsStrategy,
+	RandomParentsStrategy,
+	StakeParentsStrategy,
+	LatencyParentsStrategy,
+	RootsParentsStrategy,
+}
+
 // EmitIntervals is the configuration of emit intervals.
 type EmitIntervals struct {
 	Min                time.Duration `json:"min"`
@@ -19,6 +44,23 @@ type EmitIntervals struct {
 	SelfForkProtection time.Duration `json:"selfForkProtection"`
 }
 
+// HotStandbyConfig is the configuration of the hot-standby mode, in which the node follows the DAG
+// with the same validator key as the primary node, and takes over the events emission if the primary goes offline.
+type HotStandbyConfig struct {
+	// Enabled turns on the hot-standby mode, the node doesn't sign events until the takeover.
+	Enabled bool `json:"enabled"`
+	// Takeover is the time without the primary's events, after which the node starts emitting.
+	Takeover time.Duration `json:"takeover"`
+}
+
+// ExternalSignerConfig is the configuration of the external events signer, which keeps the validator key instead of the node.
+type ExternalSignerConfig struct {
+	// Endpoint is the IPC path or HTTP/WebSocket URL of the signer, the node's keystore is used if it's empty.
+	Endpoint string `json:"endpoint"`
+	// Timeout of the signing request, the events processing waits for it.
+	Timeout time.Duration `json:"timeout"`
+}
+
 // EmitterConfig is the configuration of events emitter.
 type EmitterConfig struct {
 	VersionToPublish string
@@ -27,6 +69,10 @@ type EmitterConfig struct {
 
 	EmitIntervals EmitIntervals `json:"emitIntervals"` // event emission intervals
 
+	HotStandby HotStandbyConfig `json:"hotStandby"`
+
+	ExternalSigner ExternalSignerConfig `json:"externalSigner"`
+
 	MaxGasRateGrowthFactor float64 `json:"maxGasRateGrowthFactor"` // fine to use float, because no need in determinism
 
 	MaxTxsFromSender int `json:"maxTxsFromSender"`
@@ -34,6 +80,8 @@ type EmitterConfig struct {
 	EpochTailLength idx.Frame `json:"epochTailLength"` // number of frames before event is considered epoch
 
 	MaxParents int `json:"maxParents"`
+	// ParentsStrategy is the parent-selection strategy, one of ParentsStrategies
+	ParentsStrategy string `json:"parentsStrategy"`
 
 	// thresholds on GasLeft
 	SmoothTpsThreshold uint64 `json:"smoothTpsThreshold"`
@@ -53,11 +101,20 @@ func DefaultEmitterConfig() EmitterConfig {
 			SelfForkProtection: 30 * time.Minute, // should be at least 2x of MaxEmitInterval
 		},
 
+		HotStandby: HotStandbyConfig{
+			Takeover: 30 * time.Minute, // should be at least 2x of MaxEmitInterval
+		},
+
+		ExternalSigner: ExternalSignerConfig{
+			Timeout: 2 * time.Second,
+		},
+
 		MaxGasRateGrowthFactor: 3.0,
 		MaxTxsFromSender:       TxTurnNonces,
 		EpochTailLength:        1,
 
-		MaxParents: 7,
+		MaxParents:      7,
+		ParentsStrategy: CasualityParentsStrategy,
 
 		SmoothTpsThreshold: (params.EventGas + params.TxGas) * 500,
 		NoTxsThreshold:     params.EventGas * 30,
@@ -65,6 +122,19 @@ func DefaultEmitterConfig() EmitterConfig {
 	}
 }
 
+// CheckParentsStrategy returns an error if the parent-selection strategy is unknown.
+func (cfg *EmitterConfig) CheckParentsStrategy() error {
+	if cfg.ParentsStrategy == "" {
+		return nil // default
+	}
+	for _, name := range ParentsStrategies {
+		if cfg.ParentsStrategy == name {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown parents strategy %q, supported are: %s", cfg.ParentsStrategy, strings.Join(ParentsStrategies, ", "))
+}
+
 // RandomizeEmitTime and return new config
 func (cfg *EmitIntervals) RandomizeEmitTime(r *rand.Rand) *EmitIntervals {
 	config := *cfg
@@ -84,5 +154,6 @@ func FakeEmitterConfig() EmitterConfig {
 	cfg := DefaultEmitterConfig()
 	cfg.EmitIntervals.Max = 10 * time.Second // don't wait long in fakenet
 	cfg.EmitIntervals.SelfForkProtection = cfg.EmitIntervals.Max * 3 / 2
+	cfg.HotStandby.Takeover = cfg.EmitIntervals.Max * 3
 	return cfg
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/Fantom-foundation/go-lachesis/inter"
 	"github.com/Fantom-foundation/go-lachesis/inter/idx"
 	"github.com/Fantom-foundation/go-lachesis/inter/pos"
+	"github.com/Fantom-foundation/go-lachesis/poset/election"
 	"github.com/Fantom-foundation/go-lachesis/vector"
 )
 
@@ -30,6 +31,12 @@ type Consensus interface {
 	GetEpochValidators() (*pos.Validators, idx.Epoch)
 	// GetConsensusTime calc consensus timestamp for given event.
 	GetConsensusTime(id hash.Event) (inter.Timestamp, error)
+	// GetEventConfirmedOn returns the frame which confirmed the event, or 0 if it isn't confirmed.
+	GetEventConfirmedOn(id hash.Event) idx.Frame
+	// GetElection returns the record of the decided election, if the elections index is enabled.
+	GetElection(epoch idx.Epoch, frame idx.Frame) *election.Record
+	// PruneSnapshots deletes the consensus states at the beginning of the epochs before the given one.
+	PruneSnapshots(before idx.Epoch)
 
 	// Bootstrap must be called (once) before calling other methods
 	Bootstrap(callbacks inter.ConsensusCallbacks)
@@ -2,6 +2,7 @@ package gossip
 
 import (
 	"math/big"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -16,6 +17,8 @@ import (
 	"github.com/Fantom-foundation/go-lachesis/inter"
 	"github.com/Fantom-foundation/go-lachesis/inter/idx"
 	"github.com/Fantom-foundation/go-lachesis/inter/pos"
+	"github.com/Fantom-foundation/go-lachesis/lachesis"
+	"github.com/Fantom-foundation/go-lachesis/light"
 	"github.com/Fantom-foundation/go-lachesis/tracing"
 )
 
@@ -57,7 +60,9 @@ func (s *Service) processEvent(realEngine Consensus, e *inter.Event) error {
 	s.store.AddHead(e.Epoch, e.Hash())
 
 	s.packsOnNewEvent(e, e.Epoch)
-	s.emitter.OnNewEvent(e)
+	if s.emitter != nil {
+		s.emitter.OnNewEvent(e)
+	}
 
 	newEpoch := oldEpoch
 	if realEngine != nil {
@@ -67,16 +72,22 @@ func (s *Service) processEvent(realEngine Consensus, e *inter.Event) error {
 	if newEpoch != oldEpoch {
 		// notify event checkers about new validation data
 		s.heavyCheckReader.Addrs.Store(ReadEpochPubKeys(s.app, newEpoch))
-		s.gasPowerCheckReader.Ctx.Store(ReadGasPowerContext(s.store, s.app, s.engine.GetValidators(), newEpoch, &s.config.Net.Economy))
+		s.gasPowerCheckReader.Ctx.Store(ReadGasPowerContext(s.store, s.app, s.engine.GetValidators(), newEpoch, &s.config.Net))
 
 		// sealings/prunings
 		s.packsOnNewEpoch(oldEpoch, newEpoch)
 		s.store.delEpochStore(oldEpoch)
 		s.store.getEpochStore(newEpoch)
 		s.occurredTxs.Clear()
+		s.switchEvmChainConfig(newEpoch)
+		if s.config.EpochSnapshot || atomic.CompareAndSwapUint32(&s.snapshotRequested, 1, 0) {
+			s.captureSnapshot(newEpoch)
+		}
 
 		// notify about new epoch after event connection
-		s.emitter.OnNewEpoch(s.engine.GetValidators(), newEpoch)
+		if s.emitter != nil {
+			s.emitter.OnNewEpoch(s.engine.GetValidators(), newEpoch)
+		}
 		s.feed.newEpoch.Send(newEpoch)
 	}
 
@@ -165,7 +176,20 @@ func (s *Service) applyNewState(
 	}
 
 	// calc appHash
+	result := &light.BlockResult{
+		Atropos:      block.Atropos,
+		TxHash:       block.TxHash,
+		Root:         block.Root,
+		ReceiptsRoot: types.DeriveSha(receipts),
+	}
+	if sealEpoch {
+		result.NextValidators = light.ValidatorsHash(s.epochValidatorsAndAddresses(block.Atropos.Epoch() + 1))
+	}
+	s.store.SetBlockResult(block.Atropos.Epoch(), block.Index, result)
 	appHash := block.TxHash
+	if s.config.Net.Rules(block.Atropos.Epoch()).LightClient {
+		appHash = result.Hash()
+	}
 
 	log.Info("New block", "index", block.Index, "atropos", block.Atropos, "fee", totalFee, "gasUsed",
 		evmBlock.GasUsed, "skipped_txs", len(block.SkippedTxs), "txs", len(evmBlock.Transactions), "t", time.Since(start))
@@ -179,6 +203,7 @@ func (s *Service) spillBlockEvents(block *inter.Block) (*inter.Block, inter.Even
 	if len(block.Events) == 0 {
 		return block, fullEvents
 	}
+	gasLimit := s.config.Net.Rules(block.Atropos.Epoch()).Blocks.BlockGasHardLimit
 	gasPowerUsedSum := uint64(0)
 	// iterate in reversed order
 	for i := len(block.Events) - 1; ; i-- {
@@ -190,7 +215,7 @@ func (s *Service) spillBlockEvents(block *inter.Block) (*inter.Block, inter.Even
 		fullEvents[i] = e
 		gasPowerUsedSum += e.GasPowerUsed
 		// stop if limit is exceeded, erase [:i] events
-		if gasPowerUsedSum > s.config.Net.Blocks.BlockGasHardLimit {
+		if gasPowerUsedSum > gasLimit {
 			// spill
 			block.Events = block.Events[i+1:]
 			fullEvents = fullEvents[i+1:]
@@ -254,7 +279,8 @@ func (s *Service) executeEvmTransactions(
 ) {
 	// s.engineMu is locked here
 
-	evmProcessor := evmcore.NewStateProcessor(s.config.Net.EvmChainConfig(), s.GetEvmStateReader())
+	rules := s.config.Net.Rules(block.Atropos.Epoch())
+	evmProcessor := evmcore.NewStateProcessor(rules.EvmChainConfig(), s.GetEvmStateReader())
 
 	// Process txs
 	receipts, _, gasUsed, totalFee, skipped, err := evmProcessor.Process(evmBlock, statedb, vm.Config{}, false)
@@ -280,6 +306,19 @@ func (s *Service) executeEvmTransactions(
 	return block, evmBlock, totalFee, receipts
 }
 
+// switchEvmChainConfig applies the EVM chain config of the epoch rules to the txpool and the occurred txs.
+func (s *Service) switchEvmChainConfig(epoch idx.Epoch) {
+	rules := s.config.Net.Rules(epoch)
+	chainConfig := rules.EvmChainConfig()
+	s.occurredTxs.SetSigner(types.NewEIP155Signer(chainConfig.ChainID))
+	if s.txpool == nil {
+		return
+	}
+	if err := s.txpool.SetChainConfig(chainConfig); err != nil {
+		s.Log.Error("Failed to switch the txpool chain config", "epoch", epoch, "err", err)
+	}
+}
+
 // onEpochSealed applies the new epoch sealing state
 func (s *Service) onEpochSealed(block *inter.Block, cheaters inter.Cheaters) {
 	// s.engineMu is locked here
@@ -290,6 +329,12 @@ func (s *Service) onEpochSealed(block *inter.Block, cheaters inter.Cheaters) {
 	for _, cheater := range cheaters {
 		s.store.DelLastHeader(epoch, cheater) // for cheaters, it's uncertain which event is "last confirmed"
 	}
+	// evicted cheaters may be not observed by the last Atropos
+	for _, it := range s.app.GetEpochValidators(epoch) {
+		if staker := s.app.GetSfcStaker(it.StakerID); staker != nil && staker.HasFork() {
+			s.store.DelLastHeader(epoch, it.StakerID)
+		}
+	}
 	// prune not needed last headers
 	s.store.DelLastHeaders(epoch - 1)
 }
@@ -299,14 +344,28 @@ func (s *Service) applyBlock(block *inter.Block, decidedFrame idx.Frame, cheater
 	// s.engineMu is locked here
 
 	confirmBlocksMeter.Inc(1)
-	// if cheater is confirmed, seal epoch right away to prune them from of BFT validators list
 
-	epochStart := s.store.GetEpochStats(pendingEpoch).Start
-	sealEpoch = decidedFrame >= s.config.Net.Dag.MaxEpochBlocks
-	sealEpoch = sealEpoch || block.Time-epochStart >= inter.Timestamp(s.config.Net.Dag.MaxEpochDuration)
-	sealEpoch = sealEpoch || cheaters.Len() > 0
+	epoch := block.Atropos.Epoch()
+	rules := s.config.Net.Rules(epoch)
+	sealEpoch = s.epochSealerOf(epoch, &rules.Dag).SealEpoch(&lachesis.EpochSealingContext{
+		Block:        block,
+		DecidedFrame: decidedFrame,
+		EpochStart:   s.store.GetEpochStats(pendingEpoch).Start,
+		EpochGasUsed: s.epochGasUsed(epoch),
+		StakersChanged: func() bool {
+			return s.stakersChanged(epoch)
+		},
+	})
+	if !rules.Dag.VectorClockConfig.EvictCheaters {
+		// if cheater is confirmed, seal epoch right away to prune them from of BFT validators list
+		sealEpoch = sealEpoch || cheaters.Len() > 0
+	}
+	// otherwise, the cheaters are evicted from the quorum by the vector clock, and they're pruned on the regular sealing
+
+	s.recordForkEvidences(block, cheaters)
 
 	block, evmBlock, receipts, txPositions, newAppHash := s.applyNewState(block, sealEpoch, cheaters)
+	s.epochGas.gas += block.GasUsed
 
 	s.store.SetBlock(block)
 	s.store.SetBlockIndex(block.Atropos, block.Index)
@@ -394,7 +453,7 @@ func (s *Service) isEventAllowedIntoBlock(header *inter.EventHeaderData, seqDept
 	if header.NoTransactions() {
 		return false // block contains only non-empty events to speed up block retrieving and processing
 	}
-	if seqDepth > s.config.Net.Dag.MaxValidatorEventsInBlock {
+	if seqDepth > s.config.Net.Rules(header.Epoch).Dag.MaxValidatorEventsInBlock {
 		return false // block contains only MaxValidatorEventsInBlock highest events from a creator to prevent huge blocks
 	}
 	return true
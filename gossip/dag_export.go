@@ -0,0 +1,105 @@
+package gossip
+
+import (
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+)
+
+// GetEpochDag returns the epoch events with their consensus marks: Atroposes, confirming blocks and cheaters branches.
+// confirmedOn returns the frame which confirmed the event, or 0.
+// Arrival times are filled from the EventLocalTimes index, if withArrivalTime.
+func (s *Store) GetEpochDag(epoch idx.Epoch, confirmedOn func(hash.Event) idx.Frame, withArrivalTime bool) *inter.EpochDag {
+	dag := &inter.EpochDag{
+		Epoch: epoch,
+	}
+	seqs := make(map[idx.StakerID]map[idx.Event]int)
+	frameBlocks := make(map[idx.Frame]idx.Block)
+
+	s.ForEachEvent(epoch, func(e *inter.Event) bool {
+		de := &inter.DagEvent{
+			ID:          e.Hash(),
+			Creator:     e.Creator,
+			Seq:         e.Seq,
+			Lamport:     e.Lamport,
+			Frame:       e.Frame,
+			IsRoot:      e.IsRoot,
+			Parents:     e.Parents,
+			ClaimedTime: e.ClaimedTime,
+		}
+		if n := s.GetBlockIndex(de.ID); n != nil {
+			de.AtroposOf = *n
+			frameBlocks[de.Frame] = *n
+		}
+		if withArrivalTime {
+			de.ArrivalTime = s.GetEventReceivingTime(de.ID)
+		}
+
+		if seqs[de.Creator] == nil {
+			seqs[de.Creator] = make(map[idx.Event]int)
+		}
+		seqs[de.Creator][de.Seq]++
+
+		dag.Events = append(dag.Events, de)
+		return true
+	})
+
+	// the block of a frame is decided by the frame Atropos,
+	// the blocks events can't be used, because they are only the events with transactions
+	for _, de := range dag.Events {
+		if frame := confirmedOn(de.ID); frame != 0 {
+			de.Block = frameBlocks[frame]
+		}
+	}
+
+	markForkBranches(dag, seqs)
+	return dag
+}
+
+// markForkBranches marks the events of creators who have several events with the same seq.
+// A branch continues by the first event, which is found with the self-parent,
+// any next such event starts a new branch.
+func markForkBranches(dag *inter.EpochDag, seqs map[idx.StakerID]map[idx.Event]int) {
+	cheaters := make(map[idx.StakerID]bool)
+	for creator, counts := range seqs {
+		for _, count := range counts {
+			if count > 1 {
+				cheaters[creator] = true
+				break
+			}
+		}
+	}
+	if len(cheaters) == 0 {
+		return
+	}
+
+	var (
+		branches   = make(map[hash.Event]int)
+		continued  = make(map[idx.StakerID]map[hash.Event]bool)
+		nextBranch = make(map[idx.StakerID]int)
+	)
+	for _, e := range dag.Events {
+		if !cheaters[e.Creator] {
+			continue
+		}
+		if continued[e.Creator] == nil {
+			continued[e.Creator] = make(map[hash.Event]bool)
+			nextBranch[e.Creator] = 1
+		}
+
+		var selfParent hash.Event // zero for the first event of the creator
+		if e.Seq > 1 && len(e.Parents) != 0 {
+			selfParent = e.Parents[0]
+		}
+
+		e.Cheater = true
+		if !continued[e.Creator][selfParent] {
+			continued[e.Creator][selfParent] = true
+			e.Branch = branches[selfParent]
+		} else {
+			e.Branch = nextBranch[e.Creator]
+			nextBranch[e.Creator]++
+		}
+		branches[e.ID] = e.Branch
+	}
+}
@@ -0,0 +1,85 @@
+package gossip
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/logger"
+)
+
+func TestStoreGetEpochDag(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	store := cachedStore()
+
+	newEvent := func(creator idx.StakerID, seq idx.Event, lamport idx.Lamport, parents ...*inter.Event) *inter.Event {
+		e := fakeEvent()
+		e.Epoch = 1
+		e.Creator = creator
+		e.Seq = seq
+		e.Lamport = lamport
+		e.Frame = 1
+		e.IsRoot = seq == 1
+		for _, p := range parents {
+			e.Parents = append(e.Parents, p.Hash())
+		}
+		store.SetEvent(e)
+		return e
+	}
+	a1 := newEvent(1, 1, 1)
+	b1 := newEvent(2, 1, 1)
+	b2 := newEvent(2, 2, 2, b1, a1)
+	// creator 2 forks at seq 2
+	b2fork := newEvent(2, 2, 3, b1)
+	b3fork := newEvent(2, 3, 4, b2fork)
+	a2 := newEvent(1, 2, 5, a1, b3fork)
+
+	store.SetBlockIndex(a1.Hash(), 7)
+	store.SetEventReceivingTime(a2.Hash(), 100)
+	confirmedOn := func(id hash.Event) idx.Frame {
+		if id == a1.Hash() || id == b1.Hash() || id == b2.Hash() {
+			return 1
+		}
+		return 0
+	}
+
+	dag := store.GetEpochDag(1, confirmedOn, true)
+	assertar.Equal(idx.Epoch(1), dag.Epoch)
+	if !assertar.Len(dag.Events, 6) {
+		return
+	}
+	byID := make(map[hash.Event]*inter.DagEvent)
+	for i, e := range dag.Events {
+		byID[e.ID] = e
+		if i > 0 {
+			assertar.True(dag.Events[i-1].Lamport <= e.Lamport, "topological order")
+		}
+	}
+
+	assertar.Equal(idx.Block(7), byID[a1.Hash()].AtroposOf)
+	assertar.Equal(idx.Block(0), byID[a2.Hash()].AtroposOf)
+	for _, e := range []*inter.Event{a1, b1, b2} {
+		assertar.Equal(idx.Block(7), byID[e.Hash()].Block)
+	}
+	for _, e := range []*inter.Event{b2fork, b3fork, a2} {
+		assertar.Equal(idx.Block(0), byID[e.Hash()].Block)
+	}
+
+	assertar.False(byID[a1.Hash()].Cheater)
+	assertar.False(byID[a2.Hash()].Cheater)
+	for _, e := range []*inter.Event{b1, b2, b2fork, b3fork} {
+		assertar.True(byID[e.Hash()].Cheater)
+	}
+	assertar.Equal(0, byID[b1.Hash()].Branch)
+	assertar.Equal(0, byID[b2.Hash()].Branch)
+	assertar.Equal(1, byID[b2fork.Hash()].Branch)
+	assertar.Equal(1, byID[b3fork.Hash()].Branch)
+
+	assertar.Equal(inter.Timestamp(100), byID[a2.Hash()].ArrivalTime)
+	assertar.Equal(inter.Timestamp(0), store.GetEpochDag(1, confirmedOn, false).Events[5].ArrivalTime)
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/Fantom-foundation/go-lachesis/evmcore"
 	"github.com/Fantom-foundation/go-lachesis/gossip/occuredtxs"
 	"github.com/Fantom-foundation/go-lachesis/gossip/piecefunc"
+	"github.com/Fantom-foundation/go-lachesis/gossip/slashprotection"
 	"github.com/Fantom-foundation/go-lachesis/hash"
 	"github.com/Fantom-foundation/go-lachesis/inter"
 	"github.com/Fantom-foundation/go-lachesis/inter/ancestor"
@@ -28,9 +29,11 @@ import (
 	"github.com/Fantom-foundation/go-lachesis/lachesis"
 	"github.com/Fantom-foundation/go-lachesis/lachesis/params"
 	"github.com/Fantom-foundation/go-lachesis/logger"
+	"github.com/Fantom-foundation/go-lachesis/poset/election"
 	"github.com/Fantom-foundation/go-lachesis/tracing"
 	"github.com/Fantom-foundation/go-lachesis/utils"
 	"github.com/Fantom-foundation/go-lachesis/utils/errlock"
+	"github.com/Fantom-foundation/go-lachesis/vector"
 )
 
 const (
@@ -38,8 +41,16 @@ const (
 	TxTimeBufferSize = 20000
 	TxTurnPeriod     = 4 * time.Second
 	TxTurnNonces     = 8
+
+	signerMinBackoff = 500 * time.Millisecond
+	signerMaxBackoff = 30 * time.Second
 )
 
+// EventSigner signs the events by the validator key which isn't kept by the node.
+type EventSigner interface {
+	SignEvent(validator common.Address, e *inter.EventHeaderData) ([]byte, error)
+}
+
 // EmitterWorld is emitter's external world
 type EmitterWorld struct {
 	Store       *Store
@@ -52,11 +63,19 @@ type EmitterWorld struct {
 
 	Checkers *eventcheck.Checkers
 
+	// SlashingProtection refuses to sign the conflicting events, it's disabled if nil
+	SlashingProtection *slashprotection.DB
+	// Signer signs the events instead of the accounts manager, if it's set. It's called without EngineMu locked
+	Signer EventSigner
+
 	OnEmitted func(e *inter.Event)
 	IsSynced  func() bool
 	PeersNum  func() int
 
 	AddVersion func(e *inter.Event) *inter.Event
+
+	// Now is the emitter's clock, the system time is used if it's nil
+	Now func() time.Time
 }
 
 type Emitter struct {
@@ -71,14 +90,29 @@ type Emitter struct {
 	myAddress  common.Address
 
 	syncStatus selfForkProtection
+	standby    hotStandby
+
+	// stats for the parent-selection strategies
+	latencies *ancestor.Latencies
+	roots     frameRoots
 
 	gasRate         metrics.Meter
 	prevEmittedTime time.Time
 
+	// the emission is postponed after the external signer failures
+	signerErrors    int
+	signerRetryTime time.Time
+
 	intervals EmitIntervals
 
-	done chan struct{}
-	wg   sync.WaitGroup
+	// controlMu protects intervals, prevEmittedTime, signerRetryTime and the connected/synced times,
+	// which Tick reads without EngineMu. They are written with controlMu locked, and with EngineMu locked
+	// except the connected/synced times, so they may be read with either of the mutexes locked.
+	controlMu sync.Mutex
+
+	done       chan struct{}
+	wg         sync.WaitGroup
+	emissionMu sync.Mutex // protects done
 
 	logger.Periodic
 }
@@ -91,6 +125,16 @@ type selfForkProtection struct {
 	becameValidatorTime     time.Time
 }
 
+type frameRoots struct {
+	frame idx.Frame
+	roots []election.RootAndSlot
+}
+
+type hotStandby struct {
+	startTime time.Time
+	tookOver  bool
+}
+
 // NewEmitter creation.
 func NewEmitter(
 	net *lachesis.Config,
@@ -98,6 +142,9 @@ func NewEmitter(
 	world EmitterWorld,
 ) *Emitter {
 
+	if world.Now == nil {
+		world.Now = time.Now
+	}
 	txTime, _ := lru.New(TxTimeBufferSize)
 	loggerInstance := logger.MakeInstance()
 	return &Emitter{
@@ -108,51 +155,50 @@ func NewEmitter(
 		gasRate:   metrics.NewMeterForced(),
 		txTime:    txTime,
 		intervals: config.EmitIntervals,
+		latencies: ancestor.NewLatencies(),
 		Periodic:  logger.Periodic{Instance: loggerInstance},
 	}
 }
 
 // init emitter without starting events emission
 func (em *Emitter) init() {
-	em.syncStatus.connectedTime = time.Now()
+	em.controlMu.Lock()
+	em.syncStatus.connectedTime = em.world.Now()
+	em.controlMu.Unlock()
+	em.standby.startTime = em.world.Now()
+	em.standby.tookOver = false
 	validators, epoch := em.world.Engine.GetEpochValidators()
 	em.OnNewEpoch(validators, epoch)
 }
 
 // StartEventEmission starts event emission.
 func (em *Emitter) StartEventEmission() {
+	em.emissionMu.Lock()
+	defer em.emissionMu.Unlock()
 	if em.done != nil {
 		return
 	}
 	em.done = make(chan struct{})
 
 	newTxsCh := make(chan evmcore.NewTxsNotify)
-	em.world.Txpool.SubscribeNewTxsNotify(newTxsCh)
+	newTxsSub := em.world.Txpool.SubscribeNewTxsNotify(newTxsCh)
 
-	em.SetValidator(em.myAddress)
+	_, myAddress := em.GetValidator()
+	em.SetValidator(myAddress)
 
 	done := em.done
 	em.wg.Add(1)
 	go func() {
 		defer em.wg.Done()
+		defer newTxsSub.Unsubscribe()
 		ticker := time.NewTicker(10 * time.Millisecond)
+		defer ticker.Stop()
 		for {
 			select {
 			case txNotify := <-newTxsCh:
 				em.memorizeTxTimes(txNotify.Txs)
 			case <-ticker.C:
-				// track synced time
-				if em.world.PeersNum() == 0 {
-					em.syncStatus.connectedTime = time.Now() // connected time ~= last time when it's true that "not connected yet"
-				}
-				if !em.world.IsSynced() {
-					em.syncStatus.syncedTime = time.Now() // synced time ~= last time when it's true that "not synced yet"
-				}
-
-				// must pass at least MinEmitInterval since last event
-				if time.Since(em.prevEmittedTime) >= em.intervals.Min {
-					em.EmitEvent()
-				}
+				em.Tick()
 			case <-done:
 				return
 			}
@@ -160,8 +206,35 @@ func (em *Emitter) StartEventEmission() {
 	}()
 }
 
+// Tick is a step of the events emission loop: it tracks the sync status,
+// and emits an event if MinEmitInterval has passed since the previous one.
+// Returns the emitted event, or nil.
+func (em *Emitter) Tick() *inter.Event {
+	// EngineMu isn't locked, to not block the events processing by the frequent ticks
+	em.controlMu.Lock()
+	// track synced time
+	if em.world.PeersNum() == 0 {
+		em.syncStatus.connectedTime = em.world.Now() // connected time ~= last time when it's true that "not connected yet"
+	}
+	if !em.world.IsSynced() {
+		em.syncStatus.syncedTime = em.world.Now() // synced time ~= last time when it's true that "not synced yet"
+	}
+
+	// must pass at least MinEmitInterval since last event
+	now := em.world.Now()
+	ready := now.Sub(em.prevEmittedTime) >= em.intervals.Min && !now.Before(em.signerRetryTime)
+	em.controlMu.Unlock()
+
+	if ready {
+		return em.EmitEvent()
+	}
+	return nil
+}
+
 // StopEventEmission stops event emission.
 func (em *Emitter) StopEventEmission() {
+	em.emissionMu.Lock()
+	defer em.emissionMu.Unlock()
 	if em.done == nil {
 		return
 	}
@@ -207,7 +280,7 @@ func (em *Emitter) memorizeTxTimes(txs types.Transactions) {
 	if em.myStakerID == 0 {
 		return // short circuit if not validator
 	}
-	now := time.Now()
+	now := em.world.Now()
 	for _, tx := range txs {
 		_, ok := em.txTime.Get(tx.Hash())
 		if !ok {
@@ -257,7 +330,7 @@ func (em *Emitter) addTxs(e *inter.Event, poolTxs map[common.Address]types.Trans
 
 	maxGasUsed := em.maxGasPowerToUse(e)
 
-	now := time.Now()
+	now := em.world.Now()
 	validators := em.world.Engine.GetValidators()
 	validatorsArr := validators.SortedIDs() // validators must be sorted deterministically
 	validatorsArrStakes := make([]pos.Stake, len(validatorsArr))
@@ -301,9 +374,9 @@ func (em *Emitter) findBestParents(epoch idx.Epoch, myStakerID idx.StakerID) (*h
 	var strategy ancestor.SearchStrategy
 	vecClock := em.world.Engine.GetVectorIndex()
 	if vecClock != nil {
-		strategy = ancestor.NewCasualityStrategy(vecClock, em.world.Engine.GetValidators())
+		strategy = em.parentsStrategy(epoch, vecClock)
 		if rand.Intn(20) == 0 { // every 20th event uses random strategy is avoid repeating patterns in DAG
-			strategy = ancestor.NewRandomStrategy(rand.New(rand.NewSource(time.Now().UnixNano())))
+			strategy = ancestor.NewRandomStrategy(rand.New(rand.NewSource(em.world.Now().UnixNano())))
 		}
 
 		// don't link to known cheaters
@@ -317,19 +390,62 @@ func (em *Emitter) findBestParents(epoch idx.Epoch, myStakerID idx.StakerID) (*h
 		strategy = ancestor.NewRandomStrategy(nil)
 	}
 
+	dag := em.net.Rules(epoch).Dag
 	maxParents := em.config.MaxParents
-	if maxParents < em.net.Dag.MaxFreeParents {
-		maxParents = em.net.Dag.MaxFreeParents
+	if maxParents < dag.MaxFreeParents {
+		maxParents = dag.MaxFreeParents
 	}
-	if maxParents > em.net.Dag.MaxParents {
-		maxParents = em.net.Dag.MaxParents
+	if maxParents > dag.MaxParents {
+		maxParents = dag.MaxParents
 	}
 	_, parents := ancestor.FindBestParents(maxParents, heads, selfParent, strategy)
 	return selfParent, parents, true
 }
 
-// createEvent is not safe for concurrent use.
+// parentsStrategy makes the configured parent-selection strategy.
+func (em *Emitter) parentsStrategy(epoch idx.Epoch, vecClock *vector.Index) ancestor.SearchStrategy {
+	validators := em.world.Engine.GetValidators()
+	creatorOf := func(id hash.Event) idx.StakerID {
+		e := em.world.Store.GetEventHeader(epoch, id)
+		if e == nil {
+			return 0
+		}
+		return e.Creator
+	}
+
+	switch em.config.ParentsStrategy {
+	case RandomParentsStrategy:
+		return ancestor.NewRandomStrategy(rand.New(rand.NewSource(em.world.Now().UnixNano())))
+	case StakeParentsStrategy:
+		return ancestor.NewStakeWeightedStrategy(rand.New(rand.NewSource(em.world.Now().UnixNano())), validators, creatorOf)
+	case LatencyParentsStrategy:
+		return ancestor.NewLatencyStrategy(em.latencies, creatorOf)
+	case RootsParentsStrategy:
+		return ancestor.NewRootsStrategy(vecClock, validators, em.roots.roots)
+	default:
+		return ancestor.NewCasualityStrategy(vecClock, validators)
+	}
+}
+
+// createEvent builds the event signed by the key of the accounts manager.
+// It's not safe for concurrent use.
 func (em *Emitter) createEvent(poolTxs map[common.Address]types.Transactions) *inter.Event {
+	event := em.buildEvent(poolTxs)
+	if event == nil {
+		return nil
+	}
+	if err := em.signEvent(event); err != nil {
+		em.Periodic.Error(time.Second, "Failed to sign event. Please unlock account.", "err", err)
+		return nil
+	}
+	if !em.finishEvent(event) {
+		return nil
+	}
+	return event
+}
+
+// buildEvent builds the unsigned event, it's not safe for concurrent use.
+func (em *Emitter) buildEvent(poolTxs map[common.Address]types.Transactions) *inter.Event {
 	if em.myStakerID == 0 {
 		// not a validator
 		return nil
@@ -340,6 +456,9 @@ func (em *Emitter) createEvent(poolTxs map[common.Address]types.Transactions) *i
 		// I'm reindexing my old events, so don't create events until connect all the existing self-events
 		return nil
 	}
+	if !em.isStandbyAllowedToEmit() {
+		return nil
+	}
 
 	var (
 		epoch          = em.world.Engine.GetEpoch()
@@ -387,7 +506,7 @@ func (em *Emitter) createEvent(poolTxs map[common.Address]types.Transactions) *i
 
 	event.Parents = parents
 	event.Lamport = maxLamport + 1
-	event.ClaimedTime = inter.MaxTimestamp(inter.Timestamp(time.Now().UnixNano()), selfParentTime+1)
+	event.ClaimedTime = inter.MaxTimestamp(inter.Timestamp(em.world.Now().UnixNano()), selfParentTime+1)
 
 	// add version
 	if em.world.AddVersion != nil {
@@ -402,7 +521,8 @@ func (em *Emitter) createEvent(poolTxs map[common.Address]types.Transactions) *i
 	}
 
 	// calc initial GasPower
-	event.GasPowerUsed = basiccheck.CalcGasPowerUsed(event, &em.net.Dag)
+	rules := em.net.Rules(event.Epoch)
+	event.GasPowerUsed = basiccheck.CalcGasPowerUsed(event, &rules.Dag)
 	availableGasPower, err := em.world.Checkers.Gaspowercheck.CalcGasPower(&event.EventHeaderData, selfParentHeader)
 	if err != nil {
 		em.Log.Warn("Gas power calculation failed", "err", err)
@@ -426,39 +546,112 @@ func (em *Emitter) createEvent(poolTxs map[common.Address]types.Transactions) *i
 	// calc Merkle root
 	event.TxHash = types.DeriveSha(event.Transactions)
 
-	// sign
-	myAddress := em.myAddress
-	signer := func(data []byte) (sig []byte, err error) {
-		acc := accounts.Account{
-			Address: myAddress,
-		}
+	return event
+}
+
+// signEvent signs the event by the key of the accounts manager.
+func (em *Emitter) signEvent(e *inter.Event) error {
+	acc := accounts.Account{
+		Address: em.myAddress,
+	}
+	return e.Sign(func(data []byte) (sig []byte, err error) {
 		w, err := em.world.Am.Find(acc)
 		if err != nil {
 			return
 		}
 		return w.SignData(acc, MimetypeEvent, data)
+	})
+}
+
+// createEventExternally builds the event signed by the external signer.
+// The signer may be slow, so EngineMu (which must be locked) is released during the signing.
+func (em *Emitter) createEventExternally(poolTxs map[common.Address]types.Transactions) *inter.Event {
+	event := em.buildEvent(poolTxs)
+	if event == nil {
+		return nil
 	}
-	if err := event.Sign(signer); err != nil {
-		em.Periodic.Error(time.Second, "Failed to sign event. Please unlock account.", "err", err)
+
+	// the signer records the signed event in its slashing protection,
+	// so the event must pass the checks before it's signed, otherwise a dropped event would block its seq
+	if err := em.checkEvent(event, false); err != nil {
+		em.Periodic.Error(time.Second, "Built event incorrectly", "err", err)
+		return nil
+	}
+
+	myAddress := em.myAddress
+	em.world.EngineMu.Unlock()
+	err := event.Sign(func(data []byte) (sig []byte, err error) {
+		return em.world.Signer.SignEvent(myAddress, &event.EventHeaderData)
+	})
+	em.world.EngineMu.Lock()
+	if err != nil {
+		em.onSignerError(err)
+		return nil
+	}
+	em.signerErrors = 0
+
+	if em.isOutdated(event) {
+		em.Log.Debug("Dropped the outdated event after signing", "epoch", event.Epoch, "seq", event.Seq)
 		return nil
 	}
+	if !em.finishEvent(event) {
+		return nil
+	}
+	return event
+}
+
+// isOutdated returns true if the event isn't on top of the self-events anymore,
+// e.g. the epoch is sealed while the engine wasn't locked.
+func (em *Emitter) isOutdated(e *inter.Event) bool {
+	if e.Epoch != em.world.Engine.GetEpoch() || e.Creator != em.myStakerID {
+		return true
+	}
+	last := em.world.Store.GetLastEvent(e.Epoch, e.Creator)
+	if e.SelfParent() == nil {
+		return last != nil
+	}
+	return last == nil || *last != *e.SelfParent()
+}
+
+// finishEvent runs the sanity checks of the signed event and records it in the slashing protection.
+// Returns false if the event must be dropped.
+func (em *Emitter) finishEvent(e *inter.Event) bool {
 	// calc hash after event is fully built
-	event.RecacheHash()
-	event.RecacheSize()
-	{
-		// sanity check
-		if em.world.Checkers != nil {
-			if err := em.world.Checkers.Validate(event, parentHeaders); err != nil {
-				em.Periodic.Error(time.Second, "Signed event incorrectly", "err", err)
-				return nil
-			}
+	e.RecacheHash()
+	e.RecacheSize()
+	// sanity check
+	if err := em.checkEvent(e, true); err != nil {
+		em.Periodic.Error(time.Second, "Signed event incorrectly", "err", err)
+		return false
+	}
+	// the event isn't published if it may conflict with the events signed by the key on another node.
+	// It's recorded after all the checks, so a dropped event doesn't block its seq
+	if em.world.SlashingProtection != nil {
+		if err := em.world.SlashingProtection.CheckAndRecord(em.myAddress, &e.EventHeaderData); err != nil {
+			em.Periodic.Error(time.Second, "Slashing protection refused the signed event", "err", err)
+			return false
 		}
 	}
 
 	// set event name for debug
-	em.nameEventForDebug(event)
+	em.nameEventForDebug(e)
 
-	return event
+	return true
+}
+
+// checkEvent runs the sanity checks of the event, the signature is checked only if it's signed.
+func (em *Emitter) checkEvent(e *inter.Event, signed bool) error {
+	if em.world.Checkers == nil {
+		return nil
+	}
+	parentHeaders := make([]*inter.EventHeaderData, len(e.Parents))
+	for i, p := range e.Parents {
+		parentHeaders[i] = em.world.Store.GetEventHeader(e.Epoch, p)
+	}
+	if signed {
+		return em.world.Checkers.Validate(e, parentHeaders)
+	}
+	return em.world.Checkers.ValidateUnsigned(e, parentHeaders)
 }
 
 var (
@@ -504,7 +697,8 @@ var (
 func (em *Emitter) OnNewEpoch(newValidators *pos.Validators, newEpoch idx.Epoch) {
 	// update myStakerID
 	em.myStakerID, _ = em.findMyStakerID()
-	em.prevEmittedTime = em.loadPrevEmitTime()
+	em.roots = frameRoots{}
+	prevEmittedTime := em.loadPrevEmitTime()
 
 	// stakers with lower stake should emit less events to reduce network load
 	// confirmingEmitInterval = piecefunc(totalStakeBeforeMe / totalStake) * MinEmitInterval
@@ -519,15 +713,21 @@ func (em *Emitter) OnNewEpoch(newValidators *pos.Validators, newEpoch idx.Epoch)
 	}
 	stakeRatio := uint64((totalStakeBeforeMe * piecefunc.PercentUnit) / totalStake)
 	confirmingEmitIntervalRatio := piecefunc.Get(stakeRatio, confirmingEmitIntervalPieces)
-	em.intervals.Confirming = time.Duration(piecefunc.Mul(uint64(em.config.EmitIntervals.Confirming), confirmingEmitIntervalRatio))
+	confirmingInterval := time.Duration(piecefunc.Mul(uint64(em.config.EmitIntervals.Confirming), confirmingEmitIntervalRatio))
 
 	// stakers with lower stake should emit more events at idle, to catch up with other stakers if their frame is behind
 	// MaxEmitInterval = piecefunc(totalStakeBeforeMe / totalStake) * MaxEmitInterval
 	maxEmitIntervalRatio := piecefunc.Get(stakeRatio, maxEmitIntervalPieces)
-	em.intervals.Max = time.Duration(piecefunc.Mul(uint64(em.config.EmitIntervals.Max), maxEmitIntervalRatio))
+	maxInterval := time.Duration(piecefunc.Mul(uint64(em.config.EmitIntervals.Max), maxEmitIntervalRatio))
+
+	em.controlMu.Lock()
+	em.prevEmittedTime = prevEmittedTime
+	em.intervals.Confirming = confirmingInterval
+	em.intervals.Max = maxInterval
+	em.controlMu.Unlock()
 
 	// track when I've became validator
-	now := time.Now()
+	now := em.world.Now()
 	if em.myStakerID != 0 && !em.world.App.HasEpochValidator(newEpoch-1, em.myStakerID) {
 		em.syncStatus.becameValidatorTime = now
 	}
@@ -535,14 +735,42 @@ func (em *Emitter) OnNewEpoch(newValidators *pos.Validators, newEpoch idx.Epoch)
 
 // OnNewEvent tracks new events to find out am I properly synced or not
 func (em *Emitter) OnNewEvent(e *inter.Event) {
-	now := time.Now()
+	now := em.world.Now()
 	myStakerID := em.myStakerID
+
+	// track the stats for the parent-selection strategies
+	if e.Creator != myStakerID {
+		latency := now.Sub(e.ClaimedTime.Time())
+		if latency < 0 {
+			latency = 0 // clock drift
+		}
+		em.latencies.Observe(e.Creator, latency)
+	}
+	if e.IsRoot {
+		if e.Frame > em.roots.frame {
+			em.roots = frameRoots{frame: e.Frame}
+		}
+		if e.Frame == em.roots.frame {
+			em.roots.roots = append(em.roots.roots, election.RootAndSlot{
+				Slot: election.Slot{
+					Frame:     e.Frame,
+					Validator: e.Creator,
+				},
+				ID: e.Hash(),
+			})
+		}
+	}
+
 	if em.myStakerID != 0 && em.syncStatus.prevLocalEmittedID != e.Hash() {
 		if e.Creator == myStakerID {
 			// event was emitted by me on another instance
 			em.syncStatus.prevExternalEmittedTime = now
+			if em.config.HotStandby.Enabled {
+				em.onPrimaryEvent(e)
+				return
+			}
 
-			passedSinceEvent := time.Since(inter.MaxTimestamp(e.ClaimedTime, e.MedianTime).Time())
+			passedSinceEvent := now.Sub(inter.MaxTimestamp(e.ClaimedTime, e.MedianTime).Time())
 			threshold := em.intervals.SelfForkProtection
 			if threshold > time.Minute {
 				threshold = time.Minute
@@ -558,6 +786,60 @@ func (em *Emitter) OnNewEvent(e *inter.Event) {
 	}
 }
 
+// onPrimaryEvent tracks the events of the primary node in the hot-standby mode.
+func (em *Emitter) onPrimaryEvent(e *inter.Event) {
+	// never sign at a seq which is already used by the primary
+	if em.world.SlashingProtection != nil {
+		if err := em.world.SlashingProtection.Observe(em.myAddress, &e.EventHeaderData); err != nil {
+			em.Log.Error("Failed to record the primary's event in slashing protection", "event", e.Hash(), "err", err)
+		}
+	}
+	if em.standby.tookOver {
+		// the primary is back, so step back to not fork
+		em.standby.tookOver = false
+		em.Log.Warn("Primary validator node is back online, hot-standby stops emitting", "event", e.Hash())
+	}
+}
+
+// lastPrimaryActivity returns the time of the last known activity of the primary node.
+func (em *Emitter) lastPrimaryActivity() time.Time {
+	last := em.standby.startTime
+	if em.syncStatus.prevExternalEmittedTime.After(last) {
+		last = em.syncStatus.prevExternalEmittedTime
+	}
+	// the primary's events which were received before the node start
+	prevEventID := em.world.Store.GetLastEvent(em.world.Engine.GetEpoch(), em.myStakerID)
+	if prevEventID != nil && *prevEventID != em.syncStatus.prevLocalEmittedID {
+		prevEvent := em.world.Store.GetEventHeader(prevEventID.Epoch(), *prevEventID)
+		if prevEvent != nil {
+			if t := inter.MaxTimestamp(prevEvent.ClaimedTime, prevEvent.MedianTime).Time(); t.After(last) {
+				last = t
+			}
+		}
+	}
+	return last
+}
+
+// isStandbyAllowedToEmit returns true if the hot-standby mode is disabled,
+// or the primary node has been offline for the takeover time.
+func (em *Emitter) isStandbyAllowedToEmit() bool {
+	if !em.config.HotStandby.Enabled || em.standby.tookOver {
+		return true
+	}
+	if em.world.SlashingProtection == nil {
+		em.Periodic.Error(25*time.Second, "Hot-standby mode requires slashing protection, emitting is disabled")
+		return false
+	}
+	silence := em.world.Now().Sub(em.lastPrimaryActivity())
+	if silence < em.config.HotStandby.Takeover {
+		em.Periodic.Info(25*time.Second, "Hot-standby, primary validator node is online", "silence", silence, "wait", em.config.HotStandby.Takeover-silence)
+		return false
+	}
+	em.standby.tookOver = true
+	em.Log.Warn("Primary validator node is offline, hot-standby takes over the events emitting", "silence", silence)
+	return true
+}
+
 func (em *Emitter) isSynced() (bool, string, time.Duration) {
 	if em.intervals.SelfForkProtection == 0 {
 		return true, "", 0 // protection disabled
@@ -568,19 +850,24 @@ func (em *Emitter) isSynced() (bool, string, time.Duration) {
 	if !em.world.IsSynced() {
 		return false, "synchronizing (all the peers have higher/lower epoch)", 0
 	}
-	sinceLastExternalEvent := time.Since(em.syncStatus.prevExternalEmittedTime)
-	if sinceLastExternalEvent < em.intervals.SelfForkProtection {
+	// in the hot-standby mode, the self-events are expected and waited for by the takeover time
+	sinceLastExternalEvent := em.world.Now().Sub(em.syncStatus.prevExternalEmittedTime)
+	if !em.config.HotStandby.Enabled && sinceLastExternalEvent < em.intervals.SelfForkProtection {
 		return false, "synchronizing (not downloaded all the self-events)", em.intervals.SelfForkProtection - sinceLastExternalEvent
 	}
-	sinceBecameValidator := time.Since(em.syncStatus.becameValidatorTime)
+	sinceBecameValidator := em.world.Now().Sub(em.syncStatus.becameValidatorTime)
 	if sinceBecameValidator < em.intervals.SelfForkProtection {
 		return false, "synchronizing (just joined the validators group)", em.intervals.SelfForkProtection - sinceBecameValidator
 	}
-	syncedPassed := time.Since(em.syncStatus.syncedTime)
+	em.controlMu.Lock()
+	syncedTime, connectedTime := em.syncStatus.syncedTime, em.syncStatus.connectedTime
+	em.controlMu.Unlock()
+
+	syncedPassed := em.world.Now().Sub(syncedTime)
 	if syncedPassed < em.intervals.SelfForkProtection {
 		return false, "synchronized (waiting additional time)", em.intervals.SelfForkProtection - syncedPassed
 	}
-	connectedPassed := time.Since(em.syncStatus.connectedTime)
+	connectedPassed := em.world.Now().Sub(connectedTime)
 	if connectedPassed < em.intervals.SelfForkProtection {
 		return false, "synchronizing (recently connected)", em.intervals.SelfForkProtection - connectedPassed
 	}
@@ -601,7 +888,7 @@ func (em *Emitter) logSyncStatus(synced bool, reason string, wait time.Duration)
 
 // return true if event is in epoch tail (unlikely to confirm)
 func (em *Emitter) isEpochTail(e *inter.Event) bool {
-	return e.Frame >= em.net.Dag.MaxEpochBlocks-em.config.EpochTailLength
+	return e.Frame >= em.net.Rules(e.Epoch).Dag.MaxEpochBlocks-em.config.EpochTailLength
 }
 
 func (em *Emitter) maxGasPowerToUse(e *inter.Event) uint64 {
@@ -709,7 +996,12 @@ func (em *Emitter) EmitEvent() *inter.Event {
 	em.world.EngineMu.Lock()
 	defer em.world.EngineMu.Unlock()
 
-	e := em.createEvent(poolTxs)
+	var e *inter.Event
+	if em.world.Signer == nil {
+		e = em.createEvent(poolTxs)
+	} else {
+		e = em.createEventExternally(poolTxs)
+	}
 	if e == nil {
 		return nil
 	}
@@ -719,8 +1011,10 @@ func (em *Emitter) EmitEvent() *inter.Event {
 		em.world.OnEmitted(e)
 	}
 	em.gasRate.Mark(int64(e.GasPowerUsed))
-	em.prevEmittedTime = time.Now() // record time after connecting, to add the event processing time"
-	em.Log.Info("New event emitted", "id", e.Hash(), "parents", len(e.Parents), "by", e.Creator, "frame", inter.FmtFrame(e.Frame, e.IsRoot), "txs", e.Transactions.Len(), "t", time.Since(e.ClaimedTime.Time()))
+	em.controlMu.Lock()
+	em.prevEmittedTime = em.world.Now() // record time after connecting, to add the event processing time"
+	em.controlMu.Unlock()
+	em.Log.Info("New event emitted", "id", e.Hash(), "parents", len(e.Parents), "by", e.Creator, "frame", inter.FmtFrame(e.Frame, e.IsRoot), "txs", e.Transactions.Len(), "t", em.world.Now().Sub(e.ClaimedTime.Time()))
 
 	// metrics
 	for _, t := range e.Transactions {
@@ -731,6 +1025,21 @@ func (em *Emitter) EmitEvent() *inter.Event {
 	return e
 }
 
+// onSignerError postpones the next event after the external signer failure,
+// the delay grows with the consecutive failures up to signerMaxBackoff.
+func (em *Emitter) onSignerError(err error) {
+	delay := signerMinBackoff << uint(em.signerErrors)
+	if delay > signerMaxBackoff || delay <= 0 {
+		delay = signerMaxBackoff
+	} else {
+		em.signerErrors++
+	}
+	em.controlMu.Lock()
+	em.signerRetryTime = em.world.Now().Add(delay)
+	em.controlMu.Unlock()
+	em.Periodic.Error(time.Second, "External signer failed to sign event", "retry", delay, "err", err)
+}
+
 func (em *Emitter) nameEventForDebug(e *inter.Event) {
 	name := []rune(hash.GetNodeName(e.Creator))
 	if len(name) < 1 {
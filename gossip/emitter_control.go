@@ -0,0 +1,120 @@
+package gossip
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+)
+
+type (
+	// GasPowerThresholds are the thresholds on the gas power left, which slow down the events emitting.
+	GasPowerThresholds struct {
+		SmoothTps uint64 `json:"smoothTps"`
+		NoTxs     uint64 `json:"noTxs"`
+		Emergency uint64 `json:"emergency"`
+	}
+
+	// EmitterStatus is the current state of the emitter.
+	EmitterStatus struct {
+		Emitting  bool
+		Validator common.Address
+		StakerID  idx.StakerID
+
+		Synced     bool
+		SyncReason string
+		SyncWait   time.Duration
+
+		HotStandby bool
+		TookOver   bool
+
+		// LastEvent is the last known event of the validator, it may be emitted by another instance
+		LastEvent       *inter.EventHeaderData
+		LastEmitted     hash.Event
+		LastEmittedTime time.Time
+
+		Intervals  EmitIntervals
+		Thresholds GasPowerThresholds
+	}
+)
+
+// IsEmitting returns true if the events emission is started.
+func (em *Emitter) IsEmitting() bool {
+	em.emissionMu.Lock()
+	defer em.emissionMu.Unlock()
+	return em.done != nil
+}
+
+// SetIntervals changes the emit intervals.
+// The intervals are adjusted by the validator's stake since the next epoch, as the configured ones.
+func (em *Emitter) SetIntervals(intervals EmitIntervals) {
+	em.world.EngineMu.Lock()
+	defer em.world.EngineMu.Unlock()
+	em.config.EmitIntervals = intervals
+	em.controlMu.Lock()
+	em.intervals = intervals
+	em.controlMu.Unlock()
+}
+
+// GetIntervals returns the emit intervals.
+func (em *Emitter) GetIntervals() EmitIntervals {
+	em.world.EngineMu.RLock()
+	defer em.world.EngineMu.RUnlock()
+	return em.intervals
+}
+
+// SetGasPowerThresholds changes the thresholds on the gas power left.
+func (em *Emitter) SetGasPowerThresholds(t GasPowerThresholds) {
+	em.world.EngineMu.Lock()
+	defer em.world.EngineMu.Unlock()
+	em.config.SmoothTpsThreshold = t.SmoothTps
+	em.config.NoTxsThreshold = t.NoTxs
+	em.config.EmergencyThreshold = t.Emergency
+}
+
+// GetGasPowerThresholds returns the thresholds on the gas power left.
+func (em *Emitter) GetGasPowerThresholds() GasPowerThresholds {
+	em.world.EngineMu.RLock()
+	defer em.world.EngineMu.RUnlock()
+	return em.gasPowerThresholds()
+}
+
+func (em *Emitter) gasPowerThresholds() GasPowerThresholds {
+	return GasPowerThresholds{
+		SmoothTps: em.config.SmoothTpsThreshold,
+		NoTxs:     em.config.NoTxsThreshold,
+		Emergency: em.config.EmergencyThreshold,
+	}
+}
+
+// Status returns the current state of the emitter.
+func (em *Emitter) Status() EmitterStatus {
+	emitting := em.IsEmitting()
+
+	em.world.EngineMu.RLock()
+	defer em.world.EngineMu.RUnlock()
+
+	status := EmitterStatus{
+		Emitting:        emitting,
+		Validator:       em.myAddress,
+		StakerID:        em.myStakerID,
+		HotStandby:      em.config.HotStandby.Enabled,
+		TookOver:        em.standby.tookOver,
+		LastEmitted:     em.syncStatus.prevLocalEmittedID,
+		LastEmittedTime: em.prevEmittedTime,
+		Intervals:       em.intervals,
+		Thresholds:      em.gasPowerThresholds(),
+	}
+	status.Synced, status.SyncReason, status.SyncWait = em.isSynced()
+
+	if em.myStakerID != 0 {
+		lastID := em.world.Store.GetLastEvent(em.world.Engine.GetEpoch(), em.myStakerID)
+		if lastID != nil {
+			status.LastEvent = em.world.Store.GetEventHeader(lastID.Epoch(), *lastID)
+		}
+	}
+	return status
+}
@@ -0,0 +1,76 @@
+package gossip
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/pos"
+	"github.com/Fantom-foundation/go-lachesis/lachesis"
+	"github.com/Fantom-foundation/go-lachesis/lachesis/genesis"
+	"github.com/Fantom-foundation/go-lachesis/logger"
+)
+
+func TestEmitterControlLocks(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	net := lachesis.FakeNetConfig(genesis.FakeValidators(3, big.NewInt(0), pos.StakeToBalance(1)))
+	config := DefaultConfig(net)
+	config.TxPool.Journal = ""
+	svc, _, err := newTestService(&node.ServiceContext{}, &config)
+	if !assertar.NoError(err) {
+		return
+	}
+	defer svc.StopManual()
+
+	api := NewPrivateEmitterAPI(svc)
+	_, err = api.EmitterStatus()
+	assertar.Equal(errEmitterNotStarted, err)
+
+	em := svc.ManualEmitter(time.Now)
+	min, max := "1h", "2h"
+	_, err = api.EmitterSetIntervals(EmitIntervalsArgs{Min: &min, Max: &max})
+	assertar.NoError(err)
+
+	// the ticks don't wait for the events processing
+	svc.engineMu.Lock()
+	ticked := make(chan *inter.Event)
+	go func() {
+		ticked <- em.Tick()
+	}()
+	select {
+	case e := <-ticked:
+		assertar.Nil(e)
+	case <-time.After(5 * time.Second):
+		assertar.Fail("Tick is blocked by EngineMu")
+	}
+	svc.engineMu.Unlock()
+
+	// the API is called along with the ticks
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			em.Tick()
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		_, err = api.EmitterSetIntervals(EmitIntervalsArgs{Min: &min})
+		assertar.NoError(err)
+		_, err = api.EmitterStatus()
+		assertar.NoError(err)
+	}
+	wg.Wait()
+
+	status, err := api.EmitterStatus()
+	if assertar.NoError(err) {
+		assertar.Equal("1h0m0s", status["intervals"].(map[string]interface{})["min"])
+	}
+}
@@ -0,0 +1,78 @@
+package gossip
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/gossip/extsigner"
+	"github.com/Fantom-foundation/go-lachesis/gossip/slashprotection"
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/inter/pos"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/memorydb"
+	"github.com/Fantom-foundation/go-lachesis/lachesis"
+	"github.com/Fantom-foundation/go-lachesis/lachesis/genesis"
+	"github.com/Fantom-foundation/go-lachesis/logger"
+)
+
+// testSigner calls the stand-in signer service directly.
+type testSigner struct {
+	*extsigner.Service
+	calls int
+}
+
+func (s *testSigner) SignEvent(validator common.Address, e *inter.EventHeaderData) ([]byte, error) {
+	s.calls++
+	return s.Service.SignEvent(validator, *extsigner.MarshalHeader(e), e.DataToSign())
+}
+
+func TestEmitterExternalSignerDroppedEvent(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	net := lachesis.FakeNetConfig(genesis.FakeValidators(3, big.NewInt(0), pos.StakeToBalance(1)))
+	config := DefaultConfig(net)
+	config.Emitter.EmitIntervals.Max = 0
+	config.Emitter.EmitIntervals.SelfForkProtection = 0
+	config.TxPool.Journal = ""
+
+	// the key is kept by the signer with its own slashing protection, not by the node
+	creator := net.Genesis.Alloc.Validators.Addresses()[0]
+	svc, _, err := newTestService(&node.ServiceContext{}, &config)
+	if !assertar.NoError(err) {
+		return
+	}
+	defer svc.txpool.Stop()
+	signer := &testSigner{
+		Service: extsigner.NewService(
+			mockAccountManager(net.Genesis.Alloc.Accounts, creator),
+			slashprotection.New(memorydb.New(), hash.FakeEvent())),
+	}
+	svc.SetEventSigner(signer)
+	svc.emitter = svc.makeEmitter(time.Now)
+	svc.emitter.SetValidator(creator)
+
+	// the event which fails the checks isn't signed
+	addVersion := svc.emitter.world.AddVersion
+	svc.emitter.world.AddVersion = func(e *inter.Event) *inter.Event {
+		e = addVersion(e)
+		e.Version = 1
+		return e
+	}
+	assertar.Nil(svc.emitter.EmitEvent())
+	assertar.Zero(signer.calls)
+
+	// so the signer doesn't refuse the rebuilt event at the same seq
+	svc.emitter.world.AddVersion = addVersion
+	e := svc.emitter.EmitEvent()
+	if assertar.NotNil(e) {
+		assertar.Equal(idx.Event(1), e.Seq)
+		assertar.Equal(1, signer.calls)
+	}
+}
@@ -0,0 +1,76 @@
+package gossip
+
+import (
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/inter/pos"
+	"github.com/Fantom-foundation/go-lachesis/lachesis"
+	"github.com/Fantom-foundation/go-lachesis/light"
+)
+
+// epochSealerCache is the epoch sealer of the epoch's rules.
+type epochSealerCache struct {
+	epoch  idx.Epoch
+	sealer lachesis.EpochSealer
+}
+
+// epochGasCounter is the gas used by the applied blocks of the epoch.
+type epochGasCounter struct {
+	epoch idx.Epoch
+	gas   uint64
+}
+
+// epochSealerOf returns the epoch sealer of the epoch's DAG rules.
+func (s *Service) epochSealerOf(epoch idx.Epoch, dag *lachesis.DagConfig) lachesis.EpochSealer {
+	// s.engineMu is locked here
+
+	if s.epochSealer.sealer == nil || s.epochSealer.epoch != epoch {
+		sealer, err := dag.EpochSealer()
+		if err != nil {
+			// the rules are checked on the service start
+			s.Log.Crit("Failed to make the epoch sealer", "epoch", epoch, "err", err)
+		}
+		s.epochSealer = epochSealerCache{
+			epoch:  epoch,
+			sealer: sealer,
+		}
+	}
+	return s.epochSealer.sealer
+}
+
+// epochGasUsed returns the gas used by the applied blocks of the epoch.
+// It's counted from the stored blocks after the restart or the epoch change.
+func (s *Service) epochGasUsed(epoch idx.Epoch) uint64 {
+	// s.engineMu is locked here
+
+	if s.epochGas.epoch != epoch {
+		s.epochGas.epoch = epoch
+		s.epochGas.gas = 0
+		s.store.ForEachBlockResult(epoch, func(n idx.Block, _ *light.BlockResult) bool {
+			s.epochGas.gas += s.store.GetBlock(n).GasUsed
+			return true
+		})
+	}
+	return s.epochGas.gas
+}
+
+// stakersChanged returns true if the active SFC stakers differ from the validators of the epoch.
+func (s *Service) stakersChanged(epoch idx.Epoch) bool {
+	// s.engineMu is locked here
+
+	builder := pos.NewBuilder()
+	for _, it := range s.GetActiveSfcStakers() {
+		builder.Set(it.StakerID, pos.BalanceToStake(it.Staker.CalcTotalStake()))
+	}
+	active := builder.Build()
+
+	current, _ := s.epochValidatorsAndAddresses(epoch)
+	if active.Len() != current.Len() {
+		return true
+	}
+	for _, id := range current.IDs() {
+		if active.Get(id) != current.Get(id) {
+			return true
+		}
+	}
+	return false
+}
@@ -25,6 +25,7 @@ import (
 
 	"github.com/Fantom-foundation/go-lachesis/ethapi"
 	"github.com/Fantom-foundation/go-lachesis/evmcore"
+	"github.com/Fantom-foundation/go-lachesis/finality"
 	"github.com/Fantom-foundation/go-lachesis/gossip/gasprice"
 	"github.com/Fantom-foundation/go-lachesis/hash"
 	"github.com/Fantom-foundation/go-lachesis/inter"
@@ -33,6 +34,8 @@ import (
 	"github.com/Fantom-foundation/go-lachesis/inter/sfctype"
 	"github.com/Fantom-foundation/go-lachesis/lachesis/genesis/sfc"
 	"github.com/Fantom-foundation/go-lachesis/lachesis/genesis/sfc/sfcpos"
+	"github.com/Fantom-foundation/go-lachesis/light"
+	"github.com/Fantom-foundation/go-lachesis/poset/election"
 	"github.com/Fantom-foundation/go-lachesis/topicsdb"
 	"github.com/Fantom-foundation/go-lachesis/tracing"
 )
@@ -49,7 +52,18 @@ type EthAPIBackend struct {
 
 // ChainConfig returns the active chain configuration.
 func (b *EthAPIBackend) ChainConfig() *params.ChainConfig {
-	return b.svc.config.Net.EvmChainConfig()
+	rules := b.svc.config.Net.Rules(b.svc.engine.GetEpoch())
+	return rules.EvmChainConfig()
+}
+
+// BlockChainConfig returns the chain configuration of the block's epoch,
+// so the historical blocks are re-executed under the rules they were processed with.
+func (b *EthAPIBackend) BlockChainConfig(header *evmcore.EvmHeader) *params.ChainConfig {
+	if header == nil || header.Hash == (common.Hash{}) {
+		return b.ChainConfig()
+	}
+	rules := b.svc.config.Net.Rules(hash.Event(header.Hash).Epoch())
+	return rules.EvmChainConfig()
 }
 
 func (b *EthAPIBackend) CurrentBlock() *evmcore.EvmBlock {
@@ -100,7 +114,10 @@ func (b *EthAPIBackend) StateAndHeaderByNumber(ctx context.Context, number rpc.B
 	if header == nil {
 		return nil, nil, errors.New("header not found")
 	}
-	stateDb := b.svc.app.StateDB(header.Root)
+	stateDb, err := b.state.StateAt(header.Root)
+	if err != nil {
+		return nil, nil, errors.New("state not found, it's probably pruned")
+	}
 	return stateDb, header, nil
 }
 
@@ -170,6 +187,66 @@ func (b *EthAPIBackend) GetEventHeader(ctx context.Context, shortEventID string)
 	return b.svc.store.GetEventHeader(epoch, id), nil
 }
 
+// GetForkEvidences returns the proofs of the staker's forks, ordered by epoch.
+func (b *EthAPIBackend) GetForkEvidences(ctx context.Context, stakerID idx.StakerID) ([]*inter.ForkEvidence, error) {
+	return b.svc.store.GetForkEvidences(stakerID), nil
+}
+
+// GetEpochDag returns the epoch events with their consensus marks.
+// * When epoch is -2 the DAG of latest epoch is returned.
+// * When epoch is -1 the DAG of latest sealed epoch is returned.
+func (b *EthAPIBackend) GetEpochDag(ctx context.Context, epoch rpc.BlockNumber, withArrivalTime bool) (*inter.EpochDag, error) {
+	if withArrivalTime && !b.svc.config.EventLocalTimeIndex {
+		return nil, errors.New("arrival-time index is disabled (enable EventLocalTimeIndex and re-process the DAGs)")
+	}
+	requested, err := b.epochWithDefault(ctx, epoch)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.svc.store.GetEpochDag(requested, b.svc.engine.GetEventConfirmedOn, withArrivalTime), nil
+}
+
+// GetElection returns the record of the decided election of the epoch frame.
+// * When epoch is -2 the election of latest epoch is returned.
+// * When epoch is -1 the election of latest sealed epoch is returned.
+func (b *EthAPIBackend) GetElection(ctx context.Context, epoch rpc.BlockNumber, frame idx.Frame) (*election.Record, error) {
+	if !b.svc.config.ElectionsIndex {
+		return nil, errors.New("elections index is disabled (enable ElectionsIndex and re-process the DAGs)")
+	}
+	requested, err := b.epochWithDefault(ctx, epoch)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.svc.engine.GetElection(requested, frame), nil
+}
+
+// GetFinalityProof returns the proof that the block is final.
+// * When number is -1 the proof of latest block is returned.
+func (b *EthAPIBackend) GetFinalityProof(ctx context.Context, number rpc.BlockNumber) (*finality.Proof, error) {
+	if number == rpc.PendingBlockNumber {
+		return nil, errors.New("pending block request isn't allowed")
+	}
+	var n idx.Block
+	if number == rpc.LatestBlockNumber {
+		n, _ = b.svc.engine.LastBlock()
+	} else {
+		n = idx.Block(number)
+	}
+	return b.svc.GetFinalityProof(n)
+}
+
+// GetEpochProof returns the proof of the epoch sealing for the light client.
+// * When epoch is -1 the proof of the last sealed epoch is returned.
+func (b *EthAPIBackend) GetEpochProof(ctx context.Context, epoch rpc.BlockNumber) (*light.EpochProof, error) {
+	requested, err := b.epochWithDefault(ctx, epoch)
+	if err != nil {
+		return nil, err
+	}
+	return b.svc.GetEpochProof(requested)
+}
+
 // GetConsensusTime returns event's consensus time, if event is confirmed.
 func (b *EthAPIBackend) GetConsensusTime(ctx context.Context, shortEventID string) (inter.Timestamp, error) {
 	id, err := b.GetFullEventID(shortEventID)
@@ -270,7 +347,7 @@ func (b *EthAPIBackend) GetBlock(ctx context.Context, h common.Hash) (*evmcore.E
 // GetReceiptsByNumber returns receipts by block number.
 func (b *EthAPIBackend) GetReceiptsByNumber(ctx context.Context, number rpc.BlockNumber) (types.Receipts, error) {
 	if !b.svc.config.TxIndex {
-		return nil, errors.New("transactions index is disabled (enable TxIndex and re-process the DAGs)")
+		return nil, errors.New("transactions index is disabled (enable TxIndex and run the reindex command)")
 	}
 
 	if number == rpc.PendingBlockNumber {
@@ -316,7 +393,7 @@ func (b *EthAPIBackend) GetEVM(ctx context.Context, msg evmcore.Message, state *
 	vmError := func() error { return nil }
 
 	context := evmcore.NewEVMContext(msg, header, b.state, nil)
-	config := b.ChainConfig()
+	config := b.BlockChainConfig(header)
 	return vm.NewEVM(context, state, config, vm.Config{}), vmError, nil
 }
 
@@ -363,7 +440,7 @@ func (b *EthAPIBackend) GetPoolTransaction(hash common.Hash) *types.Transaction
 
 func (b *EthAPIBackend) GetTransaction(ctx context.Context, txHash common.Hash) (*types.Transaction, uint64, uint64, error) {
 	if !b.svc.config.TxIndex {
-		return nil, 0, 0, errors.New("transactions index is disabled (enable TxIndex and re-process the DAG)")
+		return nil, 0, 0, errors.New("transactions index is disabled (enable TxIndex and run the reindex command)")
 	}
 
 	position := b.svc.store.GetTxPosition(txHash)
@@ -443,6 +520,10 @@ func (b *EthAPIBackend) RPCGasCap() *big.Int {
 	return b.svc.config.RPCGasCap
 }
 
+func (b *EthAPIBackend) RPCLogsLimit() int {
+	return b.svc.config.RPCLogsLimit
+}
+
 func (b *EthAPIBackend) EvmLogIndex() *topicsdb.Index {
 	return b.svc.app.EvmLogs()
 }
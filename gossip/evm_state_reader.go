@@ -80,6 +80,11 @@ func (r *EvmStateReader) getBlock(h hash.Event, n idx.Block, readTxs bool) *evmc
 		skipCount := 0
 		for _, id := range block.Events {
 			e := r.store.GetEvent(id)
+			if e == nil && id.Epoch() < r.store.GetFirstEpoch() {
+				// events of the block are pruned, only the header is available
+				transactions = transactions[:0]
+				break
+			}
 			if e == nil {
 				log.Crit("Event not found", "event", id.String())
 				continue
@@ -112,5 +117,5 @@ func (r *EvmStateReader) getBlock(h hash.Event, n idx.Block, readTxs bool) *evmc
 }
 
 func (r *EvmStateReader) StateAt(root common.Hash) (*state.StateDB, error) {
-	return r.app.StateDB(root), nil
+	return r.app.OpenStateDB(root)
 }
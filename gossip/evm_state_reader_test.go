@@ -127,3 +127,25 @@ func TestGetBlock(t *testing.T) {
 	assertar.Equal(txs[3].Hash(), evmBlock.Transactions[1].Hash())
 	assertar.Equal(txs[5].Hash(), evmBlock.Transactions[2].Hash())
 }
+
+func TestGetPrunedBlock(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	store := NewMemStore()
+
+	event := inter.NewEvent()
+	event.Epoch = 1
+	block := inter.NewBlock(1, 123, event.Hash(), hash.Event{}, hash.Events{event.Hash()})
+	store.SetBlock(block)
+	store.SetFirstEpoch(2)
+
+	reader := EvmStateReader{
+		store:    store,
+		engineMu: new(sync.RWMutex),
+	}
+	evmBlock := reader.GetDagBlock(block.Atropos, block.Index)
+
+	assertar.Equal(common.Hash(block.Atropos), evmBlock.Hash)
+	assertar.Empty(evmBlock.Transactions)
+}
@@ -0,0 +1,100 @@
+// Package extsigner signs the validator's events by an external signer over JSON-RPC (IPC, HTTP or WebSocket),
+// so the validator key isn't kept on the node.
+// The signer gets the structured event header along with the data to sign, so it can apply its own double-sign rules.
+package extsigner
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/Fantom-foundation/go-lachesis/inter"
+)
+
+const (
+	// Namespace is the RPC namespace of the signer API.
+	Namespace = "lachesis"
+	// SignEventMethod signs the event, the params are the validator address, the structured event header and the data to sign.
+	// The result is the signature of Keccak256(data) in the [R || S || V] format.
+	SignEventMethod = Namespace + "_signEvent"
+)
+
+// Client is the connection to the external signer.
+type Client struct {
+	rpc     *rpc.Client
+	timeout time.Duration
+}
+
+// Dial connects to the external signer at the endpoint, which is an IPC path or HTTP/WebSocket URL.
+// The signing requests fail after the timeout, if it's set.
+func Dial(endpoint string, timeout time.Duration) (*Client, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dialTimeout(timeout))
+	defer cancel()
+
+	c, err := rpc.DialContext(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(c, timeout), nil
+}
+
+func dialTimeout(timeout time.Duration) time.Duration {
+	if timeout <= 0 {
+		return 10 * time.Second
+	}
+	return timeout
+}
+
+// NewClient wraps the RPC client of the external signer.
+func NewClient(c *rpc.Client, timeout time.Duration) *Client {
+	return &Client{
+		rpc:     c,
+		timeout: timeout,
+	}
+}
+
+// Close closes the connection.
+func (c *Client) Close() {
+	c.rpc.Close()
+}
+
+// SignEvent requests the signer to sign the event by the validator key.
+// The signature is checked against the validator address.
+func (c *Client) SignEvent(validator common.Address, e *inter.EventHeaderData) ([]byte, error) {
+	ctx := context.Background()
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	data := e.DataToSign()
+	var sig hexutil.Bytes
+	err := c.rpc.CallContext(ctx, &sig, SignEventMethod, validator, MarshalHeader(e), hexutil.Bytes(data))
+	if err != nil {
+		return nil, err
+	}
+	if err := verify(validator, data, sig); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+func verify(validator common.Address, data []byte, sig []byte) error {
+	if len(sig) != crypto.SignatureLength {
+		return fmt.Errorf("wrong signature length %d", len(sig))
+	}
+	pk, err := crypto.SigToPub(crypto.Keccak256(data), sig)
+	if err != nil {
+		return err
+	}
+	if signer := crypto.PubkeyToAddress(*pk); signer != validator {
+		return fmt.Errorf("event is signed by %s, not by the validator %s", signer.Hex(), validator.Hex())
+	}
+	return nil
+}
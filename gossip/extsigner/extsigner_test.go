@@ -0,0 +1,180 @@
+package extsigner
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/gossip/slashprotection"
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/memorydb"
+	"github.com/Fantom-foundation/go-lachesis/logger"
+)
+
+func fakeEvent(seq idx.Event, lamport idx.Lamport) *inter.Event {
+	e := inter.NewEvent()
+	e.Epoch = 2
+	e.Seq = seq
+	e.Lamport = lamport
+	e.Creator = 1
+	e.Frame = 3
+	e.IsRoot = true
+	e.Parents = hash.Events{hash.FakeEvent(), hash.FakeEvent()}
+	e.ClaimedTime = 100
+	e.MedianTime = 90
+	e.GasPowerUsed = 28000
+	e.GasPowerLeft.Gas = [2]uint64{1000, 2000}
+	e.Extra = []byte{1, 2, 3}
+	e.TxHash = inter.EmptyTxHash
+	return e
+}
+
+// startSigner starts the stand-in signer with the key, it's served over IPC and HTTP.
+func startSigner(t *testing.T, key *ecdsa.PrivateKey) (ipcPath, httpURL string, stop func()) {
+	dir, err := ioutil.TempDir("", "extsigner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ks := keystore.NewKeyStore(filepath.Join(dir, "keystore"), keystore.LightScryptN, keystore.LightScryptP)
+	acc, err := ks.ImportECDSA(key, "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ks.Unlock(acc, "password"); err != nil {
+		t.Fatal(err)
+	}
+	am := accounts.NewManager(&accounts.Config{}, ks)
+	protection := slashprotection.New(memorydb.New(), hash.FakeEvent())
+	signer := NewService(am, protection)
+
+	ipcPath = filepath.Join(dir, "signer.ipc")
+	listener, handler, err := rpc.StartIPCEndpoint(ipcPath, signer.APIs())
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpSrv := httptest.NewServer(handler)
+
+	return ipcPath, httpSrv.URL, func() {
+		httpSrv.Close()
+		listener.Close()
+		handler.Stop()
+		am.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestEventHeaderJSON(t *testing.T) {
+	assertar := assert.New(t)
+
+	e := fakeEvent(5, 10)
+	raw, err := json.Marshal(MarshalHeader(&e.EventHeaderData))
+	if !assertar.NoError(err) {
+		return
+	}
+	var h EventHeader
+	if !assertar.NoError(json.Unmarshal(raw, &h)) {
+		return
+	}
+	assertar.Equal(common.Hash(e.Hash()), h.Hash)
+	assertar.Equal(e.Hash(), h.HeaderData().CalcHash())
+	assertar.Equal(e.DataToSign(), h.HeaderData().DataToSign())
+}
+
+func TestClientSignEvent(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	key, _ := crypto.GenerateKey()
+	validator := crypto.PubkeyToAddress(key.PublicKey)
+	ipcPath, httpURL, stop := startSigner(t, key)
+	defer stop()
+
+	for i, endpoint := range []string{ipcPath, httpURL} {
+		c, err := Dial(endpoint, time.Second)
+		if !assertar.NoError(err, endpoint) {
+			return
+		}
+
+		e := fakeEvent(idx.Event(i+1), idx.Lamport(i+1))
+		if !assertar.NoError(e.Sign(func(data []byte) ([]byte, error) {
+			return c.SignEvent(validator, &e.EventHeaderData)
+		}), endpoint) {
+			return
+		}
+		assertar.True(e.VerifySignature(validator), endpoint)
+
+		// signing the same event again is allowed
+		sig, err := c.SignEvent(validator, &e.EventHeaderData)
+		assertar.NoError(err, endpoint)
+		assertar.Equal(e.Sig, sig, endpoint)
+
+		// the signer refuses to double-sign
+		fork := fakeEvent(idx.Event(i+1), idx.Lamport(i+1))
+		_, err = c.SignEvent(validator, &fork.EventHeaderData)
+		assertar.Error(err, endpoint)
+
+		// unknown key
+		_, err = c.SignEvent(common.Address{1}, &fakeEvent(idx.Event(i+2), idx.Lamport(i+2)).EventHeaderData)
+		assertar.Error(err, endpoint)
+
+		c.Close()
+	}
+}
+
+func TestServiceDataMismatch(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	key, _ := crypto.GenerateKey()
+	validator := crypto.PubkeyToAddress(key.PublicKey)
+	ipcPath, _, stop := startSigner(t, key)
+	defer stop()
+
+	c, err := rpc.Dial(ipcPath)
+	if !assertar.NoError(err) {
+		return
+	}
+	defer c.Close()
+
+	// the signed data must be of the checked header
+	e := fakeEvent(1, 1)
+	other := fakeEvent(1, 1)
+	var sig hexutil.Bytes
+	err = c.Call(&sig, SignEventMethod, validator, MarshalHeader(&e.EventHeaderData), hexutil.Bytes(other.DataToSign()))
+	if assertar.Error(err) {
+		assertar.Equal(ErrDataMismatch.Error(), err.Error())
+	}
+
+	err = c.Call(&sig, SignEventMethod, validator, MarshalHeader(&e.EventHeaderData), hexutil.Bytes(e.DataToSign()))
+	assertar.NoError(err)
+}
+
+func TestVerify(t *testing.T) {
+	assertar := assert.New(t)
+
+	key, _ := crypto.GenerateKey()
+	validator := crypto.PubkeyToAddress(key.PublicKey)
+	e := fakeEvent(1, 1)
+	if !assertar.NoError(e.SignBy(key)) {
+		return
+	}
+
+	assertar.NoError(verify(validator, e.DataToSign(), e.Sig))
+	assertar.Error(verify(common.Address{1}, e.DataToSign(), e.Sig))
+	assertar.Error(verify(validator, e.DataToSign(), e.Sig[:64]))
+}
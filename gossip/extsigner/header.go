@@ -0,0 +1,96 @@
+package extsigner
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+)
+
+// EventHeader is the structured event header which is sent to the signer,
+// the fields are named as in the events RPC API.
+type EventHeader struct {
+	Version       uint32          `json:"version"`
+	Epoch         idx.Epoch       `json:"epoch"`
+	Seq           idx.Event       `json:"seq"`
+	Hash          common.Hash     `json:"hash"`
+	Frame         idx.Frame       `json:"frame"`
+	IsRoot        bool            `json:"isRoot"`
+	Creator       idx.StakerID    `json:"creator"`
+	PrevEpochHash common.Hash     `json:"prevEpochHash"`
+	Parents       []common.Hash   `json:"parents"`
+	Lamport       idx.Lamport     `json:"lamport"`
+	ClaimedTime   inter.Timestamp `json:"claimedTime"`
+	MedianTime    inter.Timestamp `json:"medianTime"`
+	Extra         hexutil.Bytes   `json:"extraData"`
+	TxHash        common.Hash     `json:"transactionsRoot"`
+	GasPowerLeft  GasPowerLeft    `json:"gasPowerLeft"`
+	GasPowerUsed  uint64          `json:"gasPowerUsed"`
+}
+
+// GasPowerLeft is the gas power left of the event.
+type GasPowerLeft struct {
+	ShortTerm uint64 `json:"shortTerm"`
+	LongTerm  uint64 `json:"longTerm"`
+}
+
+// MarshalHeader converts the event header into the structured form.
+func MarshalHeader(e *inter.EventHeaderData) *EventHeader {
+	parents := make([]common.Hash, len(e.Parents))
+	for i, p := range e.Parents {
+		parents[i] = common.Hash(p)
+	}
+	return &EventHeader{
+		Version:       e.Version,
+		Epoch:         e.Epoch,
+		Seq:           e.Seq,
+		Hash:          common.Hash(e.CalcHash()),
+		Frame:         e.Frame,
+		IsRoot:        e.IsRoot,
+		Creator:       e.Creator,
+		PrevEpochHash: e.PrevEpochHash,
+		Parents:       parents,
+		Lamport:       e.Lamport,
+		ClaimedTime:   e.ClaimedTime,
+		MedianTime:    e.MedianTime,
+		Extra:         e.Extra,
+		TxHash:        e.TxHash,
+		GasPowerLeft: GasPowerLeft{
+			ShortTerm: e.GasPowerLeft.Gas[idx.ShortTermGas],
+			LongTerm:  e.GasPowerLeft.Gas[idx.LongTermGas],
+		},
+		GasPowerUsed: e.GasPowerUsed,
+	}
+}
+
+// HeaderData converts the structured form back into the event header.
+// The Hash field isn't used, the ID is calculated from the other fields.
+func (h *EventHeader) HeaderData() *inter.EventHeaderData {
+	e := &inter.EventHeaderData{
+		Version:       h.Version,
+		Epoch:         h.Epoch,
+		Seq:           h.Seq,
+		Frame:         h.Frame,
+		IsRoot:        h.IsRoot,
+		Creator:       h.Creator,
+		PrevEpochHash: h.PrevEpochHash,
+		Parents:       make(hash.Events, len(h.Parents)),
+		Lamport:       h.Lamport,
+		ClaimedTime:   h.ClaimedTime,
+		MedianTime:    h.MedianTime,
+		TxHash:        h.TxHash,
+		Extra:         []byte(h.Extra),
+		GasPowerUsed:  h.GasPowerUsed,
+	}
+	for i, p := range h.Parents {
+		e.Parents[i] = hash.Event(p)
+	}
+	if e.Extra == nil {
+		e.Extra = []byte{}
+	}
+	e.GasPowerLeft.Gas[idx.ShortTermGas] = h.GasPowerLeft.ShortTerm
+	e.GasPowerLeft.Gas[idx.LongTermGas] = h.GasPowerLeft.LongTerm
+	return e
+}
@@ -0,0 +1,80 @@
+package extsigner
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/Fantom-foundation/go-lachesis/gossip/slashprotection"
+	"github.com/Fantom-foundation/go-lachesis/logger"
+)
+
+// MimetypeEvent is the mimetype of the events data for the accounts wallets.
+const MimetypeEvent = "application/event"
+
+// ErrDataMismatch is returned if the data to sign doesn't match the event header.
+var ErrDataMismatch = errors.New("data to sign doesn't match the event header")
+
+// Service is a signer of the events by the keys of the accounts manager.
+// It's the stand-in for an external signer, e.g. to run it on another host or in tests.
+type Service struct {
+	am         *accounts.Manager
+	protection *slashprotection.DB
+
+	logger.Instance
+}
+
+// NewService creates the signer service. The events which may conflict with the signed ones are refused
+// by the slashing-protection DB, it's disabled if nil.
+// An event is recorded as signed before the node publishes it, so the node checks the event before it's signed.
+func NewService(am *accounts.Manager, protection *slashprotection.DB) *Service {
+	s := &Service{
+		am:         am,
+		protection: protection,
+		Instance:   logger.MakeInstance(),
+	}
+	s.SetName("extsigner")
+	return s
+}
+
+// SignEvent signs the event data by the validator key, it's served as the SignEventMethod.
+func (s *Service) SignEvent(validator common.Address, header EventHeader, data hexutil.Bytes) (hexutil.Bytes, error) {
+	e := header.HeaderData()
+	if !bytes.Equal(e.DataToSign(), data) {
+		return nil, ErrDataMismatch
+	}
+
+	acc := accounts.Account{Address: validator}
+	w, err := s.am.Find(acc)
+	if err != nil {
+		return nil, err
+	}
+	if s.protection != nil {
+		if err := s.protection.CheckAndRecord(validator, e); err != nil {
+			s.Log.Warn("Refused to sign the event", "validator", validator, "epoch", e.Epoch, "seq", e.Seq, "err", err)
+			return nil, err
+		}
+	}
+	sig, err := w.SignData(acc, MimetypeEvent, data)
+	if err != nil {
+		return nil, err
+	}
+	s.Log.Debug("Signed the event", "validator", validator, "id", e.CalcHash(), "epoch", e.Epoch, "seq", e.Seq)
+	return sig, nil
+}
+
+// APIs returns the RPC API of the signer.
+func (s *Service) APIs() []rpc.API {
+	return []rpc.API{
+		{
+			Namespace: Namespace,
+			Version:   "1.0",
+			Service:   s,
+			Public:    true,
+		},
+	}
+}
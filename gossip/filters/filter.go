@@ -19,6 +19,7 @@ package filters
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -45,6 +46,12 @@ type Backend interface {
 	SubscribeLogsEvent(ch chan<- []*types.Log) notify.Subscription
 
 	EvmLogIndex() *topicsdb.Index
+	RPCLogsLimit() int // max number of the logs of a query, 0 means no limit
+}
+
+// errTooManyLogs is returned if more logs than the limit match the query.
+func errTooManyLogs(limit int) error {
+	return fmt.Errorf("query returned more than %d results", limit)
 }
 
 // Filter can be used to retrieve and filter logs.
@@ -122,21 +129,37 @@ func (f *Filter) Logs(ctx context.Context) ([]*types.Log, error) {
 		end = head
 	}
 
-	if isEmpty(f.topics) {
+	if isEmpty(f.topics) && len(f.addresses) == 0 {
 		return f.unindexedLogs(ctx, int64(end))
 	}
 
 	return f.indexedLogs(ctx, int64(end))
 }
 
-// indexedLogs returns the logs matching the filter criteria based on topics index.
+// indexedLogs returns the logs matching the filter criteria based on topics and addresses index.
 func (f *Filter) indexedLogs(ctx context.Context, end int64) ([]*types.Log, error) {
-	logs, err := f.backend.EvmLogIndex().Find(f.topics)
+	begin := f.begin
+	if begin < 0 {
+		begin = 0
+	}
+	if end < begin {
+		return nil, nil
+	}
+
+	// one more log is requested to know whether the limit is exceeded
+	limit := f.backend.RPCLogsLimit()
+	if limit > 0 {
+		limit++
+	}
+	logs, err := f.backend.EvmLogIndex().FindInRange(uint64(begin), uint64(end), f.addresses, f.topics, limit)
 	if err != nil {
 		return nil, err
 	}
+	if limit > 0 && len(logs) == limit {
+		return nil, errTooManyLogs(limit - 1)
+	}
 
-	logs = filterLogs(logs, big.NewInt(f.begin), big.NewInt(end), f.addresses, f.topics)
+	logs = filterLogs(logs, nil, nil, f.addresses, f.topics)
 
 	return logs, nil
 }
@@ -158,6 +181,9 @@ func (f *Filter) unindexedLogs(ctx context.Context, end int64) (logs []*types.Lo
 			return
 		}
 		logs = append(logs, found...)
+		if limit := f.backend.RPCLogsLimit(); limit > 0 && len(logs) > limit {
+			return nil, errTooManyLogs(limit)
+		}
 	}
 	return
 }
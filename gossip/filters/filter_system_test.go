@@ -44,6 +44,7 @@ import (
 type testBackend struct {
 	db         ethdb.Database
 	logIndex   *topicsdb.Index
+	logsLimit  int
 	blocksFeed *notify.Feed
 	txsFeed    *notify.Feed
 	logsFeed   *notify.Feed
@@ -136,6 +137,10 @@ func (b *testBackend) EvmLogIndex() *topicsdb.Index {
 	return b.logIndex
 }
 
+func (b *testBackend) RPCLogsLimit() int {
+	return b.logsLimit
+}
+
 // TestBlockSubscription tests if a block subscription returns block hashes for posted chain notify.
 // It creates multiple subscriptions:
 // - one at the start and should receive all posted chain events and a second (blockHashes)
@@ -233,6 +233,16 @@ func TestFilters(t *testing.T) {
 		t.Error("expected 0 log, got", len(logs))
 	}
 
+	filter = NewRangeFilter(backend, 2, 998, []common.Address{addr}, nil)
+
+	logs, err = filter.Logs(context.Background())
+	if err != nil {
+		t.Error(err)
+	}
+	if len(logs) != 2 {
+		t.Error("expected 2 log, got", len(logs))
+	}
+
 	failAddr := common.BytesToAddress([]byte("failmenow"))
 	filter = NewRangeFilter(backend, 0, -1, []common.Address{failAddr}, nil)
 
@@ -253,4 +263,25 @@ func TestFilters(t *testing.T) {
 	if len(logs) != 0 {
 		t.Error("expected 0 log, got", len(logs))
 	}
+
+	// the query fails if more logs than the limit match it
+	backend.logsLimit = 4
+	filter = NewRangeFilter(backend, 0, -1, []common.Address{addr}, [][]common.Hash{{hash1, hash2, hash3, hash4}})
+	logs, err = filter.Logs(context.Background())
+	if err != nil {
+		t.Error(err)
+	}
+	if len(logs) != 4 {
+		t.Error("expected 4 log, got", len(logs))
+	}
+
+	backend.logsLimit = 3
+	filter = NewRangeFilter(backend, 0, -1, []common.Address{addr}, [][]common.Hash{{hash1, hash2, hash3, hash4}})
+	if _, err = filter.Logs(context.Background()); err == nil {
+		t.Error("expected the limit error")
+	}
+	filter = NewRangeFilter(backend, 0, -1, nil, nil)
+	if _, err = filter.Logs(context.Background()); err == nil {
+		t.Error("expected the limit error of the unindexed logs")
+	}
 }
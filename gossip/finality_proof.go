@@ -0,0 +1,110 @@
+package gossip
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/hashicorp/golang-lru"
+
+	"github.com/Fantom-foundation/go-lachesis/finality"
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/inter/pos"
+)
+
+var errGenesisFinality = errors.New("genesis block has no Atropos")
+
+// finalityProofsCacheSize is the number of the built finality proofs which are kept in memory.
+const finalityProofsCacheSize = 128
+
+// finalityProofsCache keeps the built finality proofs, the proof of a final block stays valid.
+// The proofs are built one at a time, because every proof recalculates the vector clocks
+// of the epoch events up to the block election.
+type finalityProofsCache struct {
+	mu     sync.Mutex
+	proofs *lru.Cache // idx.Block -> *finality.Proof
+}
+
+// GetFinalityProof builds the proof that the block is final, see finality.Proof.
+// The proofs of the sealed epochs are available until their events are pruned.
+func (s *Service) GetFinalityProof(n idx.Block) (*finality.Proof, error) {
+	if n == 0 {
+		return nil, errGenesisFinality
+	}
+
+	s.finalityProofs.mu.Lock()
+	defer s.finalityProofs.mu.Unlock()
+	if proof, ok := s.finalityProofs.proofs.Get(n); ok {
+		return proof.(*finality.Proof), nil
+	}
+	proof, err := s.buildFinalityProof(n)
+	if err != nil {
+		return nil, err
+	}
+	s.finalityProofs.proofs.Add(n, proof)
+	return proof, nil
+}
+
+// buildFinalityProof builds the proof of the block, the election of the block frame is recalculated
+// from the stored events of the epoch.
+func (s *Service) buildFinalityProof(n idx.Block) (*finality.Proof, error) {
+	block := s.store.GetBlock(n)
+	if block == nil {
+		return nil, fmt.Errorf("block %d isn't found", n)
+	}
+	epoch := block.Atropos.Epoch()
+	atropos := s.store.GetEvent(block.Atropos)
+	if atropos == nil {
+		return nil, fmt.Errorf("Atropos of block %d isn't found, probably the epoch %d is pruned", n, epoch)
+	}
+
+	validators, addresses := s.epochValidatorsAndAddresses(epoch)
+	rules := s.config.Net.Rules(epoch)
+	builder, err := finality.NewBuilder(epoch, n, n-idx.Block(atropos.Frame), validators, addresses, rules.Dag.VectorClockConfig.EvictCheaters)
+	if err != nil {
+		return nil, err
+	}
+	decided := false
+	s.store.ForEachEventFrom(epoch, 0, func(e *inter.Event) bool {
+		decided, err = builder.Add(&e.EventHeader)
+		return err == nil && !decided
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !decided {
+		return nil, fmt.Errorf("block %d isn't provable yet, the roots which decide its frame aren't known", n)
+	}
+
+	proof, err := builder.Proof()
+	if err != nil {
+		return nil, err
+	}
+	// sanity check
+	if proof.Atropos != block.Atropos {
+		return nil, fmt.Errorf("block %d Atropos mismatches the elected one %s", n, proof.Atropos.String())
+	}
+	if err := proof.Verify(); err != nil {
+		return nil, err
+	}
+	return proof, nil
+}
+
+// epochValidatorsAndAddresses returns the validators of the epoch and their addresses in the order of SortedIDs.
+func (s *Service) epochValidatorsAndAddresses(epoch idx.Epoch) (*pos.Validators, []common.Address) {
+	builder := pos.NewBuilder()
+	byID := make(map[idx.StakerID]common.Address)
+	for _, it := range s.app.GetEpochValidators(epoch) {
+		builder.Set(it.StakerID, pos.BalanceToStake(it.Staker.CalcTotalStake()))
+		byID[it.StakerID] = it.Staker.Address
+	}
+	validators := builder.Build()
+
+	addresses := make([]common.Address, 0, validators.Len())
+	for _, id := range validators.SortedIDs() {
+		addresses = append(addresses, byID[id])
+	}
+	return validators, addresses
+}
@@ -0,0 +1,34 @@
+package gossip
+
+import (
+	"github.com/Fantom-foundation/go-lachesis/inter"
+)
+
+// recordForkEvidences stores the proofs of the new cheaters' forks, and notifies about them.
+func (s *Service) recordForkEvidences(block *inter.Block, cheaters inter.Cheaters) {
+	// s.engineMu is locked here
+
+	// not the current epoch, which is already the next one if the block seals the epoch
+	epoch := block.Atropos.Epoch()
+	for _, cheater := range cheaters {
+		if known := s.store.GetForkEvidence(cheater, epoch); known != nil {
+			if known.Block == 0 {
+				// the evidence is received from a peer before the local block
+				known.Block = block.Index
+				s.store.SetForkEvidence(known)
+			}
+			continue
+		}
+		evidence := s.store.FindForkEvidence(epoch, cheater)
+		if evidence == nil {
+			s.Log.Error("Fork evidence isn't found", "staker", cheater, "epoch", epoch)
+			continue
+		}
+		evidence.Block = block.Index
+		s.store.SetForkEvidence(evidence)
+
+		s.Log.Warn("Fork is detected", "staker", cheater, "epoch", epoch, "block", block.Index,
+			"a", evidence.A.Hash(), "b", evidence.B.Hash())
+		s.feed.newForkEvidence.Send(evidence)
+	}
+}
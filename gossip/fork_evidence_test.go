@@ -0,0 +1,56 @@
+package gossip
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/inter/pos"
+	"github.com/Fantom-foundation/go-lachesis/lachesis"
+	"github.com/Fantom-foundation/go-lachesis/lachesis/genesis"
+	"github.com/Fantom-foundation/go-lachesis/logger"
+)
+
+func TestRecordForkEvidencesOfBlockEpoch(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	net := lachesis.FakeNetConfig(genesis.FakeValidators(3, big.NewInt(0), pos.StakeToBalance(1)))
+	config := DefaultConfig(net)
+	config.TxPool.Journal = ""
+
+	svc, engine, err := newTestService(&node.ServiceContext{}, &config)
+	if !assertar.NoError(err) {
+		return
+	}
+	defer svc.txpool.Stop()
+
+	// the block is of another epoch than the current one, as it happens on the epoch sealing or replay
+	epoch := engine.GetEpoch() + 2
+	newEvent := func(seq idx.Event) *inter.Event {
+		e := fakeEvent()
+		e.Epoch = epoch
+		e.Creator = 1
+		e.Seq = seq
+		e.Lamport = idx.Lamport(seq)
+		svc.store.SetEvent(e)
+		return e
+	}
+	a := newEvent(1)
+	newEvent(1)
+
+	block := inter.NewBlock(5, 0, a.Hash(), a.Hash(), nil)
+	svc.recordForkEvidences(block, inter.Cheaters{1})
+
+	evidence := svc.store.GetForkEvidence(1, epoch)
+	if !assertar.NotNil(evidence) {
+		return
+	}
+	assertar.Equal(epoch, evidence.Epoch)
+	assertar.Equal(idx.Block(5), evidence.Block)
+	assertar.Nil(svc.store.GetForkEvidence(1, engine.GetEpoch()))
+}
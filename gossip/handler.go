@@ -16,6 +16,7 @@ import (
 	"github.com/ethereum/go-ethereum/rlp"
 
 	"github.com/Fantom-foundation/go-lachesis/eventcheck"
+	"github.com/Fantom-foundation/go-lachesis/eventcheck/epochcheck"
 	"github.com/Fantom-foundation/go-lachesis/evmcore"
 	"github.com/Fantom-foundation/go-lachesis/gossip/fetcher"
 	"github.com/Fantom-foundation/go-lachesis/gossip/ordering"
@@ -57,6 +58,7 @@ type dagNotifier interface {
 	SubscribeNewEpoch(ch chan<- idx.Epoch) notify.Subscription
 	SubscribeNewPack(ch chan<- idx.Pack) notify.Subscription
 	SubscribeNewEmitted(ch chan<- *inter.Event) notify.Subscription
+	SubscribeNewForkEvidence(ch chan<- *inter.ForkEvidence) notify.Subscription
 }
 
 type ProtocolManager struct {
@@ -78,6 +80,7 @@ type ProtocolManager struct {
 	downloader *packsdownloader.PacksDownloader
 	fetcher    *fetcher.Fetcher
 	buffer     *ordering.EventBuffer
+	checkers   *eventcheck.Checkers
 
 	store    *Store
 	engine   Consensus
@@ -90,6 +93,8 @@ type ProtocolManager struct {
 	newPacksSub      notify.Subscription
 	newEpochsCh      chan idx.Epoch
 	newEpochsSub     notify.Subscription
+	forkEvidenceCh   chan *inter.ForkEvidence
+	forkEvidenceSub  notify.Subscription
 
 	// channels for fetcher, syncer, txsyncLoop
 	newPeerCh   chan *peer
@@ -129,6 +134,7 @@ func NewProtocolManager(
 		peers:       newPeerSet(),
 		serverPool:  serverPool,
 		engineMu:    engineMu,
+		checkers:    checkers,
 		newPeerCh:   make(chan *peer),
 		noMorePeers: make(chan struct{}),
 		txsyncCh:    make(chan *txsync),
@@ -145,31 +151,33 @@ func NewProtocolManager(
 	return pm, nil
 }
 
-func (pm *ProtocolManager) makeFetcher(checkers *eventcheck.Checkers) (*fetcher.Fetcher, *ordering.EventBuffer) {
-	// checkers
-	firstCheck := func(e *inter.Event) error {
-		if err := checkers.Basiccheck.Validate(e); err != nil {
-			return err
-		}
-		if err := checkers.Epochcheck.Validate(e); err != nil {
-			return err
-		}
-		return nil
+// firstCheck runs the checks of the event which don't depend on its parents, except the heavy check.
+func firstCheck(checkers *eventcheck.Checkers, e *inter.Event) error {
+	if err := checkers.Basiccheck.Validate(e); err != nil {
+		return err
 	}
-	bufferedCheck := func(e *inter.Event, parents []*inter.EventHeaderData) error {
-		var selfParent *inter.EventHeaderData
-		if e.SelfParent() != nil {
-			selfParent = parents[0]
-		}
-		if err := checkers.Parentscheck.Validate(e, parents); err != nil {
-			return err
-		}
-		if err := checkers.Gaspowercheck.Validate(e, selfParent); err != nil {
-			return err
-		}
-		return nil
+	if err := checkers.Epochcheck.Validate(e); err != nil {
+		return err
 	}
+	return nil
+}
 
+// bufferedCheck runs the checks of the event against its parents.
+func bufferedCheck(checkers *eventcheck.Checkers, e *inter.Event, parents []*inter.EventHeaderData) error {
+	var selfParent *inter.EventHeaderData
+	if e.SelfParent() != nil {
+		selfParent = parents[0]
+	}
+	if err := checkers.Parentscheck.Validate(e, parents); err != nil {
+		return err
+	}
+	if err := checkers.Gaspowercheck.Validate(e, selfParent); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (pm *ProtocolManager) makeFetcher(checkers *eventcheck.Checkers) (*fetcher.Fetcher, *ordering.EventBuffer) {
 	// DAG callbacks
 	buffer := ordering.New(eventsBuffSize, ordering.Callback{
 
@@ -209,15 +217,19 @@ func (pm *ProtocolManager) makeFetcher(checkers *eventcheck.Checkers) (*fetcher.
 			return pm.store.GetEventHeader(id.Epoch(), id)
 		},
 
-		Check: bufferedCheck,
+		Check: func(e *inter.Event, parents []*inter.EventHeaderData) error {
+			return bufferedCheck(checkers, e, parents)
+		},
 	})
 
 	newFetcher := fetcher.New(fetcher.Callback{
 		PushEvent:      buffer.PushEvent,
 		OnlyInterested: pm.onlyInterestedEvents,
 		DropPeer:       pm.removePeer,
-		FirstCheck:     firstCheck,
-		HeavyCheck:     checkers.Heavycheck,
+		FirstCheck: func(e *inter.Event) error {
+			return firstCheck(checkers, e)
+		},
+		HeavyCheck: checkers.Heavycheck,
 	})
 	return newFetcher, buffer
 }
@@ -338,6 +350,10 @@ func (pm *ProtocolManager) Start(maxPeers int) {
 		// epoch changes
 		pm.newEpochsCh = make(chan idx.Epoch, 4)
 		pm.newEpochsSub = pm.notifier.SubscribeNewEpoch(pm.newEpochsCh)
+		// broadcast detected forks
+		pm.forkEvidenceCh = make(chan *inter.ForkEvidence, 4)
+		pm.forkEvidenceSub = pm.notifier.SubscribeNewForkEvidence(pm.forkEvidenceCh)
+		go pm.forkEvidenceBroadcastLoop()
 	}
 
 	go pm.emittedBroadcastLoop()
@@ -357,6 +373,7 @@ func (pm *ProtocolManager) Stop() {
 		pm.emittedEventsSub.Unsubscribe() // quits eventBroadcastLoop
 		pm.newPacksSub.Unsubscribe()      // quits progressBroadcastLoop
 		pm.newEpochsSub.Unsubscribe()     // quits onNewEpochLoop
+		pm.forkEvidenceSub.Unsubscribe()  // quits forkEvidenceBroadcastLoop
 	}
 
 	// Quit the sync loop.
@@ -390,6 +407,7 @@ func (pm *ProtocolManager) myProgress() PeerProgress {
 		NumOfBlocks:  blockI,
 		LastBlock:    block,
 		LastPackInfo: pm.store.GetPackInfoOrDefault(epoch, pm.store.GetPacksNumOrDefault(epoch)-1),
+		FirstEpoch:   pm.store.GetFirstEpoch(),
 	}
 }
 
@@ -431,6 +449,7 @@ func (pm *ProtocolManager) handle(p *peer) error {
 		return err
 	}
 	defer pm.removePeer(p.id)
+	go pm.delayedEventsLoop(p)
 
 	// Propagate existing transactions. new transactions appearing
 	// after this will be sent via broadcasts.
@@ -468,8 +487,8 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 		return errResp(ErrExtraStatusMsg, "uncontrolled status message")
 
 	case msg.Code == ProgressMsg:
-		var progress PeerProgress
-		if err := msg.Decode(&progress); err != nil {
+		progress, err := decodeProgressMsg(p.version, msg)
+		if err != nil {
 			return errResp(ErrDecode, "%v: %v", msg, err)
 		}
 		p.SetProgress(progress)
@@ -477,13 +496,18 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 			atomic.StoreUint32(&pm.synced, 1) // Mark initial sync done on any peer which has the same epoch
 		}
 
-		// notify downloader about new peer's epoch
-		_ = pm.downloader.RegisterPeer(packsdownloader.Peer{
-			ID:               p.id,
-			Epoch:            p.progress.Epoch,
-			RequestPack:      p.RequestPack,
-			RequestPackInfos: p.RequestPackInfos,
-		}, myEpoch)
+		if progress.FirstEpoch > myEpoch {
+			// the peer doesn't serve events of my epoch, so it's useless for syncing
+			_ = pm.downloader.UnregisterPeer(p.id)
+		} else {
+			// notify downloader about new peer's epoch
+			_ = pm.downloader.RegisterPeer(packsdownloader.Peer{
+				ID:               p.id,
+				Epoch:            p.progress.Epoch,
+				RequestPack:      p.RequestPack,
+				RequestPackInfos: p.RequestPackInfos,
+			}, myEpoch)
+		}
 		peerDwnlr = pm.downloader.Peer(p.id)
 
 		if peerDwnlr != nil && progress.LastPackInfo.Index > 0 {
@@ -527,6 +551,7 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 		for _, e := range events {
 			p.MarkEvent(e.Hash())
 		}
+		events = pm.checkClaimedTime(p, events)
 		_ = pm.fetcher.Enqueue(p.id, events, time.Now(), p.RequestEvents)
 
 	case msg.Code == EvmTxMsg:
@@ -585,6 +610,16 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 			return err
 		}
 
+		if request.Epoch < pm.store.GetFirstEpoch() {
+			// the requested epoch is pruned
+			_ = p.SendPackInfosRLP(&packInfosDataRLP{
+				Epoch:           request.Epoch,
+				TotalNumOfPacks: 0,
+				RawInfos:        []rlp.RawValue{},
+			})
+			break
+		}
+
 		packsNum, ok := pm.store.GetPacksNum(request.Epoch)
 		if !ok {
 			// no packs in the requested epoch
@@ -691,6 +726,33 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 		// Notify downloader about new pack
 		_ = peerDwnlr.NotifyPack(pack.Epoch, pack.Index, pack.IDs, time.Now(), p.RequestEvents)
 
+	case msg.Code == ForkEvidenceMsg:
+		var evidence inter.ForkEvidence
+		if err := msg.Decode(&evidence); err != nil {
+			return errResp(ErrDecode, "%v: %v", msg, err)
+		}
+		err := pm.checkers.Heavycheck.ValidateForkEvidence(&evidence)
+		if err == epochcheck.ErrNotRelevant {
+			break
+		}
+		if err != nil {
+			return errResp(ErrDecode, "%v: %v", msg, err)
+		}
+		// the block isn't signed, it's set when the cheater is observed by a local block
+		evidence.Block = 0
+
+		pm.engineMu.Lock()
+		known := pm.store.GetForkEvidence(evidence.Creator(), evidence.Epoch) != nil
+		if !known {
+			pm.store.SetForkEvidence(&evidence)
+		}
+		pm.engineMu.Unlock()
+
+		if !known {
+			pm.Log.Warn("Fork evidence is received", "staker", evidence.Creator(), "epoch", evidence.Epoch, "peer", p.id)
+			pm.BroadcastForkEvidence(&evidence)
+		}
+
 	default:
 		return errResp(ErrInvalidMsgCode, "%v", msg.Code)
 	}
@@ -854,6 +916,31 @@ func (pm *ProtocolManager) onNewEpochLoop() {
 	}
 }
 
+// BroadcastForkEvidence propagates the fork evidence to all the peers which support it.
+func (pm *ProtocolManager) BroadcastForkEvidence(evidence *inter.ForkEvidence) {
+	for _, peer := range pm.peers.List() {
+		if peer.version < lachesis64 {
+			continue
+		}
+		if err := peer.SendForkEvidence(evidence); err != nil {
+			pm.Log.Debug("Failed to send fork evidence", "peer", peer.id, "err", err)
+		}
+	}
+}
+
+// Fork evidence broadcast loop
+func (pm *ProtocolManager) forkEvidenceBroadcastLoop() {
+	for {
+		select {
+		case evidence := <-pm.forkEvidenceCh:
+			pm.BroadcastForkEvidence(evidence)
+		// Err() channel will be closed when unsubscribing.
+		case <-pm.forkEvidenceSub.Err():
+			return
+		}
+	}
+}
+
 func (pm *ProtocolManager) txBroadcastLoop() {
 	for {
 		select {
@@ -14,6 +14,7 @@ import (
 
 	"github.com/Fantom-foundation/go-lachesis/app"
 	"github.com/Fantom-foundation/go-lachesis/eventcheck"
+	"github.com/Fantom-foundation/go-lachesis/eventcheck/timecheck"
 	"github.com/Fantom-foundation/go-lachesis/hash"
 	"github.com/Fantom-foundation/go-lachesis/inter"
 	"github.com/Fantom-foundation/go-lachesis/inter/idx"
@@ -30,6 +31,16 @@ func TestGetEvents62(t *testing.T) {
 	testGetEvents(t, lachesis62)
 }
 
+func TestGetEvents63(t *testing.T) {
+	logger.SetTestMode(t)
+	testGetEvents(t, lachesis63)
+}
+
+func TestGetEvents64(t *testing.T) {
+	logger.SetTestMode(t)
+	testGetEvents(t, lachesis64)
+}
+
 func testGetEvents(t *testing.T, protocol int) {
 	assertar := assert.New(t)
 
@@ -188,7 +199,7 @@ func testBroadcastEvent(t *testing.T, totalPeers int, forcedAggressiveBroadcast
 	}
 
 	// start emitter
-	svc.emitter = svc.makeEmitter()
+	svc.emitter = svc.makeEmitter(time.Now)
 	svc.emitter.SetValidator(creator)
 
 	emittedEvents := make([]*inter.Event, 0)
@@ -272,6 +283,7 @@ func mockCheckers(epoch idx.Epoch, net *lachesis.Config, engine Consensus, s *St
 	heavyCheckReader := &HeavyCheckReader{}
 	heavyCheckReader.Addrs.Store(ReadEpochPubKeys(a, epoch))
 	gasPowerCheckReader := &GasPowerCheckReader{}
-	gasPowerCheckReader.Ctx.Store(ReadGasPowerContext(s, a, engine.GetValidators(), engine.GetEpoch(), &net.Economy))
-	return makeCheckers(net, heavyCheckReader, gasPowerCheckReader, engine, s)
+	gasPowerCheckReader.Ctx.Store(ReadGasPowerContext(s, a, engine.GetValidators(), engine.GetEpoch(), net))
+	timeCheck := timecheck.DefaultConfig()
+	return makeCheckers(net, &timeCheck, heavyCheckReader, gasPowerCheckReader, engine, s)
 }
@@ -0,0 +1,148 @@
+package gossip
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/Fantom-foundation/go-lachesis/eventcheck/timecheck"
+	"github.com/Fantom-foundation/go-lachesis/inter"
+)
+
+// checkClaimedTime returns the peer's events which pass the claimed time check, and tracks the time drifts.
+// The events which are ahead of the local time are delayed in the peer's queue, and the events which are
+// too far in the future are rejected. The peer is dropped if it keeps sending the rejected events,
+// no matter who created them, because the honest peers reject such events too rather than relay them.
+// All the received events pass the check, the events of the packs are requested and come as EventsMsg too.
+func (pm *ProtocolManager) checkClaimedTime(p *peer, events []*inter.Event) []*inter.Event {
+	var (
+		passed  = make([]*inter.Event, 0, len(events))
+		delayed []*inter.Event
+		delay   time.Duration
+	)
+	for _, e := range events {
+		drift := pm.checkers.Timecheck.Drift(e).Milliseconds()
+		eventTimeDriftMeter.Update(drift)
+		// the event isn't verified yet, so the gauges are registered only for the current validators
+		if pm.checkers.Epochcheck.Validate(e) == nil {
+			validatorTimeDriftGauge(e.Creator).Update(drift)
+		}
+
+		switch err := pm.checkers.Timecheck.Validate(e); err {
+		case nil:
+			passed = append(passed, e)
+		case timecheck.ErrTooEarly:
+			eventTimeDelayedMeter.Inc(1)
+			delayed = append(delayed, e)
+			if d := pm.checkers.Timecheck.Delay(e); d > delay {
+				delay = d
+			}
+		default:
+			eventTimeRejectedMeter.Inc(1)
+			log.Warn("Incoming event rejected", "event", e.Hash().String(), "creator", e.Creator, "peer", p.id, "err", err)
+			if p.PenalizeTime(time.Now(), pm.config.Protocol.TimePenaltyDecay) > pm.config.Protocol.MaxTimePenalties {
+				pm.removePeer(p.id)
+				return nil
+			}
+		}
+	}
+
+	if len(delayed) != 0 && !p.delayed.push(time.Now().Add(delay), delayed) {
+		eventTimeRejectedMeter.Inc(int64(len(delayed)))
+		log.Debug("Too many delayed events, dropped", "peer", p.id, "events", len(delayed))
+	}
+
+	return passed
+}
+
+// delayedEventsLoop enqueues the peer's delayed events into the fetcher, when their claimed time comes.
+func (pm *ProtocolManager) delayedEventsLoop(p *peer) {
+	for {
+		due, next := p.delayed.popDue(time.Now())
+		if len(due) != 0 {
+			_ = pm.fetcher.Enqueue(p.id, due, time.Now(), p.RequestEvents)
+		}
+
+		var (
+			timer *time.Timer
+			wait  <-chan time.Time
+		)
+		if !next.IsZero() {
+			timer = time.NewTimer(time.Until(next))
+			wait = timer.C
+		}
+		select {
+		case <-wait:
+		case <-p.delayed.wake:
+		case <-p.term:
+			return
+		case <-pm.quitSync:
+			return
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
+
+// delayedEvents is the bounded queue of the peer's events which are ahead of the local time.
+type delayedEvents struct {
+	mu      sync.Mutex
+	batches []delayedBatch
+	size    int
+	wake    chan struct{}
+}
+
+type delayedBatch struct {
+	due    time.Time
+	events []*inter.Event
+}
+
+func newDelayedEvents() delayedEvents {
+	return delayedEvents{
+		wake: make(chan struct{}, 1),
+	}
+}
+
+// push adds the events which are due at the time, returns false if the queue is full.
+func (q *delayedEvents) push(due time.Time, events []*inter.Event) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.size+len(events) > maxDelayedEvents {
+		return false
+	}
+	q.batches = append(q.batches, delayedBatch{
+		due:    due,
+		events: events,
+	})
+	q.size += len(events)
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return true
+}
+
+// popDue removes the events which are due at the time, and returns them along with the due time of the rest.
+func (q *delayedEvents) popDue(now time.Time) (due []*inter.Event, next time.Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	rest := q.batches[:0]
+	for _, b := range q.batches {
+		if !b.due.After(now) {
+			due = append(due, b.events...)
+			q.size -= len(b.events)
+			continue
+		}
+		rest = append(rest, b)
+		if next.IsZero() || b.due.Before(next) {
+			next = b.due
+		}
+	}
+	q.batches = rest
+	return due, next
+}
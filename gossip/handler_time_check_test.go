@@ -0,0 +1,112 @@
+package gossip
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/crypto"
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/logger"
+)
+
+func TestDelayedEvents(t *testing.T) {
+	assertar := assert.New(t)
+
+	q := newDelayedEvents()
+	now := time.Unix(1000, 0)
+	a := []*inter.Event{inter.NewEvent()}
+	b := []*inter.Event{inter.NewEvent(), inter.NewEvent()}
+
+	assertar.True(q.push(now.Add(2*time.Second), a))
+	assertar.True(q.push(now.Add(time.Second), b))
+	assertar.Len(q.wake, 1)
+
+	due, next := q.popDue(now)
+	assertar.Empty(due)
+	assertar.Equal(now.Add(time.Second), next)
+
+	due, next = q.popDue(now.Add(time.Second))
+	assertar.Equal(b, due)
+	assertar.Equal(now.Add(2*time.Second), next)
+
+	due, next = q.popDue(now.Add(time.Hour))
+	assertar.Equal(a, due)
+	assertar.True(next.IsZero())
+
+	// the queue is bounded
+	full := make([]*inter.Event, maxDelayedEvents)
+	assertar.True(q.push(now, full))
+	assertar.False(q.push(now, a))
+	due, _ = q.popDue(now)
+	assertar.Len(due, maxDelayedEvents)
+	assertar.True(q.push(now, a))
+}
+
+func TestPeerPenalizeTime(t *testing.T) {
+	assertar := assert.New(t)
+
+	p := &peer{}
+	now := time.Unix(1000, 0)
+	decay := time.Minute
+
+	assertar.Equal(1, p.PenalizeTime(now, decay))
+	assertar.Equal(2, p.PenalizeTime(now.Add(30*time.Second), decay))
+	assertar.Equal(3, p.PenalizeTime(now.Add(50*time.Second), decay))
+	// one penalty is forgiven per minute
+	assertar.Equal(3, p.PenalizeTime(now.Add(70*time.Second), decay))
+	assertar.Equal(2, p.PenalizeTime(now.Add(3*time.Minute), decay))
+	// all of them are forgiven
+	assertar.Equal(1, p.PenalizeTime(now.Add(time.Hour), decay))
+}
+
+func TestClaimedTimePenalties(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	pm, _ := newTestProtocolManagerMust(t, 3, 0, nil, nil)
+	defer pm.Stop()
+	p, _ := newTestPeer("peer", lachesis62, pm, true)
+	defer p.close()
+	for pm.peers.Len() < 1 { // wait until the peer is registered
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// the events are signed by the validator key, not by the node key of the peer
+	future := inter.FromUnix(time.Now().Add(time.Hour).Unix())
+	farFutureEvent := func(creator idx.StakerID, seq idx.Event) *inter.Event {
+		e := inter.NewEvent()
+		e.Epoch = 1
+		e.Seq = seq
+		e.Creator = creator
+		e.Lamport = idx.Lamport(seq)
+		e.Frame = 1
+		e.ClaimedTime = future
+		e.TxHash = inter.EmptyTxHash
+		assertar.NoError(e.Sign(func(data []byte) ([]byte, error) {
+			return ethcrypto.Sign(ethcrypto.Keccak256(data), crypto.FakeKey(int(creator)))
+		}))
+		return e
+	}
+
+	// the peer is dropped after the penalties, no matter who created the events
+	assertar.NoError(p2p.Send(p.app, EventsMsg, []*inter.Event{farFutureEvent(1000, 1)}))
+	for seq := idx.Event(1); seq < idx.Event(pm.config.Protocol.MaxTimePenalties); seq++ {
+		assertar.NoError(p2p.Send(p.app, EventsMsg, []*inter.Event{farFutureEvent(2, seq)}))
+	}
+	assertar.NoError(p2p.Send(p.app, EventsMsg, []*inter.Event{farFutureEvent(3, 1)}))
+	for deadline := time.Now().Add(5 * time.Second); pm.peers.Peer(p.peer.id) != nil && time.Now().Before(deadline); {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assertar.Nil(pm.peers.Peer(p.peer.id), "the peer isn't dropped")
+
+	// the drift gauges are registered only for the validators
+	assertar.Nil(metrics.DefaultRegistry.Get(fmt.Sprintf("event/time/drift/%d", 1000)))
+	assertar.NotNil(metrics.DefaultRegistry.Get(fmt.Sprintf("event/time/drift/%d", 2)))
+}
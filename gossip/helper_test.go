@@ -145,6 +145,7 @@ func newTestPeer(name string, version int, pm *ProtocolManager, shake bool) (*te
 				NumOfBlocks:  blockI,
 				LastBlock:    block,
 				LastPackInfo: pm.store.GetPackInfoOrDefault(epoch, pm.store.GetPacksNumOrDefault(epoch)-1),
+				FirstEpoch:   pm.store.GetFirstEpoch(),
 			}
 		)
 		tp.handshake(nil, myProgress, genesis)
@@ -168,10 +169,10 @@ func (p *testPeer) handshake(t *testing.T, progress *PeerProgress, genesis commo
 	if err := p2p.Send(p.app, EthStatusMsg, msg); err != nil {
 		t.Fatalf("status send: %v", err)
 	}
-	if err := p2p.ExpectMsg(p.app, ProgressMsg, progress); err != nil {
+	if err := p2p.ExpectMsg(p.app, ProgressMsg, progressMsgData(p.version, *progress)); err != nil {
 		t.Fatalf("progress recv: %v", err)
 	}
-	if err := p2p.Send(p.app, ProgressMsg, progress); err != nil {
+	if err := p2p.Send(p.app, ProgressMsg, progressMsgData(p.version, *progress)); err != nil {
 		t.Fatalf("progress send: %v", err)
 	}
 }
@@ -0,0 +1,56 @@
+package gossip
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/light"
+)
+
+var errGenesisEpoch = errors.New("epoch 0 is the genesis, the light client starts from the genesis checkpoint")
+
+// GetEpochProof builds the proof of the epoch sealing for the light client, see light.EpochProof.
+// The first events of the next epoch are needed, so the proof of an epoch is available
+// after the most of its validators have emitted their events in the next epoch.
+func (s *Service) GetEpochProof(epoch idx.Epoch) (*light.EpochProof, error) {
+	if epoch == 0 {
+		return nil, errGenesisEpoch
+	}
+	if !s.config.Net.Rules(epoch).LightClient {
+		return nil, fmt.Errorf("light client proofs aren't enabled in epoch %d", epoch)
+	}
+	if epoch >= s.engine.GetEpoch() {
+		return nil, fmt.Errorf("epoch %d isn't sealed yet", epoch)
+	}
+
+	proof := &light.EpochProof{
+		Epoch: epoch,
+	}
+	var last idx.Block
+	s.store.ForEachBlockResult(epoch, func(n idx.Block, r *light.BlockResult) bool {
+		proof.Blocks = append(proof.Blocks, *r)
+		last = n
+		return true
+	})
+	if len(proof.Blocks) == 0 {
+		return nil, fmt.Errorf("results of the epoch %d blocks aren't found", epoch)
+	}
+	proof.Time = s.store.GetBlock(last).Time
+	proof.NextValidators, proof.NextAddresses = s.epochValidatorsAndAddresses(epoch + 1)
+
+	validators, _ := s.epochValidatorsAndAddresses(epoch)
+	signed := validators.NewCounter()
+	s.store.ForEachEvent(epoch+1, func(e *inter.Event) bool {
+		if e.Seq == 1 && validators.Exists(e.Creator) && signed.Count(e.Creator) {
+			proof.Events = append(proof.Events, &e.EventHeader)
+		}
+		return !signed.HasQuorum()
+	})
+	if !signed.HasQuorum() {
+		return nil, fmt.Errorf("epoch %d isn't provable yet, the validators haven't signed its state in the next epoch", epoch)
+	}
+
+	return proof, nil
+}
@@ -0,0 +1,85 @@
+package gossip
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+)
+
+// logsMigrationBatch is a maximum number of log records which are moved between the DB flushes.
+const logsMigrationBatch = 10000
+
+// migrateLogsIndex moves the log records of the former topicsdb layout, it does nothing once they are moved.
+// The tx index of the records is restored from the block events, it's 0 (as it was returned before)
+// if the events are pruned.
+func (s *Service) migrateLogsIndex() {
+	var (
+		start    = time.Now()
+		reported = start
+		total    int
+		cached   *idx.Block
+		indexes  map[common.Hash]uint
+	)
+	txIndex := func(block uint64, tx common.Hash) uint {
+		n := idx.Block(block)
+		if cached == nil || *cached != n {
+			cached, indexes = &n, s.blockTxIndexes(n)
+		}
+		return indexes[tx]
+	}
+
+	for done := false; !done; {
+		var (
+			moved int
+			err   error
+		)
+		moved, done, err = s.app.EvmLogs().MigrateFormerLayout(txIndex, logsMigrationBatch)
+		if err != nil {
+			s.Log.Crit("Failed to migrate the logs index", "err", err)
+		}
+		total += moved
+		if moved == 0 {
+			continue
+		}
+		if err := s.store.Commit(nil, true); err != nil {
+			s.Log.Crit("Failed to flush the logs index", "err", err)
+		}
+		if time.Since(reported) >= reindexReportInterval {
+			s.Log.Info("Migrating the logs index", "logs", total, "elapsed", time.Since(start))
+			reported = time.Now()
+		}
+	}
+
+	if total != 0 {
+		s.Log.Info("Logs index is migrated", "logs", total, "elapsed", time.Since(start))
+	}
+}
+
+// blockTxIndexes returns the indexes of the block transactions as they are executed, the skipped ones included.
+// It's empty if the block events are pruned.
+func (s *Service) blockTxIndexes(n idx.Block) map[common.Hash]uint {
+	indexes := make(map[common.Hash]uint)
+
+	block := s.store.GetBlock(n)
+	if block == nil {
+		return indexes
+	}
+	events, missing := s.store.getBlockEvents(block)
+	if missing != nil {
+		return indexes
+	}
+
+	var i uint
+	for _, e := range events {
+		for _, tx := range e.Transactions {
+			// the repeated tx is skipped, the logs are of the first one
+			if _, ok := indexes[tx.Hash()]; !ok {
+				indexes[tx.Hash()] = i
+			}
+			i++
+		}
+	}
+	return indexes
+}
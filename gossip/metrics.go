@@ -1,15 +1,27 @@
 package gossip
 
 import (
+	"fmt"
+
 	"github.com/ethereum/go-ethereum/metrics"
 
 	"github.com/Fantom-foundation/go-lachesis/cmd/tx-storm/meta"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
 )
 
 var (
 	confirmBlocksMeter = metrics.NewRegisteredCounter("confirm/blocks", nil)
 	confirmTxnsMeter   = metrics.NewRegisteredCounter("confirm/transactions", nil)
 	txTtfMeter         = metrics.NewRegisteredHistogram("tx_ttf", nil, metrics.NewUniformSample(500))
+
+	eventTimeDriftMeter    = metrics.NewRegisteredHistogram("event/time/drift", nil, metrics.NewUniformSample(500))
+	eventTimeDelayedMeter  = metrics.NewRegisteredCounter("event/time/delayed", nil)
+	eventTimeRejectedMeter = metrics.NewRegisteredCounter("event/time/rejected", nil)
 )
 
+// validatorTimeDriftGauge returns the gauge of the last time drift of the validator's events.
+func validatorTimeDriftGauge(validator idx.StakerID) metrics.Gauge {
+	return metrics.GetOrRegisterGauge(fmt.Sprintf("event/time/drift/%d", validator), nil)
+}
+
 var txLatency = meta.NewTxs()
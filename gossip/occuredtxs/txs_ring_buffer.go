@@ -106,6 +106,12 @@ func (s *Buffer) MayBeConflicted(sender common.Address, txHash common.Hash) bool
 	return s.ring.GetTxsNum(sender) != 0
 }
 
+// SetSigner switches the signer, e.g. to the chain config of a new epoch.
+// not safe for concurrent use
+func (s *Buffer) SetSigner(txSigner types.Signer) {
+	s.txSigner = txSigner
+}
+
 // Clear is not safe for concurrent use
 func (s *Buffer) Clear() {
 	s.ring.Clear()
@@ -0,0 +1,102 @@
+package gossip
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/inter/pos"
+	"github.com/Fantom-foundation/go-lachesis/lachesis"
+	"github.com/Fantom-foundation/go-lachesis/lachesis/genesis"
+	"github.com/Fantom-foundation/go-lachesis/logger"
+)
+
+func TestPackInfosOfSealedEpoch(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	net := lachesis.FakeNetConfig(genesis.FakeValidators(3, big.NewInt(0), pos.StakeToBalance(1)))
+	net.Dag.MaxEpochBlocks = 5
+	config := DefaultConfig(net)
+	config.TxPool.Journal = ""
+
+	svc, engine, err := newTestService(&node.ServiceContext{}, &config)
+	if !assertar.NoError(err) {
+		return
+	}
+	defer svc.txpool.Stop()
+
+	// the epoch is sealed by its last event, so all of them are in the last pack
+	var (
+		size    uint32
+		count   uint32
+		parents = hash.EventsSet{}
+		events  hash.Events
+	)
+	inter.ForEachRandEvent(net.Genesis.Alloc.Validators.Validators().IDs(), 30, 3, nil, inter.ForEachEvent{
+		Process: func(e *inter.Event, name string) {
+			assertar.NoError(svc.ProcessEvent(e))
+			size += uint32(e.Size())
+			count++
+			parents.Add(e.Parents...)
+			events.Add(e.Hash())
+		},
+		Build: func(e *inter.Event, name string) *inter.Event {
+			if engine.GetEpoch() != 1 {
+				return nil
+			}
+			e.Epoch = 1
+			return engine.Prepare(e)
+		},
+	})
+	if !assertar.Equal(idx.Epoch(2), engine.GetEpoch()) {
+		return
+	}
+	var heads hash.Events
+	for _, id := range events {
+		if !parents.Contains(id) {
+			heads.Add(id)
+		}
+	}
+
+	svc.pm.Start(1000)
+	defer svc.pm.Stop()
+	p, _ := newTestPeer("peer", lachesis62, svc.pm, true)
+	defer p.close()
+
+	assertar.NoError(p2p.Send(p.app, GetPackInfosMsg, &getPackInfosData{
+		Epoch:   1,
+		Indexes: []idx.Pack{1},
+	}))
+	var infos packInfosData
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); {
+		msg, err := p.app.ReadMsg()
+		if !assertar.NoError(err) {
+			return
+		}
+		if msg.Code != PackInfosMsg {
+			_ = msg.Discard()
+			continue
+		}
+		assertar.NoError(msg.Decode(&infos))
+		break
+	}
+
+	assertar.Equal(idx.Epoch(1), infos.Epoch)
+	assertar.Equal(idx.Pack(2), infos.TotalNumOfPacks)
+	if !assertar.Len(infos.Infos, 1) {
+		return
+	}
+	info := infos.Infos[0]
+	assertar.Equal(idx.Pack(1), info.Index)
+	assertar.Equal(count, info.NumOfEvents)
+	assertar.Equal(size, info.Size)
+	assertar.ElementsMatch(heads, info.Heads)
+}
@@ -41,6 +41,10 @@ const (
 	// dropping broadcasts.
 	maxQueuedAnns = 128
 
+	// maxDelayedEvents is the maximum number of the peer's events which wait for their claimed time,
+	// the events above are dropped.
+	maxDelayedEvents = 1024
+
 	handshakeTimeout = 5 * time.Second
 )
 
@@ -69,6 +73,10 @@ type peer struct {
 
 	progress PeerProgress
 
+	timePenalties   int       // number of the peer's events rejected by the claimed time check
+	timePenaltyTime time.Time // time of the last decay of the penalties
+	delayed         delayedEvents
+
 	poolEntry *poolEntry
 
 	sync.RWMutex
@@ -81,6 +89,25 @@ func (p *peer) SetProgress(x PeerProgress) {
 	p.progress = x
 }
 
+// PenalizeTime counts the peer's event rejected by the claimed time check, and returns the number of such events.
+// One penalty is forgiven per the decay period.
+func (p *peer) PenalizeTime(now time.Time, decay time.Duration) int {
+	p.Lock()
+	defer p.Unlock()
+
+	if p.timePenalties == 0 || decay <= 0 {
+		p.timePenaltyTime = now
+	} else if forgiven := int(now.Sub(p.timePenaltyTime) / decay); forgiven > 0 {
+		if forgiven > p.timePenalties {
+			forgiven = p.timePenalties
+		}
+		p.timePenalties -= forgiven
+		p.timePenaltyTime = p.timePenaltyTime.Add(time.Duration(forgiven) * decay)
+	}
+	p.timePenalties++
+	return p.timePenalties
+}
+
 func (p *peer) InterestedIn(h hash.Event) bool {
 	e := h.Epoch()
 
@@ -115,6 +142,7 @@ func newPeer(version int, p *p2p.Peer, rw p2p.MsgReadWriter) *peer {
 		queuedProps: make(chan inter.Events, maxQueuedProps),
 		queuedAnns:  make(chan hash.Events, maxQueuedAnns),
 		term:        make(chan struct{}),
+		delayed:     newDelayedEvents(),
 	}
 }
 
@@ -274,6 +302,10 @@ func (p *peer) SendPack(pack *packData) error {
 	return p2p.Send(p.rw, PackMsg, pack)
 }
 
+func (p *peer) SendForkEvidence(evidence *inter.ForkEvidence) error {
+	return p2p.Send(p.rw, ForkEvidenceMsg, evidence)
+}
+
 // AsyncSendEvents queues an entire event for propagation to a remote peer. If
 // the peer's broadcast queue is full, the event is silently dropped.
 func (p *peer) AsyncSendEvents(events inter.Events) {
@@ -388,7 +420,40 @@ func (p *peer) Handshake(network uint64, progress PeerProgress, genesis common.H
 }
 
 func (p *peer) SendProgress(progress PeerProgress) error {
-	return p2p.Send(p.rw, ProgressMsg, progress)
+	return p2p.Send(p.rw, ProgressMsg, progressMsgData(p.version, progress))
+}
+
+// progressMsgData returns ProgressMsg payload for the protocol version.
+func progressMsgData(version int, progress PeerProgress) interface{} {
+	if version < lachesis63 {
+		return &peerProgress62{
+			Epoch:        progress.Epoch,
+			NumOfBlocks:  progress.NumOfBlocks,
+			LastPackInfo: progress.LastPackInfo,
+			LastBlock:    progress.LastBlock,
+		}
+	}
+	return &progress
+}
+
+// decodeProgressMsg decodes ProgressMsg payload of the protocol version.
+func decodeProgressMsg(version int, msg p2p.Msg) (PeerProgress, error) {
+	if version < lachesis63 {
+		var progress peerProgress62
+		if err := msg.Decode(&progress); err != nil {
+			return PeerProgress{}, err
+		}
+		return PeerProgress{
+			Epoch:        progress.Epoch,
+			NumOfBlocks:  progress.NumOfBlocks,
+			LastPackInfo: progress.LastPackInfo,
+			LastBlock:    progress.LastBlock,
+			FirstEpoch:   1,
+		}, nil
+	}
+	var progress PeerProgress
+	err := msg.Decode(&progress)
+	return progress, err
 }
 
 func (p *peer) readStatus(network uint64, status *ethStatusData, genesis common.Hash) (err error) {
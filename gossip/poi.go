@@ -31,20 +31,22 @@ func (s *Service) UpdateAddressPOI(address common.Address, senderTotalFee *big.I
 // updateUsersPOI calculates the Proof Of Importance weights for users
 func (s *Service) updateUsersPOI(block *inter.Block, evmBlock *evmcore.EvmBlock, receipts types.Receipts, totalFee *big.Int, sealEpoch bool) {
 	// User POI calculations
-	poiPeriod := PoiPeriod(block.Time, &s.config.Net.Economy)
+	rules := s.config.Net.Rules(block.Atropos.Epoch())
+	economy := rules.Economy
+	poiPeriod := PoiPeriod(block.Time, &economy)
 	s.app.AddPoiFee(poiPeriod, totalFee)
 
 	for i, tx := range evmBlock.Transactions {
 		txFee := new(big.Int).Mul(new(big.Int).SetUint64(receipts[i].GasUsed), tx.GasPrice())
 
-		signer := types.NewEIP155Signer(s.config.Net.EvmChainConfig().ChainID)
+		signer := types.NewEIP155Signer(rules.EvmChainConfig().ChainID)
 		sender, err := signer.Sender(tx)
 		if err != nil {
 			s.Log.Crit("Failed to get sender from transaction", "err", err)
 		}
 
 		senderLastTxTime := s.app.GetAddressLastTxTime(sender)
-		prevUserPoiPeriod := PoiPeriod(senderLastTxTime, &s.config.Net.Economy)
+		prevUserPoiPeriod := PoiPeriod(senderLastTxTime, &economy)
 		senderTotalFee := s.app.GetAddressFee(sender, prevUserPoiPeriod)
 
 		delegator := s.app.GetSfcDelegator(sender)
@@ -102,8 +104,9 @@ func (s *Service) UpdateStakerPOI(stakerID idx.StakerID, stakerAddress common.Ad
 // updateStakersPOI calculates the Proof Of Importance weights for stakers
 func (s *Service) updateStakersPOI(block *inter.Block, sealEpoch bool) {
 	// Stakers POI calculations
-	poiPeriod := PoiPeriod(block.Time, &s.config.Net.Economy)
-	prevBlockPoiPeriod := PoiPeriod(s.store.GetBlock(block.Index-1).Time, &s.config.Net.Economy)
+	economy := s.config.Net.Rules(block.Atropos.Epoch()).Economy
+	poiPeriod := PoiPeriod(block.Time, &economy)
+	prevBlockPoiPeriod := PoiPeriod(s.store.GetBlock(block.Index-1).Time, &economy)
 
 	if poiPeriod != prevBlockPoiPeriod {
 		for _, it := range s.GetActiveSfcStakers() {
@@ -7,6 +7,7 @@ import (
 	"github.com/Fantom-foundation/go-lachesis/inter"
 	"github.com/Fantom-foundation/go-lachesis/inter/idx"
 	"github.com/Fantom-foundation/go-lachesis/inter/pos"
+	"github.com/Fantom-foundation/go-lachesis/poset/election"
 	"github.com/Fantom-foundation/go-lachesis/vector"
 )
 
@@ -89,6 +90,30 @@ func (hook *HookedEngine) GetConsensusTime(id hash.Event) (inter.Timestamp, erro
 	return hook.engine.GetConsensusTime(id)
 }
 
+// GetEventConfirmedOn returns the frame which confirmed the event, or 0 if it isn't confirmed.
+func (hook *HookedEngine) GetEventConfirmedOn(id hash.Event) idx.Frame {
+	if hook.engine == nil {
+		return 0
+	}
+	return hook.engine.GetEventConfirmedOn(id)
+}
+
+// GetElection returns the record of the decided election, if the elections index is enabled.
+func (hook *HookedEngine) GetElection(epoch idx.Epoch, frame idx.Frame) *election.Record {
+	if hook.engine == nil {
+		return nil
+	}
+	return hook.engine.GetElection(epoch, frame)
+}
+
+// PruneSnapshots deletes the consensus states at the beginning of the epochs before the given one.
+func (hook *HookedEngine) PruneSnapshots(before idx.Epoch) {
+	if hook.engine == nil {
+		return
+	}
+	hook.engine.PruneSnapshots(before)
+}
+
 // Bootstrap restores poset's state from store.
 func (hook *HookedEngine) Bootstrap(callbacks inter.ConsensusCallbacks) {
 	if hook.engine == nil {
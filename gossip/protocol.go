@@ -16,16 +16,18 @@ import (
 // Constants to match up protocol versions and messages
 const (
 	lachesis62 = 62 // derived from eth62
+	lachesis63 = 63 // PeerProgress has FirstEpoch
+	lachesis64 = 64 // ForkEvidenceMsg
 )
 
 // protocolName is the official short name of the protocol used during capability negotiation.
 const protocolName = "lachesis"
 
 // ProtocolVersions are the supported versions of the protocol (first is primary).
-var ProtocolVersions = []uint{lachesis62}
+var ProtocolVersions = []uint{lachesis64, lachesis63, lachesis62}
 
 // protocolLengths are the number of implemented message corresponding to different protocol versions.
-var protocolLengths = map[uint]uint64{lachesis62: PackMsg + 1}
+var protocolLengths = map[uint]uint64{lachesis64: ForkEvidenceMsg + 1, lachesis63: PackMsg + 1, lachesis62: PackMsg + 1}
 
 const protocolMaxMsgSize = 10 * 1024 * 1024 // Maximum cap on the size of a protocol message
 
@@ -56,12 +58,19 @@ const (
 	// Request pack infos by epoch:pack indexes
 	GetPackInfosMsg = 0xf4
 	// Contains the requested pack infos. An answer to GetPackInfosMsg.
+	// Has no infos and zero TotalNumOfPacks if the epoch is pruned.
 	PackInfosMsg = 0xf5
 
 	// Request pack by epoch:pack index
 	GetPackMsg = 0xf6
 	// Contains the requested pack. An answer to GetPackMsg.
 	PackMsg = 0xf7
+
+	// Protocol messages belonging to lachesis/64
+
+	// Contains a proof of the validator's fork in the current epoch.
+	// It's propagated once by each node which didn't know it.
+	ForkEvidenceMsg = 0xf8
 )
 
 type errCode int
@@ -125,6 +134,17 @@ type PeerProgress struct {
 	NumOfBlocks  idx.Block
 	LastPackInfo PackInfo
 	LastBlock    hash.Event
+	// FirstEpoch is the first epoch which events are served from, the history before is pruned.
+	// It's sent since lachesis63.
+	FirstEpoch idx.Epoch
+}
+
+// peerProgress62 is PeerProgress of lachesis62.
+type peerProgress62 struct {
+	Epoch        idx.Epoch
+	NumOfBlocks  idx.Block
+	LastPackInfo PackInfo
+	LastBlock    hash.Event
 }
 
 type packInfosData struct {
@@ -27,6 +27,16 @@ func TestStatusMsgErrors62(t *testing.T) {
 	testStatusMsgErrors(t, lachesis62)
 }
 
+func TestStatusMsgErrors63(t *testing.T) {
+	logger.SetTestMode(t)
+	testStatusMsgErrors(t, lachesis63)
+}
+
+func TestStatusMsgErrors64(t *testing.T) {
+	logger.SetTestMode(t)
+	testStatusMsgErrors(t, lachesis64)
+}
+
 func testStatusMsgErrors(t *testing.T, protocol int) {
 	pm, _ := newTestProtocolManagerMust(t, 5, 5, nil, nil)
 	var (
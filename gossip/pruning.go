@@ -0,0 +1,213 @@
+package gossip
+
+import (
+	"time"
+
+	"github.com/Fantom-foundation/go-lachesis/app"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+)
+
+const (
+	// minEpochsToKeep is a minimal history of events, needed by the node itself.
+	minEpochsToKeep = idx.Epoch(2)
+	// minBlocksToKeep is a minimal history of states, needed by the node itself.
+	minBlocksToKeep = idx.Block(2)
+
+	defaultPruningInterval = 10 * time.Minute
+
+	// pruningBatch is a maximum number of records which are processed without releasing s.engineMu.
+	pruningBatch = 1000
+)
+
+// pruningLoop deletes the history behind the retention window in background.
+func (s *Service) pruningLoop() {
+	defer s.wg.Done()
+
+	interval := s.config.Pruning.Interval
+	if interval <= 0 {
+		interval = defaultPruningInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.prune()
+
+		select {
+		case <-ticker.C:
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func (s *Service) prune() {
+	start := time.Now()
+
+	if s.config.Pruning.Epochs != 0 {
+		s.pruneEvents()
+	}
+	if s.config.Pruning.Blocks != 0 {
+		s.pruneBlocks()
+	}
+
+	// flush the deletions, if nothing else does
+	s.engineMu.Lock()
+	defer s.engineMu.Unlock()
+
+	err := s.app.Commit(nil, false)
+	if err == nil {
+		err = s.store.Commit(nil, false)
+	}
+	if err != nil {
+		s.Log.Error("Failed to flush pruned history", "err", err)
+	}
+	s.Log.Debug("History is pruned", "firstEpoch", s.store.GetFirstEpoch(), "firstBlock", s.store.GetFirstBlock(), "elapsed", time.Since(start))
+}
+
+func (s *Service) stopped() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// pruneEvents deletes events, packs, tx positions and consensus states of the epochs behind the retention window.
+// Records are deleted from the beginning of the tables, so an interrupted pruning is finished by the next one.
+func (s *Service) pruneEvents() {
+	keep := s.config.Pruning.Epochs
+	if keep < minEpochsToKeep {
+		keep = minEpochsToKeep
+	}
+
+	s.engineMu.Lock()
+	epoch := s.engine.GetEpoch()
+	if epoch <= keep || epoch-keep+1 <= s.store.GetFirstEpoch() {
+		s.engineMu.Unlock()
+		return
+	}
+	first := epoch - keep + 1
+	// don't serve the history which is being deleted
+	s.store.SetFirstEpoch(first)
+	s.engineMu.Unlock()
+
+	s.pruneBatches(func() bool {
+		return s.store.PruneEvents(first, pruningBatch)
+	})
+	s.pruneBatches(func() bool {
+		return s.store.PrunePacks(first, pruningBatch)
+	})
+
+	// the consensus states are needed to replay the kept epochs and to export the captured snapshot
+	before := first
+	if snap := s.store.GetSnapshot(); snap != nil && snap.Epoch < before {
+		before = snap.Epoch
+	}
+	s.engineMu.Lock()
+	s.engine.PruneSnapshots(before)
+	s.engineMu.Unlock()
+}
+
+// pruneBlocks deletes receipts, block results and EVM states of the blocks behind the retention window.
+// Records are deleted from the beginning of the tables, so an interrupted pruning is finished by the next one.
+func (s *Service) pruneBlocks() {
+	keep := s.config.Pruning.Blocks
+	if keep < minBlocksToKeep {
+		keep = minBlocksToKeep
+	}
+
+	s.engineMu.Lock()
+	last, _ := s.engine.LastBlock()
+	if last <= keep || last-keep+1 <= s.store.GetFirstBlock() {
+		s.engineMu.Unlock()
+		return
+	}
+	first := last - keep + 1
+	s.store.SetFirstBlock(first)
+	// the results are pruned by whole epochs, because the epoch proof needs all of them
+	var firstEpoch idx.Epoch
+	if block := s.store.GetBlock(first); block != nil {
+		firstEpoch = block.Atropos.Epoch()
+	}
+	s.engineMu.Unlock()
+
+	s.pruneBatches(func() bool {
+		return s.app.PruneReceipts(first, pruningBatch)
+	})
+	s.pruneBatches(func() bool {
+		return s.store.PruneBlockResults(firstEpoch, pruningBatch)
+	})
+	s.pruneBatches(func() bool {
+		return s.store.PruneBlocksDecidedBy(first, pruningBatch)
+	})
+	s.pruneStates(first, last)
+}
+
+// pruneBatches calls prune under s.engineMu until it's done or the service is stopped.
+func (s *Service) pruneBatches(prune func() (done bool)) {
+	for done := false; !done; {
+		if s.stopped() {
+			return
+		}
+		s.engineMu.Lock()
+		done = prune()
+		s.engineMu.Unlock()
+	}
+}
+
+// pruneStates deletes EVM trie nodes and contract codes, which aren't reachable from the states
+// of the kept blocks and of the captured snapshot.
+func (s *Service) pruneStates(first, last idx.Block) {
+	marker := s.app.NewStateMarker()
+
+	// mark the kept states, the nodes are immutable so s.engineMu isn't needed
+	if snap := s.store.GetSnapshot(); snap != nil && snap.Block != nil {
+		if !s.markState(marker, snap.Block.Index) {
+			return
+		}
+	}
+	for n := first; n <= last; n++ {
+		if s.stopped() || !s.markState(marker, n) {
+			return
+		}
+	}
+
+	var (
+		from    = []byte{}
+		deleted = 0
+	)
+	for from != nil {
+		if s.stopped() {
+			return
+		}
+		s.engineMu.Lock()
+		// mark the states of blocks which are applied in the meantime
+		now, _ := s.engine.LastBlock()
+		for ; last < now; last++ {
+			if !s.markState(marker, last+1) {
+				s.engineMu.Unlock()
+				return
+			}
+		}
+		var n int
+		from, n = s.app.SweepStates(marker, from, pruningBatch)
+		deleted += n
+		s.engineMu.Unlock()
+	}
+	s.Log.Debug("EVM states are pruned", "firstBlock", first, "deleted", deleted)
+}
+
+func (s *Service) markState(marker *app.StateMarker, n idx.Block) bool {
+	block := s.store.GetBlock(n)
+	if block == nil {
+		return true
+	}
+	if err := marker.Mark(block.Root); err != nil {
+		// don't sweep anything if the kept states aren't complete
+		s.Log.Error("Failed to mark EVM state", "block", n, "err", err)
+		return false
+	}
+	return true
+}
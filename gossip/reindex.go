@@ -0,0 +1,167 @@
+package gossip
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+
+	"github.com/Fantom-foundation/go-lachesis/evmcore"
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+)
+
+const (
+	// reindexBatch is a maximum number of blocks which are reindexed without releasing s.engineMu.
+	reindexBatch = 100
+
+	reindexReportInterval = 8 * time.Second
+)
+
+var errBlockReplayMismatch = errors.New("replayed transactions don't match the block")
+
+// ReindexTxs fills TxPositions and receipts of the applied blocks, e.g. if the node was run with TxIndex disabled.
+// The progress is saved along with the indexes, so an interrupted reindexing is resumed by the next call.
+// Blocks with pruned events or EVM states are skipped. The reindexing is aborted if the replayed
+// transactions don't match a block, nothing is written for the block then.
+func (s *Service) ReindexTxs(stop <-chan struct{}) error {
+	s.engineMu.RLock()
+	last, _ := s.engine.LastBlock()
+	s.engineMu.RUnlock()
+
+	var (
+		from     = s.store.GetTxReindexProgress()
+		start    = time.Now()
+		reported = start
+		indexed  int
+		skipped  int
+	)
+	s.Log.Info("Reindexing transactions", "from", from, "last", last)
+
+	for n := from; n <= last; {
+		select {
+		case <-stop:
+			s.Log.Warn("Transactions reindexing is interrupted", "block", n, "indexed", indexed, "skipped", skipped)
+			return s.store.Commit(nil, true)
+		default:
+		}
+
+		var err error
+		s.engineMu.Lock()
+		for end := n + reindexBatch; n <= last && n < end; n++ {
+			var ok bool
+			ok, err = s.reindexBlockTxs(n)
+			if err != nil {
+				break
+			}
+			if ok {
+				indexed++
+			} else {
+				skipped++
+			}
+		}
+		s.store.SetTxReindexProgress(n)
+		if err == nil {
+			err = s.store.Commit(nil, false)
+		} else {
+			s.Log.Error("Transactions reindexing is aborted", "block", n, "err", err)
+			_ = s.store.Commit(nil, true)
+		}
+		s.engineMu.Unlock()
+		if err != nil {
+			return err
+		}
+
+		if time.Since(reported) >= reindexReportInterval {
+			s.Log.Info("Reindexing transactions", "block", n-1, "last", last, "indexed", indexed, "skipped", skipped, "elapsed", time.Since(start))
+			reported = time.Now()
+		}
+	}
+
+	s.Log.Info("Transactions are reindexed", "indexed", indexed, "skipped", skipped, "elapsed", time.Since(start))
+	return s.store.Commit(nil, true)
+}
+
+// reindexBlockTxs writes the positions and the receipts of the block transactions.
+// Returns false if they can't be restored, because the block events or the parent EVM state are pruned,
+// or errBlockReplayMismatch if the replayed transactions don't match the block.
+// Nothing is written for the block in both the cases.
+func (s *Service) reindexBlockTxs(n idx.Block) (bool, error) {
+	// s.engineMu is locked here
+
+	block := s.store.GetBlock(n)
+	if block == nil {
+		return false, nil
+	}
+	events, missing := s.store.getBlockEvents(block)
+	if missing != nil {
+		s.Log.Debug("Block transactions aren't reindexed", "block", n, "missing", missing)
+		return false, nil
+	}
+
+	receipts, err := s.replayBlockTxs(block, events)
+	if err == errBlockReplayMismatch {
+		return false, fmt.Errorf("block %d: %v", n, err)
+	}
+	if err != nil {
+		s.Log.Debug("Block transactions aren't reindexed", "block", n, "err", err)
+		return false, nil
+	}
+
+	txs, positions := blockTxPositions(block, events)
+	for _, tx := range txs {
+		position := positions[tx.Hash()]
+		s.store.SetTxPosition(tx.Hash(), &position)
+	}
+	if receipts.Len() != 0 {
+		s.app.SetReceipts(n, receipts)
+	}
+	return true, nil
+}
+
+// replayBlockTxs executes the block transactions on the parent EVM state to get their receipts.
+// The state isn't committed.
+func (s *Service) replayBlockTxs(block *inter.Block, events inter.Events) (types.Receipts, error) {
+	// s.engineMu is locked here
+
+	parent := s.store.GetBlock(block.Index - 1)
+	if parent == nil {
+		return nil, errors.New("parent block isn't found")
+	}
+	statedb, err := s.app.OpenStateDB(parent.Root)
+	if err != nil {
+		return nil, err
+	}
+
+	evmBlock := &evmcore.EvmBlock{
+		EvmHeader:    *evmcore.ToEvmHeader(block),
+		Transactions: make(types.Transactions, 0, len(events)*10),
+	}
+	for _, e := range events {
+		evmBlock.Transactions = append(evmBlock.Transactions, e.Transactions...)
+	}
+
+	rules := s.config.Net.Rules(block.Atropos.Epoch())
+	evmProcessor := evmcore.NewStateProcessor(rules.EvmChainConfig(), s.GetEvmStateReader())
+	receipts, _, gasUsed, _, skippedTxs, err := evmProcessor.Process(evmBlock, statedb, vm.Config{}, false)
+	if err == nil {
+		// the pruned trie nodes are reported here
+		err = statedb.Error()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if gasUsed != block.GasUsed || len(skippedTxs) != len(block.SkippedTxs) {
+		return nil, errBlockReplayMismatch
+	}
+	for i, skipped := range skippedTxs {
+		if skipped != block.SkippedTxs[i] {
+			return nil, errBlockReplayMismatch
+		}
+	}
+
+	return receipts, nil
+}
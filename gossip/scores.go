@@ -59,6 +59,7 @@ func (s *Service) updateOriginationScores(block *inter.Block, evmBlock *evmcore.
 // updateValidationScores calculates the validation scores
 func (s *Service) updateValidationScores(block *inter.Block, sealEpoch bool) {
 	blockTimeDiff := block.Time - s.store.GetBlock(block.Index-1).Time
+	economy := s.config.Net.Rules(block.Atropos.Epoch()).Economy
 
 	// Calc validation scores
 	for _, it := range s.GetActiveSfcStakers() {
@@ -77,8 +78,8 @@ func (s *Service) updateValidationScores(block *inter.Block, sealEpoch bool) {
 		}
 
 		missedNum := s.app.GetBlocksMissed(it.StakerID).Num
-		if missedNum > s.config.Net.Economy.BlockMissedLatency {
-			missedNum = s.config.Net.Economy.BlockMissedLatency
+		if missedNum > economy.BlockMissedLatency {
+			missedNum = economy.BlockMissedLatency
 		}
 
 		// Add score for previous blocks, but no more than FrameLatency prev blocks
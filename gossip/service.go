@@ -1,6 +1,7 @@
 package gossip
 
 import (
+	"errors"
 	"fmt"
 	"math/rand"
 	"sync"
@@ -17,6 +18,7 @@ import (
 	"github.com/ethereum/go-ethereum/p2p/discv5"
 	"github.com/ethereum/go-ethereum/p2p/enr"
 	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/hashicorp/golang-lru"
 
 	"github.com/Fantom-foundation/go-lachesis/app"
 	"github.com/Fantom-foundation/go-lachesis/ethapi"
@@ -26,10 +28,12 @@ import (
 	"github.com/Fantom-foundation/go-lachesis/eventcheck/gaspowercheck"
 	"github.com/Fantom-foundation/go-lachesis/eventcheck/heavycheck"
 	"github.com/Fantom-foundation/go-lachesis/eventcheck/parentscheck"
+	"github.com/Fantom-foundation/go-lachesis/eventcheck/timecheck"
 	"github.com/Fantom-foundation/go-lachesis/evmcore"
 	"github.com/Fantom-foundation/go-lachesis/gossip/filters"
 	"github.com/Fantom-foundation/go-lachesis/gossip/gasprice"
 	"github.com/Fantom-foundation/go-lachesis/gossip/occuredtxs"
+	"github.com/Fantom-foundation/go-lachesis/gossip/slashprotection"
 	"github.com/Fantom-foundation/go-lachesis/hash"
 	"github.com/Fantom-foundation/go-lachesis/inter"
 	"github.com/Fantom-foundation/go-lachesis/inter/idx"
@@ -42,6 +46,10 @@ const (
 	txsRingBufferSize = 20000 // Maximum number of stored hashes of included but not confirmed txs
 )
 
+var (
+	errUnknownParents = errors.New("event has unknown parents")
+)
+
 type ServiceFeed struct {
 	scope notify.SubscriptionScope
 
@@ -51,6 +59,7 @@ type ServiceFeed struct {
 	newBlock        notify.Feed
 	newTxs          notify.Feed
 	newLogs         notify.Feed
+	newForkEvidence notify.Feed
 }
 
 func (f *ServiceFeed) SubscribeNewEpoch(ch chan<- idx.Epoch) notify.Subscription {
@@ -77,6 +86,10 @@ func (f *ServiceFeed) SubscribeNewLogs(ch chan<- []*types.Log) notify.Subscripti
 	return f.scope.Track(f.newLogs.Subscribe(ch))
 }
 
+func (f *ServiceFeed) SubscribeNewForkEvidence(ch chan<- *inter.ForkEvidence) notify.Subscription {
+	return f.scope.Track(f.newForkEvidence.Subscribe(ch))
+}
+
 // Service implements go-ethereum/node.Service interface.
 type Service struct {
 	config *Config
@@ -102,10 +115,16 @@ type Service struct {
 	heavyCheckReader    HeavyCheckReader
 	gasPowerCheckReader GasPowerCheckReader
 	checkers            *eventcheck.Checkers
+	slashingProtection  *slashprotection.DB
+	eventSigner         EventSigner
+	epochSealer         epochSealerCache
+	finalityProofs      finalityProofsCache
+	snapshotRequested   uint32 // atomic, whether to capture the state at the beginning of the next epoch
 
 	// global variables. TODO refactor to pass them as arguments if possible
 	blockParticipated map[idx.StakerID]bool // validators who participated in last block
 	currentEvent      hash.Event            // current event which is being processed
+	epochGas          epochGasCounter       // gas used by the blocks of current epoch, for the epoch sealing
 
 	feed ServiceFeed
 
@@ -119,6 +138,16 @@ type Service struct {
 }
 
 func NewService(ctx *node.ServiceContext, config *Config, store *Store, engine Consensus, app *app.Store) (*Service, error) {
+	if _, err := config.Net.Dag.EpochSealer(); err != nil {
+		return nil, err
+	}
+	if err := config.Net.ValidateUpgrades(); err != nil {
+		return nil, err
+	}
+	if err := config.Emitter.CheckParentsStrategy(); err != nil {
+		return nil, err
+	}
+
 	svc := &Service{
 		config: config,
 
@@ -137,6 +166,10 @@ func NewService(ctx *node.ServiceContext, config *Config, store *Store, engine C
 		Instance: logger.MakeInstance(),
 	}
 
+	svc.finalityProofs.proofs, _ = lru.New(finalityProofsCacheSize)
+
+	svc.migrateLogsIndex()
+
 	// wrap engine
 	svc.engine = &HookedEngine{
 		engine:       engine,
@@ -159,11 +192,12 @@ func NewService(ctx *node.ServiceContext, config *Config, store *Store, engine C
 		config.TxPool.Journal = ctx.ResolvePath(config.TxPool.Journal)
 	}
 	svc.txpool = evmcore.NewTxPool(config.TxPool, config.Net.EvmChainConfig(), stateReader)
+	svc.switchEvmChainConfig(svc.engine.GetEpoch())
 
 	// create checkers
-	svc.heavyCheckReader.Addrs.Store(ReadEpochPubKeys(svc.app, svc.engine.GetEpoch()))                                                                     // read pub keys of current epoch from disk
-	svc.gasPowerCheckReader.Ctx.Store(ReadGasPowerContext(svc.store, svc.app, svc.engine.GetValidators(), svc.engine.GetEpoch(), &svc.config.Net.Economy)) // read gaspower check data from disk
-	svc.checkers = makeCheckers(&svc.config.Net, &svc.heavyCheckReader, &svc.gasPowerCheckReader, svc.engine, svc.store)
+	svc.heavyCheckReader.Addrs.Store(ReadEpochPubKeys(svc.app, svc.engine.GetEpoch()))                                                             // read pub keys of current epoch from disk
+	svc.gasPowerCheckReader.Ctx.Store(ReadGasPowerContext(svc.store, svc.app, svc.engine.GetValidators(), svc.engine.GetEpoch(), &svc.config.Net)) // read gaspower check data from disk
+	svc.checkers = makeCheckers(&svc.config.Net, &svc.config.TimeCheck, &svc.heavyCheckReader, &svc.gasPowerCheckReader, svc.engine, svc.store)
 
 	// create protocol manager
 	var err error
@@ -177,7 +211,7 @@ func NewService(ctx *node.ServiceContext, config *Config, store *Store, engine C
 }
 
 // makeCheckers builds event checkers
-func makeCheckers(net *lachesis.Config, heavyCheckReader *HeavyCheckReader, gasPowerCheckReader *GasPowerCheckReader, engine Consensus, store *Store) *eventcheck.Checkers {
+func makeCheckers(net *lachesis.Config, timeCheck *timecheck.Config, heavyCheckReader *HeavyCheckReader, gasPowerCheckReader *GasPowerCheckReader, engine Consensus, store *Store) *eventcheck.Checkers {
 	// create signatures checker
 	ledgerID := net.EvmChainConfig().ChainID
 	heavyCheck := heavycheck.NewDefault(&net.Dag, heavyCheckReader, types.NewEIP155Signer(ledgerID))
@@ -186,17 +220,18 @@ func makeCheckers(net *lachesis.Config, heavyCheckReader *HeavyCheckReader, gasP
 	gaspowerCheck := gaspowercheck.New(gasPowerCheckReader)
 
 	return &eventcheck.Checkers{
-		Basiccheck:    basiccheck.New(&net.Dag),
+		Basiccheck:    basiccheck.New(net),
 		Epochcheck:    epochcheck.New(&net.Dag, engine),
 		Parentscheck:  parentscheck.New(&net.Dag),
 		Heavycheck:    heavyCheck,
 		Gaspowercheck: gaspowerCheck,
+		Timecheck:     timecheck.New(timeCheck, time.Now),
 	}
 }
 
-func (s *Service) makeEmitter() *Emitter {
+func (s *Service) makeEmitter(now func() time.Time) *Emitter {
 	// randomize event time to decrease peak load, and increase chance of catching double instances of validator
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	r := rand.New(rand.NewSource(now().UnixNano()))
 	emitterCfg := s.config.Emitter // copy data
 	emitterCfg.EmitIntervals = *emitterCfg.EmitIntervals.RandomizeEmitTime(r)
 
@@ -209,6 +244,9 @@ func (s *Service) makeEmitter() *Emitter {
 			App:         s.app,
 			Txpool:      s.txpool,
 			OccurredTxs: s.occurredTxs,
+
+			SlashingProtection: s.slashingProtection,
+			Signer:             s.eventSigner,
 			OnEmitted: func(emitted *inter.Event) {
 				// s.engineMu is locked here
 
@@ -242,10 +280,52 @@ func (s *Service) makeEmitter() *Emitter {
 				return e
 			},
 			Checkers: s.checkers,
+			Now:      now,
 		},
 	)
 }
 
+// SetSlashingProtection sets the slashing-protection DB of the validator keys, it must be called before the emitter start.
+// The DB is closed by the service on stop.
+func (s *Service) SetSlashingProtection(db *slashprotection.DB) {
+	s.slashingProtection = db
+}
+
+// SetEventSigner sets the external signer of the validator's events, it must be called before the emitter start.
+func (s *Service) SetEventSigner(signer EventSigner) {
+	s.eventSigner = signer
+}
+
+// ManualEmitter creates the emitter of the configured validator without starting the events emission,
+// events are emitted only by the Tick or EmitEvent calls. The emitter reads the time from the now clock.
+// Intended for the in-process simulations, when the service isn't started.
+func (s *Service) ManualEmitter(now func() time.Time) *Emitter {
+	em := s.makeEmitter(now)
+	em.SetValidator(s.config.Emitter.Validator)
+	s.setEmitter(em)
+	return em
+}
+
+// setEmitter sets the emitter, which is called by the consensus callbacks and the APIs.
+func (s *Service) setEmitter(em *Emitter) {
+	s.engineMu.Lock()
+	defer s.engineMu.Unlock()
+	s.emitter = em
+}
+
+// getEmitter returns the emitter, or nil if it isn't created yet.
+// The consensus callbacks use s.emitter directly, because s.engineMu is locked there.
+func (s *Service) getEmitter() *Emitter {
+	s.engineMu.RLock()
+	defer s.engineMu.RUnlock()
+	return s.emitter
+}
+
+// StopManual stops the routines of the service which isn't started, e.g. the one with the ManualEmitter.
+func (s *Service) StopManual() {
+	s.txpool.Stop()
+}
+
 // Protocols returns protocols the service can communicate on.
 func (s *Service) Protocols() []p2p.Protocol {
 	protos := make([]p2p.Protocol, len(ProtocolVersions))
@@ -276,6 +356,16 @@ func (s *Service) APIs() []rpc.API {
 			Version:   "1.0",
 			Service:   s.netRPCService,
 			Public:    true,
+		}, {
+			Namespace: "admin",
+			Version:   "1.0",
+			Service:   NewPrivateEmitterAPI(s),
+			Public:    false,
+		}, {
+			Namespace: "admin",
+			Version:   "1.0",
+			Service:   NewPrivateSnapshotAPI(s),
+			Public:    false,
 		},
 	}...)
 
@@ -304,9 +394,15 @@ func (s *Service) Start(srv *p2p.Server) error {
 
 	s.serverPool.start(srv, s.Topic)
 
-	s.emitter = s.makeEmitter()
-	s.emitter.SetValidator(s.config.Emitter.Validator)
-	s.emitter.StartEventEmission()
+	em := s.makeEmitter(time.Now)
+	em.SetValidator(s.config.Emitter.Validator)
+	s.setEmitter(em)
+	em.StartEventEmission()
+
+	if s.config.Pruning.Epochs != 0 || s.config.Pruning.Blocks != 0 {
+		s.wg.Add(1)
+		go s.pruningLoop()
+	}
 
 	return nil
 }
@@ -314,7 +410,7 @@ func (s *Service) Start(srv *p2p.Server) error {
 // Stop method invoked when the node terminates the service.
 func (s *Service) Stop() error {
 	close(s.done)
-	s.emitter.StopEventEmission()
+	s.getEmitter().StopEventEmission()
 	s.pm.Stop()
 	s.wg.Wait()
 	s.feed.scope.Close()
@@ -327,10 +423,56 @@ func (s *Service) Stop() error {
 	if err != nil {
 		return err
 	}
-	return s.store.Commit(nil, true)
+	err = s.store.Commit(nil, true)
+	if err != nil {
+		return err
+	}
+
+	if s.slashingProtection != nil {
+		return s.slashingProtection.Close()
+	}
+	return nil
 }
 
 // AccountManager return node's account manager
 func (s *Service) AccountManager() *accounts.Manager {
 	return s.node.AccountManager
 }
+
+// ImportEvent checks the event by the same checkers as the events received from peers, and connects it to the DAG.
+// Intended for the offline events import, when events don't come through the fetcher. Parents must be connected first.
+func (s *Service) ImportEvent(e *inter.Event) error {
+	if s.store.HasEventHeader(e.Hash()) {
+		return eventcheck.ErrAlreadyConnectedEvent
+	}
+	if err := firstCheck(s.checkers, e); err != nil {
+		return err
+	}
+	if err := s.checkers.Heavycheck.Validate(e); err != nil {
+		return err
+	}
+
+	s.engineMu.Lock()
+	defer s.engineMu.Unlock()
+
+	parents := make([]*inter.EventHeaderData, len(e.Parents))
+	for i, p := range e.Parents {
+		parents[i] = s.store.GetEventHeader(p.Epoch(), p)
+		if parents[i] == nil {
+			return errUnknownParents
+		}
+	}
+	if err := bufferedCheck(s.checkers, e, parents); err != nil {
+		return err
+	}
+	return s.engine.ProcessEvent(e)
+}
+
+// ProcessEvent connects the event, which is already validated, to the DAG.
+// Event order matter: parents first.
+func (s *Service) ProcessEvent(e *inter.Event) error {
+	s.engineMu.Lock()
+	defer s.engineMu.Unlock()
+
+	return s.engine.ProcessEvent(e)
+}
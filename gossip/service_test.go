@@ -2,10 +2,30 @@ package gossip
 
 import (
 	"crypto/rand"
+	"math/big"
+	"testing"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/p2p/enode"
+	ethparams "github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/app"
+	"github.com/Fantom-foundation/go-lachesis/eventcheck"
+	"github.com/Fantom-foundation/go-lachesis/eventcheck/heavycheck"
+	"github.com/Fantom-foundation/go-lachesis/eventcheck/parentscheck"
+	"github.com/Fantom-foundation/go-lachesis/evmcore"
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/inter/pos"
+	"github.com/Fantom-foundation/go-lachesis/lachesis"
+	"github.com/Fantom-foundation/go-lachesis/lachesis/genesis"
+	"github.com/Fantom-foundation/go-lachesis/logger"
+	"github.com/Fantom-foundation/go-lachesis/poset"
 )
 
 func getProtocol(svc node.Service, name string, version uint) *p2p.Protocol {
@@ -34,3 +54,139 @@ func runProtocol(name string, p *p2p.Protocol, errc chan<- error) *p2p.MsgPipeRW
 
 	return net
 }
+
+// newTestService creates the service over the in-memory stores with the genesis of config.Net.
+func newTestService(ctx *node.ServiceContext, config *Config) (*Service, *poset.Poset, error) {
+	app := app.NewMemStore()
+	state, _, err := app.ApplyGenesis(&config.Net, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	store := NewMemStore()
+	genesisAtropos, genesisEvmState, _, err := store.ApplyGenesis(&config.Net, state)
+	if err != nil {
+		return nil, nil, err
+	}
+	engineStore := poset.NewMemStore()
+	err = engineStore.ApplyGenesis(&config.Net.Genesis, genesisAtropos, genesisEvmState)
+	if err != nil {
+		return nil, nil, err
+	}
+	engine := poset.New(config.Net.Dag, engineStore, store)
+
+	svc, err := NewService(ctx, config, store, engine, app)
+	return svc, engine, err
+}
+
+func TestServiceEvmChainConfigUpgrade(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	net := lachesis.FakeNetConfig(genesis.FakeValidators(3, big.NewInt(0), pos.StakeToBalance(1)))
+	net.Dag.MaxEpochBlocks = 5
+	evm := *ethparams.AllEthashProtocolChanges
+	evm.IstanbulBlock = nil
+	net.Upgrades = append(net.Upgrades, lachesis.Upgrade{Name: "evm", Epoch: 2, Evm: &evm})
+	config := DefaultConfig(net)
+	config.TxPool.Journal = ""
+
+	svc, engine, err := newTestService(&node.ServiceContext{}, &config)
+	if !assertar.NoError(err) {
+		return
+	}
+	defer svc.txpool.Stop()
+	assertar.NotNil(svc.txpool.ChainConfig().IstanbulBlock)
+
+	inter.ForEachRandEvent(net.Genesis.Alloc.Validators.Validators().IDs(), 30, 3, nil, inter.ForEachEvent{
+		Process: func(e *inter.Event, name string) {
+			assertar.NoError(svc.ProcessEvent(e))
+		},
+		Build: func(e *inter.Event, name string) *inter.Event {
+			if engine.GetEpoch() != 1 {
+				return nil
+			}
+			e.Epoch = 1
+			return engine.Prepare(e)
+		},
+	})
+	if !assertar.Equal(idx.Epoch(2), engine.GetEpoch()) {
+		return
+	}
+	assertar.Nil(svc.txpool.ChainConfig().IstanbulBlock)
+	assertar.Equal(net.EvmChainConfig().ChainID, svc.txpool.ChainConfig().ChainID)
+
+	// the blocks before the upgrade are re-executed under the rules of their epoch
+	backend := &EthAPIBackend{svc: svc}
+	assertar.Nil(backend.ChainConfig().IstanbulBlock)
+	block := svc.store.GetBlock(1)
+	if !assertar.NotNil(block) {
+		return
+	}
+	assertar.Equal(idx.Epoch(1), block.Atropos.Epoch())
+	assertar.NotNil(backend.BlockChainConfig(evmcore.ToEvmHeader(block)).IstanbulBlock)
+}
+
+func TestServiceImportEvent(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	net := lachesis.FakeNetConfig(genesis.FakeValidators(3, big.NewInt(0), pos.StakeToBalance(1)))
+	config := DefaultConfig(net)
+	config.Emitter.EmitIntervals.Max = 0
+	config.Emitter.EmitIntervals.SelfForkProtection = 0
+	config.TxPool.Journal = ""
+
+	// emit the events by the first validator
+	creator := net.Genesis.Alloc.Validators.Addresses()[0]
+	source, _, err := newTestService(&node.ServiceContext{
+		AccountManager: mockAccountManager(net.Genesis.Alloc.Accounts, creator),
+	}, &config)
+	if !assertar.NoError(err) {
+		return
+	}
+	defer source.txpool.Stop()
+	source.emitter = source.makeEmitter(time.Now)
+	source.emitter.SetValidator(creator)
+	events := make([]*inter.Event, 3)
+	for i := range events {
+		events[i] = source.emitter.EmitEvent()
+		if !assertar.NotNil(events[i]) {
+			return
+		}
+	}
+
+	svc, engine, err := newTestService(&node.ServiceContext{}, &config)
+	if !assertar.NoError(err) {
+		return
+	}
+	defer svc.txpool.Stop()
+
+	// bad signature
+	badSig := *events[0]
+	badSig.Sig = common.CopyBytes(events[0].Sig)
+	badSig.Sig[0]++
+	assertar.Equal(heavycheck.ErrWrongEventSig, svc.ImportEvent(&badSig))
+
+	// unknown parent
+	assertar.Equal(errUnknownParents, svc.ImportEvent(events[1]))
+
+	assertar.NoError(svc.ImportEvent(events[0]))
+	assertar.Equal(eventcheck.ErrAlreadyConnectedEvent, svc.ImportEvent(events[0]))
+
+	// bad parent, the event is signed correctly
+	badParent := *events[1]
+	badParent.Lamport++
+	key := net.Genesis.Alloc.Accounts[creator].PrivateKey
+	assertar.NoError(badParent.Sign(func(data []byte) ([]byte, error) {
+		return ethcrypto.Sign(ethcrypto.Keccak256(data), key)
+	}))
+	assertar.Equal(parentscheck.ErrWrongLamport, svc.ImportEvent(&badParent))
+
+	assertar.NoError(svc.ImportEvent(events[1]))
+	assertar.NoError(svc.ImportEvent(events[2]))
+	for _, e := range events {
+		assertar.True(svc.store.HasEventHeader(e.Hash()))
+	}
+	assertar.False(svc.store.HasEventHeader(badParent.Hash()))
+	assertar.Equal(idx.Epoch(1), engine.GetEpoch())
+}
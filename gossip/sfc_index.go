@@ -73,12 +73,13 @@ func (s *Service) calcRewardWeights(stakers []sfctype.SfcStakerAndID, _epochDura
 		pois = append(pois, poi)
 	}
 
+	txRewardPoiImpact := s.config.Net.Rules(s.engine.GetEpoch()).Economy.TxRewardPoiImpact
 	txRewardWeights = make([]*big.Int, 0, len(stakers))
 	for i := range stakers {
 		// txRewardWeight = ({origination score} + {CONST} * {PoI}) * {validation score}
 		// origination score is roughly proportional to {validation score} * {stake}, so the whole formula is roughly
 		// {stake} * {validation score} ^ 2
-		poiWithRatio := new(big.Int).Mul(pois[i], s.config.Net.Economy.TxRewardPoiImpact)
+		poiWithRatio := new(big.Int).Mul(pois[i], txRewardPoiImpact)
 		poiWithRatio.Div(poiWithRatio, lachesis.PercentUnit)
 
 		txRewardWeight := new(big.Int).Add(originationScores[i], poiWithRatio)
@@ -111,12 +112,14 @@ func (s *Service) calcRewardWeights(stakers []sfctype.SfcStakerAndID, _epochDura
 
 // getRewardPerSec returns current rewardPerSec, depending on config and value provided by SFC
 func (s *Service) getRewardPerSec() *big.Int {
-	rewardPerSecond := s.app.GetSfcConstants(s.engine.GetEpoch() - 1).BaseRewardPerSec
+	epoch := s.engine.GetEpoch()
+	economy := s.config.Net.Rules(epoch).Economy
+	rewardPerSecond := s.app.GetSfcConstants(epoch - 1).BaseRewardPerSec
 	if rewardPerSecond == nil || rewardPerSecond.Sign() == 0 {
-		rewardPerSecond = s.config.Net.Economy.InitialRewardPerSecond
+		rewardPerSecond = economy.InitialRewardPerSecond
 	}
-	if rewardPerSecond.Cmp(s.config.Net.Economy.MaxRewardPerSecond) > 0 {
-		rewardPerSecond = s.config.Net.Economy.MaxRewardPerSecond
+	if rewardPerSecond.Cmp(economy.MaxRewardPerSecond) > 0 {
+		rewardPerSecond = economy.MaxRewardPerSecond
 	}
 	return new(big.Int).Set(rewardPerSecond)
 }
@@ -332,7 +335,7 @@ func (s *Service) processSfc(block *inter.Block, receipts types.Receipts, blockF
 			}
 
 			gotMissed := s.app.GetBlocksMissed(it.StakerID)
-			badMissed := s.config.Net.Economy.OfflinePenaltyThreshold
+			badMissed := s.config.Net.Rules(epoch).Economy.OfflinePenaltyThreshold
 			if gotMissed.Num >= badMissed.BlocksNum && gotMissed.Period >= inter.Timestamp(badMissed.Period) {
 				// write into DB
 				it.Staker.Status |= sfctype.OfflineBit
@@ -362,6 +365,9 @@ func (s *Service) processSfc(block *inter.Block, receipts types.Receipts, blockF
 			if _, ok := cheatersSet[it.StakerID]; ok {
 				continue // don't give reward to cheaters
 			}
+			if staker := s.app.GetSfcStaker(it.StakerID); staker != nil && staker.HasFork() {
+				continue // don't give reward to cheaters, which may be not observed by the last Atropos if they're evicted
+			}
 			if baseRewardWeight.Sign() == 0 && txRewardWeight.Sign() == 0 {
 				continue // don't give reward to offline validators
 			}
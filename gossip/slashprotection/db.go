@@ -0,0 +1,169 @@
+// Package slashprotection keeps the highest events signed by the validator keys,
+// and refuses to sign the events which may be a double-sign.
+// The data is independent from the chain DBs, and it's moved with the keys by the JSON export/import.
+package slashprotection
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/kvdb"
+)
+
+var (
+	// ErrOldEpoch is returned if the event's epoch is lower than the epoch of a signed event.
+	ErrOldEpoch = errors.New("event epoch is lower than the epoch of a signed event")
+	// ErrNotHigher is returned if the event may conflict with a signed event of the epoch.
+	ErrNotHigher = errors.New("event seq or lamport isn't higher than of a signed event")
+)
+
+// Record is the highest signed event of a validator key.
+type Record struct {
+	Epoch   idx.Epoch
+	Seq     idx.Event
+	Lamport idx.Lamport
+	// Event is the ID of the signed event, it's zero if the record is merged from conflicting ones
+	Event hash.Event
+}
+
+// DB is the slashing-protection database, keyed by validator address.
+type DB struct {
+	db      kvdb.KeyValueStore
+	genesis hash.Event
+
+	mu sync.Mutex
+}
+
+// New makes the slashing-protection DB of the network with the genesis.
+func New(db kvdb.KeyValueStore, genesis hash.Event) *DB {
+	return &DB{
+		db:      db,
+		genesis: genesis,
+	}
+}
+
+// Close closes the underlying DB.
+func (p *DB) Close() error {
+	return p.db.Close()
+}
+
+// Get returns the highest signed event of the validator, or nil if there's none.
+func (p *DB) Get(validator common.Address) (*Record, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.get(validator)
+}
+
+func (p *DB) get(validator common.Address) (*Record, error) {
+	raw, err := p.db.Get(validator.Bytes())
+	if err != nil || raw == nil {
+		return nil, err
+	}
+	r := &Record{}
+	if err := rlp.DecodeBytes(raw, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (p *DB) set(validator common.Address, r *Record) error {
+	raw, err := rlp.EncodeToBytes(r)
+	if err != nil {
+		return err
+	}
+	return p.db.Put(validator.Bytes(), raw)
+}
+
+// CheckAndRecord checks that the event doesn't conflict with the events signed by the validator,
+// and records it as signed. It must be called before the signed event is published.
+// Signing the same event again is allowed.
+func (p *DB) CheckAndRecord(validator common.Address, e *inter.EventHeaderData) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	id := e.CalcHash()
+	prev, err := p.get(validator)
+	if err != nil {
+		return err
+	}
+	if prev != nil {
+		if err := prev.check(e, id); err != nil {
+			return err
+		}
+	}
+
+	return p.set(validator, &Record{
+		Epoch:   e.Epoch,
+		Seq:     e.Seq,
+		Lamport: e.Lamport,
+		Event:   id,
+	})
+}
+
+// Observe records the event of the validator which is signed elsewhere, e.g. by the primary node of a hot-standby,
+// so the events which may conflict with it are refused.
+func (p *DB) Observe(validator common.Address, e *inter.EventHeaderData) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	observed := Record{
+		Epoch:   e.Epoch,
+		Seq:     e.Seq,
+		Lamport: e.Lamport,
+		Event:   e.CalcHash(),
+	}
+	prev, err := p.get(validator)
+	if err != nil {
+		return err
+	}
+	if prev != nil {
+		observed = prev.merge(observed)
+	}
+	return p.set(validator, &observed)
+}
+
+func (r *Record) check(e *inter.EventHeaderData, id hash.Event) error {
+	if e.Epoch < r.Epoch {
+		return fmt.Errorf("%v: %d < %d", ErrOldEpoch, e.Epoch, r.Epoch)
+	}
+	if e.Epoch > r.Epoch {
+		return nil
+	}
+	if e.Seq == r.Seq && id == r.Event {
+		return nil
+	}
+	if e.Seq <= r.Seq || e.Lamport <= r.Lamport {
+		return fmt.Errorf("%v: seq %d, lamport %d, signed seq %d, lamport %d in epoch %d",
+			ErrNotHigher, e.Seq, e.Lamport, r.Seq, r.Lamport, r.Epoch)
+	}
+	return nil
+}
+
+// merge returns the record which protects from the both records.
+func (r Record) merge(o Record) Record {
+	if r.Epoch != o.Epoch {
+		if o.Epoch > r.Epoch {
+			return o
+		}
+		return r
+	}
+	res := r
+	if o.Seq > res.Seq {
+		res.Seq = o.Seq
+		res.Event = o.Event
+	} else if o.Seq == res.Seq && o.Event != res.Event {
+		res.Event = hash.ZeroEvent
+	}
+	if o.Lamport > res.Lamport {
+		res.Lamport = o.Lamport
+	}
+	return res
+}
@@ -0,0 +1,158 @@
+package slashprotection
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/memorydb"
+	"github.com/Fantom-foundation/go-lachesis/logger"
+)
+
+func fakeEvent(epoch idx.Epoch, seq idx.Event, lamport idx.Lamport, extra string) *inter.EventHeaderData {
+	e := inter.NewEvent()
+	e.Epoch = epoch
+	e.Seq = seq
+	e.Lamport = lamport
+	e.Extra = []byte(extra)
+	return &e.EventHeaderData
+}
+
+func TestCheckAndRecord(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	p := New(memorydb.New(), hash.FakeEvent())
+	me := common.Address{1}
+	other := common.Address{2}
+
+	r, err := p.Get(me)
+	assertar.NoError(err)
+	assertar.Nil(r)
+
+	e := fakeEvent(2, 5, 10, "")
+	assertar.NoError(p.CheckAndRecord(me, e))
+	// the same event may be signed again
+	assertar.NoError(p.CheckAndRecord(me, e))
+
+	r, err = p.Get(me)
+	assertar.NoError(err)
+	assertar.Equal(&Record{Epoch: 2, Seq: 5, Lamport: 10, Event: e.CalcHash()}, r)
+
+	// conflicts
+	assertar.Error(p.CheckAndRecord(me, fakeEvent(2, 5, 10, "fork")))
+	assertar.Error(p.CheckAndRecord(me, fakeEvent(2, 4, 11, "")))
+	assertar.Error(p.CheckAndRecord(me, fakeEvent(2, 6, 10, "")))
+	assertar.Error(p.CheckAndRecord(me, fakeEvent(1, 100, 100, "")))
+
+	// the records are per validator
+	assertar.NoError(p.CheckAndRecord(other, fakeEvent(1, 1, 1, "")))
+
+	assertar.NoError(p.CheckAndRecord(me, fakeEvent(2, 6, 11, "")))
+	// new epoch
+	assertar.NoError(p.CheckAndRecord(me, fakeEvent(3, 1, 1, "")))
+	assertar.Error(p.CheckAndRecord(me, fakeEvent(2, 7, 12, "")))
+}
+
+func TestExportImport(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	genesis := hash.FakeEvent()
+	a := common.Address{1}
+	b := common.Address{2}
+	c := common.Address{3}
+
+	src := New(memorydb.New(), genesis)
+	assertar.NoError(src.CheckAndRecord(a, fakeEvent(2, 5, 10, "")))
+	assertar.NoError(src.CheckAndRecord(b, fakeEvent(3, 7, 20, "")))
+	assertar.NoError(src.CheckAndRecord(c, fakeEvent(1, 1, 1, "")))
+
+	dst := New(memorydb.New(), genesis)
+	assertar.NoError(dst.CheckAndRecord(a, fakeEvent(2, 3, 15, "")))
+	assertar.NoError(dst.CheckAndRecord(b, fakeEvent(4, 1, 1, "")))
+
+	buf := &bytes.Buffer{}
+	assertar.NoError(src.Export(buf, a, b))
+	n, err := dst.Import(bytes.NewReader(buf.Bytes()))
+	assertar.NoError(err)
+	assertar.Equal(2, n)
+
+	// merged with the higher seq and lamport
+	r, err := dst.Get(a)
+	assertar.NoError(err)
+	assertar.Equal(idx.Epoch(2), r.Epoch)
+	assertar.Equal(idx.Event(5), r.Seq)
+	assertar.Equal(idx.Lamport(15), r.Lamport)
+	assertar.Error(dst.CheckAndRecord(a, fakeEvent(2, 5, 16, "")))
+	assertar.Error(dst.CheckAndRecord(a, fakeEvent(2, 6, 15, "")))
+	assertar.NoError(dst.CheckAndRecord(a, fakeEvent(2, 6, 16, "")))
+
+	// the higher epoch is kept
+	r, err = dst.Get(b)
+	assertar.NoError(err)
+	assertar.Equal(idx.Epoch(4), r.Epoch)
+
+	// not exported
+	r, err = dst.Get(c)
+	assertar.NoError(err)
+	assertar.Nil(r)
+
+	// another genesis
+	_, err = New(memorydb.New(), hash.FakeEvent()).Import(bytes.NewReader(buf.Bytes()))
+	assertar.Error(err)
+
+	// another version
+	var data Interchange
+	assertar.NoError(json.Unmarshal(buf.Bytes(), &data))
+	data.Version++
+	raw, err := json.Marshal(&data)
+	assertar.NoError(err)
+	_, err = dst.Import(bytes.NewReader(raw))
+	assertar.Error(err)
+}
+
+func TestMergeConflicting(t *testing.T) {
+	assertar := assert.New(t)
+
+	x := Record{Epoch: 1, Seq: 5, Lamport: 5, Event: hash.FakeEvent()}
+	y := Record{Epoch: 1, Seq: 5, Lamport: 6, Event: hash.FakeEvent()}
+	m := x.merge(y)
+	assertar.Equal(idx.Event(5), m.Seq)
+	assertar.Equal(idx.Lamport(6), m.Lamport)
+	assertar.Equal(hash.ZeroEvent, m.Event)
+
+	// none of the conflicting events may be signed again
+	e := fakeEvent(1, 5, 7, "")
+	assertar.Error(m.check(e, e.CalcHash()))
+}
+
+func TestObserve(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	p := New(memorydb.New(), hash.FakeEvent())
+	me := common.Address{1}
+
+	assertar.NoError(p.CheckAndRecord(me, fakeEvent(2, 5, 10, "")))
+	// the event is signed by another node with the key
+	observed := fakeEvent(2, 6, 12, "primary")
+	assertar.NoError(p.Observe(me, observed))
+
+	assertar.Error(p.CheckAndRecord(me, fakeEvent(2, 6, 13, "")))
+	assertar.Error(p.CheckAndRecord(me, fakeEvent(2, 7, 12, "")))
+	assertar.NoError(p.CheckAndRecord(me, fakeEvent(2, 7, 13, "")))
+
+	// the events of the previous epochs don't lower the record
+	assertar.NoError(p.Observe(me, fakeEvent(1, 100, 100, "")))
+	r, err := p.Get(me)
+	assertar.NoError(err)
+	assertar.Equal(idx.Epoch(2), r.Epoch)
+	assertar.Equal(idx.Event(7), r.Seq)
+}
@@ -0,0 +1,117 @@
+package slashprotection
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+)
+
+// InterchangeVersion is the current version of the JSON export format.
+const InterchangeVersion = 1
+
+type (
+	// Interchange is the JSON export format of the slashing-protection data.
+	Interchange struct {
+		Version    uint                   `json:"version"`
+		Genesis    common.Hash            `json:"genesis"`
+		Validators []InterchangeValidator `json:"validators"`
+	}
+
+	// InterchangeValidator is the highest signed event of a validator key.
+	InterchangeValidator struct {
+		Address common.Address `json:"address"`
+		Epoch   idx.Epoch      `json:"epoch"`
+		Seq     idx.Event      `json:"seq"`
+		Lamport idx.Lamport    `json:"lamport"`
+		Event   common.Hash    `json:"event"`
+	}
+)
+
+// Export writes the records of all the validators, or of the given ones only.
+func (p *DB) Export(w io.Writer, validators ...common.Address) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	filter := make(map[common.Address]bool, len(validators))
+	for _, addr := range validators {
+		filter[addr] = true
+	}
+
+	data := Interchange{
+		Version:    InterchangeVersion,
+		Genesis:    common.Hash(p.genesis),
+		Validators: []InterchangeValidator{},
+	}
+	it := p.db.NewIterator()
+	defer it.Release()
+	for it.Next() {
+		addr := common.BytesToAddress(it.Key())
+		if len(filter) != 0 && !filter[addr] {
+			continue
+		}
+		r := &Record{}
+		if err := rlp.DecodeBytes(it.Value(), r); err != nil {
+			return err
+		}
+		data.Validators = append(data.Validators, InterchangeValidator{
+			Address: addr,
+			Epoch:   r.Epoch,
+			Seq:     r.Seq,
+			Lamport: r.Lamport,
+			Event:   common.Hash(r.Event),
+		})
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(&data)
+}
+
+// Import merges the exported records, so the DB protects from the events signed on the both sides.
+// It returns the number of the imported validators.
+func (p *DB) Import(r io.Reader) (int, error) {
+	var data Interchange
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&data); err != nil {
+		return 0, err
+	}
+	if data.Version != InterchangeVersion {
+		return 0, fmt.Errorf("unsupported slashing-protection data version %d", data.Version)
+	}
+	if hash.Event(data.Genesis) != p.genesis {
+		return 0, fmt.Errorf("slashing-protection data is for another genesis %s", data.Genesis.Hex())
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, v := range data.Validators {
+		imported := Record{
+			Epoch:   v.Epoch,
+			Seq:     v.Seq,
+			Lamport: v.Lamport,
+			Event:   hash.Event(v.Event),
+		}
+		prev, err := p.get(v.Address)
+		if err != nil {
+			return 0, err
+		}
+		if prev != nil {
+			imported = prev.merge(imported)
+		}
+		if err := p.set(v.Address, &imported); err != nil {
+			return 0, err
+		}
+	}
+	return len(data.Validators), nil
+}
@@ -0,0 +1,79 @@
+package gossip
+
+import (
+	"fmt"
+
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/inter/sfctype"
+)
+
+// Snapshot is a gossip state at the beginning of an epoch.
+type Snapshot struct {
+	Epoch idx.Epoch
+	// Block is the last block of the previous epoch
+	Block *inter.Block
+	// EpochStats are stats of the previous epoch
+	EpochStats *sfctype.EpochStats
+	// DirtyEpochStats are stats of the epoch
+	DirtyEpochStats *sfctype.EpochStats
+	// LastHeaders are the last headers from validators in the previous epoch
+	LastHeaders []*inter.EventHeaderData
+}
+
+// captureSnapshot saves the gossip and app states at the beginning of the epoch.
+func (s *Service) captureSnapshot(epoch idx.Epoch) {
+	// s.engineMu is locked here
+
+	lastBlock, _ := s.engine.LastBlock()
+
+	snap := &Snapshot{
+		Epoch:           epoch,
+		Block:           s.store.GetBlock(lastBlock),
+		EpochStats:      s.store.GetEpochStats(epoch - 1),
+		DirtyEpochStats: s.store.GetDirtyEpochStats(),
+	}
+	for _, header := range s.store.GetLastHeaders(epoch - 1) {
+		snap.LastHeaders = append(snap.LastHeaders, header)
+	}
+
+	s.store.SetSnapshot(snap)
+	s.app.CaptureSnapshot(epoch)
+}
+
+// SetSnapshot stores the last captured gossip state.
+func (s *Store) SetSnapshot(snap *Snapshot) {
+	s.set(s.table.Snapshot, []byte("c"), snap)
+}
+
+// GetSnapshot returns the last captured gossip state.
+func (s *Store) GetSnapshot() *Snapshot {
+	w, _ := s.get(s.table.Snapshot, []byte("c"), &Snapshot{}).(*Snapshot)
+	return w
+}
+
+// ApplySnapshot writes the gossip state at the beginning of an epoch.
+// Genesis must be already applied.
+func (s *Store) ApplySnapshot(snap *Snapshot) error {
+	if snap.Block == nil || snap.EpochStats == nil || snap.DirtyEpochStats == nil {
+		return fmt.Errorf("incomplete snapshot")
+	}
+	if s.GetBlock(snap.Block.Index) != nil {
+		return fmt.Errorf("block %d already exists", snap.Block.Index)
+	}
+
+	s.SetBlock(snap.Block)
+	s.SetBlockIndex(snap.Block.Atropos, snap.Block.Index)
+	s.SetEpochStats(snap.Epoch-1, snap.EpochStats)
+	s.SetDirtyEpochStats(snap.DirtyEpochStats)
+	for _, header := range snap.LastHeaders {
+		s.AddLastHeader(snap.Epoch-1, header)
+	}
+	s.SetSnapshot(snap)
+
+	// the history before the snapshot isn't known
+	s.SetFirstEpoch(snap.Epoch)
+	s.SetFirstBlock(snap.Block.Index + 1)
+
+	return nil
+}
@@ -48,6 +48,21 @@ type Store struct {
 		DecisiveEvents  kvdb.KeyValueStore `table:"9"`
 		EventLocalTimes kvdb.KeyValueStore `table:"!"`
 
+		// light client tables
+		BlockResults kvdb.KeyValueStore `table:"B"`
+
+		// slashing tables
+		ForkEvidences kvdb.KeyValueStore `table:"F"`
+
+		// state snapshot tables
+		Snapshot kvdb.KeyValueStore `table:"S"`
+
+		// history pruning tables
+		History kvdb.KeyValueStore `table:"H"`
+
+		// reindexing tables
+		Reindex kvdb.KeyValueStore `table:"I"`
+
 		TmpDbs kvdb.KeyValueStore `table:"T"`
 	}
 
@@ -218,6 +233,13 @@ func (s *Store) dropTable(it ethdb.Iterator, t kvdb.KeyValueStore) {
 	}
 }
 
+// deleteRange deletes the table's keys in the range [start, limit). A nil limit is the end of the table.
+func (s *Store) deleteRange(t kvdb.KeyValueStore, start, limit []byte) {
+	if err := kvdb.DeleteRange(t, start, limit); err != nil {
+		s.Log.Crit("Failed to erase key range", "err", err)
+	}
+}
+
 func (s *Store) makeCache(size int) *lru.Cache {
 	if size <= 0 {
 		return nil
@@ -0,0 +1,60 @@
+package gossip
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/light"
+)
+
+func blockResultKey(epoch idx.Epoch, n idx.Block) []byte {
+	return append(epoch.Bytes(), n.Bytes()...)
+}
+
+// SetBlockResult stores the result of the block execution, which the block app hash is made of.
+func (s *Store) SetBlockResult(epoch idx.Epoch, n idx.Block, r *light.BlockResult) {
+	s.set(s.table.BlockResults, blockResultKey(epoch, n), r)
+}
+
+// ForEachBlockResult iterates the results of the epoch blocks in order.
+func (s *Store) ForEachBlockResult(epoch idx.Epoch, onResult func(n idx.Block, r *light.BlockResult) bool) {
+	it := s.table.BlockResults.NewIteratorWithPrefix(epoch.Bytes())
+	defer it.Release()
+	for it.Next() {
+		r := &light.BlockResult{}
+		if err := rlp.DecodeBytes(it.Value(), r); err != nil {
+			s.Log.Crit("Failed to decode rlp", "err", err)
+		}
+		if !onResult(idx.BytesToBlock(it.Key()[len(epoch.Bytes()):]), r) {
+			break
+		}
+	}
+}
+
+// PruneBlockResults deletes up to limit block results of the epochs before the specified one.
+// Returns true if there are no such results left.
+func (s *Store) PruneBlockResults(before idx.Epoch, limit int) bool {
+	results := 0
+	end := before.Bytes()
+
+	it := s.table.BlockResults.NewIterator()
+	for it.Next() {
+		if idx.BytesToEpoch(it.Key()[:len(before.Bytes())]) >= before {
+			break
+		}
+		if results == limit {
+			end = common.CopyBytes(it.Key())
+			break
+		}
+		results++
+	}
+	it.Release()
+
+	// the keys are prefixed by epoch, so the results are deleted by a range
+	if results != 0 {
+		s.deleteRange(s.table.BlockResults, nil, end)
+	}
+
+	return results < limit
+}
@@ -0,0 +1,72 @@
+package gossip
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/app"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/flushable"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/memorydb"
+	"github.com/Fantom-foundation/go-lachesis/light"
+	"github.com/Fantom-foundation/go-lachesis/logger"
+)
+
+func TestStoreBlockResultsApartFromReceipts(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	// the stores share the main DB
+	dbs := flushable.NewSyncedPool(memorydb.NewProducer(""))
+	store := NewStore(dbs, LiteStoreConfig())
+	adb := app.NewStore(dbs, app.LiteStoreConfig())
+
+	for n := idx.Block(1); n <= 4; n++ {
+		store.SetBlockResult(idx.Epoch(n/3), n, &light.BlockResult{Root: common.Hash{byte(n)}})
+		adb.SetReceipts(n, types.Receipts{{Status: types.ReceiptStatusSuccessful, Logs: []*types.Log{}}})
+	}
+
+	// all the receipts are pruned in one batch, the block results aren't walked and are kept
+	assertar.True(adb.PruneReceipts(10, 5))
+	for n := idx.Block(1); n <= 4; n++ {
+		assertar.Nil(adb.GetReceipts(n), n)
+	}
+
+	var results []idx.Block
+	for epoch := idx.Epoch(0); epoch <= 1; epoch++ {
+		store.ForEachBlockResult(epoch, func(n idx.Block, r *light.BlockResult) bool {
+			assertar.Equal(common.Hash{byte(n)}, r.Root)
+			results = append(results, n)
+			return true
+		})
+	}
+	assertar.Equal([]idx.Block{1, 2, 3, 4}, results)
+}
+
+func TestStorePruneBlockResults(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	store := cachedStore()
+	for n := idx.Block(1); n <= 9; n++ {
+		store.SetBlockResult(idx.Epoch(1+n/3), n, &light.BlockResult{Root: common.Hash{byte(n)}})
+	}
+	count := func(epoch idx.Epoch) (num int) {
+		store.ForEachBlockResult(epoch, func(idx.Block, *light.BlockResult) bool {
+			num++
+			return true
+		})
+		return
+	}
+
+	assertar.False(store.PruneBlockResults(3, 4))
+	assertar.Equal(0, count(1))
+	assertar.Equal(1, count(2))
+	assertar.True(store.PruneBlockResults(3, 4))
+	assertar.Equal(0, count(2))
+	assertar.Equal(3, count(3))
+	assertar.Equal(1, count(4))
+}
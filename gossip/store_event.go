@@ -84,6 +84,38 @@ func (s *Store) ForEachEvent(epoch idx.Epoch, onEvent func(event *inter.Event) b
 	}
 }
 
+// ForEachEventFrom iterates over the epoch events in the order of lamport, starting from the lamport.
+func (s *Store) ForEachEventFrom(epoch idx.Epoch, lamport idx.Lamport, onEvent func(event *inter.Event) bool) {
+	it := s.table.Events.NewIteratorWithStart(append(epoch.Bytes(), lamport.Bytes()...))
+	defer it.Release()
+	for it.Next() {
+		if hash.BytesToEvent(it.Key()).Epoch() != epoch {
+			return
+		}
+		event := &inter.Event{}
+		err := rlp.DecodeBytes(it.Value(), event)
+		if err != nil {
+			s.Log.Crit("Failed to decode event", "err", err)
+		}
+
+		if !onEvent(event) {
+			return
+		}
+	}
+}
+
+// ForEachEventRLP iterates over serialized events, starting from the epoch, in the order of (epoch, lamport).
+// The order is topological, i.e. parents always go before their children.
+func (s *Store) ForEachEventRLP(from idx.Epoch, onEvent func(id hash.Event, event rlp.RawValue) bool) {
+	it := s.table.Events.NewIteratorWithStart(from.Bytes())
+	defer it.Release()
+	for it.Next() {
+		if !onEvent(hash.BytesToEvent(it.Key()), it.Value()) {
+			return
+		}
+	}
+}
+
 func (s *Store) FindEventHashes(epoch idx.Epoch, lamport idx.Lamport, hashPrefix []byte) hash.Events {
 	prefix := bytes.NewBuffer(epoch.Bytes())
 	prefix.Write(lamport.Bytes())
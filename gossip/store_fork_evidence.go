@@ -0,0 +1,65 @@
+package gossip
+
+import (
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+)
+
+func forkEvidenceKey(staker idx.StakerID, epoch idx.Epoch) []byte {
+	return append(staker.Bytes(), epoch.Bytes()...)
+}
+
+// SetForkEvidence stores the proof of the staker's fork in the epoch.
+func (s *Store) SetForkEvidence(f *inter.ForkEvidence) {
+	s.set(s.table.ForkEvidences, forkEvidenceKey(f.Creator(), f.Epoch), f)
+}
+
+// GetForkEvidence returns the stored proof of the staker's fork in the epoch.
+func (s *Store) GetForkEvidence(staker idx.StakerID, epoch idx.Epoch) *inter.ForkEvidence {
+	f, _ := s.get(s.table.ForkEvidences, forkEvidenceKey(staker, epoch), &inter.ForkEvidence{}).(*inter.ForkEvidence)
+	return f
+}
+
+// GetForkEvidences returns the stored proofs of the staker's forks, ordered by epoch.
+func (s *Store) GetForkEvidences(staker idx.StakerID) []*inter.ForkEvidence {
+	res := make([]*inter.ForkEvidence, 0, 1)
+
+	it := s.table.ForkEvidences.NewIteratorWithPrefix(staker.Bytes())
+	defer it.Release()
+	for it.Next() {
+		f := &inter.ForkEvidence{}
+		if err := rlp.DecodeBytes(it.Value(), f); err != nil {
+			s.Log.Crit("Failed to decode rlp", "err", err)
+		}
+		res = append(res, f)
+	}
+
+	return res
+}
+
+// FindForkEvidence looks for two events of the staker with the same sequence number among the epoch events.
+// Returns nil if the staker has no forks in the epoch.
+func (s *Store) FindForkEvidence(epoch idx.Epoch, staker idx.StakerID) *inter.ForkEvidence {
+	var (
+		bySeq    = make(map[idx.Event]*inter.Event)
+		evidence *inter.ForkEvidence
+	)
+	s.ForEachEvent(epoch, func(e *inter.Event) bool {
+		if e.Creator != staker {
+			return true
+		}
+		if prev, ok := bySeq[e.Seq]; ok && prev.Hash() != e.Hash() {
+			evidence = &inter.ForkEvidence{
+				Epoch: epoch,
+				A:     &prev.EventHeader,
+				B:     &e.EventHeader,
+			}
+			return false
+		}
+		bySeq[e.Seq] = e
+		return true
+	})
+	return evidence
+}
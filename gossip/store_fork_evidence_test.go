@@ -0,0 +1,71 @@
+package gossip
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/logger"
+)
+
+func TestStoreForkEvidence(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	store := cachedStore()
+
+	newEvent := func(epoch idx.Epoch, creator idx.StakerID, seq idx.Event) *inter.Event {
+		e := fakeEvent()
+		e.Epoch = epoch
+		e.Creator = creator
+		e.Seq = seq
+		e.Lamport = idx.Lamport(seq)
+		store.SetEvent(e)
+		return e
+	}
+	newEvent(2, 1, 1)
+	a := newEvent(2, 1, 2)
+	newEvent(2, 2, 1)
+	newEvent(2, 2, 2)
+	b := newEvent(2, 1, 2)
+
+	assertar.Nil(store.FindForkEvidence(2, 2), "honest staker")
+	assertar.Nil(store.FindForkEvidence(1, 1), "other epoch")
+
+	f := store.FindForkEvidence(2, 1)
+	if !assertar.NotNil(f) {
+		return
+	}
+	assertar.Equal(idx.Epoch(2), f.Epoch)
+	assertar.ElementsMatch([]*inter.EventHeader{&a.EventHeader, &b.EventHeader}, []*inter.EventHeader{f.A, f.B})
+
+	assertar.Nil(store.GetForkEvidence(1, 2))
+	assertar.Empty(store.GetForkEvidences(1))
+
+	f.Block = 10
+	store.SetForkEvidence(f)
+	other := &inter.ForkEvidence{Epoch: 1, Block: 5, A: f.A, B: f.B}
+	store.SetForkEvidence(other)
+
+	// compare the signed events, because the decoded headers have no cached hashes
+	sameEvidence := func(exp, got *inter.ForkEvidence) {
+		if !assertar.NotNil(got) {
+			return
+		}
+		assertar.Equal(exp.Epoch, got.Epoch)
+		assertar.Equal(exp.Block, got.Block)
+		assertar.Equal(exp.A.CalcHash(), got.A.CalcHash())
+		assertar.Equal(exp.B.CalcHash(), got.B.CalcHash())
+		assertar.Equal(exp.A.Sig, got.A.Sig)
+		assertar.Equal(exp.B.Sig, got.B.Sig)
+	}
+	sameEvidence(f, store.GetForkEvidence(1, 2))
+	all := store.GetForkEvidences(1)
+	if assertar.Len(all, 2) {
+		sameEvidence(other, all[0])
+		sameEvidence(f, all[1])
+	}
+	assertar.Empty(store.GetForkEvidences(2))
+}
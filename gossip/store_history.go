@@ -0,0 +1,162 @@
+package gossip
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+)
+
+// SetFirstEpoch stores the first epoch, which events and packs are kept from.
+func (s *Store) SetFirstEpoch(epoch idx.Epoch) {
+	if err := s.table.History.Put([]byte("e"), epoch.Bytes()); err != nil {
+		s.Log.Crit("Failed to put key-value", "err", err)
+	}
+}
+
+// GetFirstEpoch returns the first epoch, which events and packs are kept from.
+func (s *Store) GetFirstEpoch() idx.Epoch {
+	buf, err := s.table.History.Get([]byte("e"))
+	if err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	}
+	if buf == nil {
+		return 1
+	}
+	return idx.BytesToEpoch(buf)
+}
+
+// SetFirstBlock stores the first block, which receipts and EVM states are kept from.
+func (s *Store) SetFirstBlock(n idx.Block) {
+	if err := s.table.History.Put([]byte("b"), n.Bytes()); err != nil {
+		s.Log.Crit("Failed to put key-value", "err", err)
+	}
+}
+
+// GetFirstBlock returns the first block, which receipts and EVM states are kept from.
+func (s *Store) GetFirstBlock() idx.Block {
+	buf, err := s.table.History.Get([]byte("b"))
+	if err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	}
+	if buf == nil {
+		return 1
+	}
+	return idx.BytesToBlock(buf)
+}
+
+// PruneEvents deletes up to limit events of the epochs before the specified one,
+// along with their local times and positions of their transactions.
+// Returns true if there are no such events left.
+func (s *Store) PruneEvents(before idx.Epoch, limit int) bool {
+	events := make([]*inter.Event, 0, limit)
+
+	it := s.table.Events.NewIterator()
+	for it.Next() && len(events) < limit {
+		if hash.BytesToEvent(it.Key()).Epoch() >= before {
+			break
+		}
+		e := &inter.Event{}
+		if err := rlp.DecodeBytes(it.Value(), e); err != nil {
+			s.Log.Crit("Failed to decode rlp", "err", err)
+		}
+		events = append(events, e)
+	}
+	it.Release()
+
+	for _, e := range events {
+		id := e.Hash()
+		for _, tx := range e.Transactions {
+			if pos := s.GetTxPosition(tx.Hash()); pos != nil && pos.Event == id {
+				s.DelTxPosition(tx.Hash())
+			}
+		}
+		if err := s.table.Events.Delete(id.Bytes()); err != nil {
+			s.Log.Crit("Failed to erase key-value", "err", err)
+		}
+		if err := s.table.EventLocalTimes.Delete(id.Bytes()); err != nil {
+			s.Log.Crit("Failed to erase key-value", "err", err)
+		}
+		if s.cache.Events != nil {
+			s.cache.Events.Remove(id)
+		}
+	}
+
+	return len(events) < limit
+}
+
+// PrunePacks deletes the packs of up to limit epochs before the specified one.
+// Returns true if there are no such packs left.
+func (s *Store) PrunePacks(before idx.Epoch, limit int) bool {
+	epochs := 0
+	end := before
+
+	it := s.table.PacksNum.NewIterator()
+	for it.Next() {
+		epoch := idx.BytesToEpoch(it.Key())
+		if epoch >= before {
+			break
+		}
+		if epochs == limit {
+			end = epoch
+			break
+		}
+		epochs++
+	}
+	it.Release()
+
+	if epochs == 0 {
+		return true
+	}
+
+	// the keys are prefixed by epoch, so the packs are deleted by ranges
+	s.deleteRange(s.table.Packs, nil, end.Bytes())
+	s.deleteRange(s.table.PackInfos, nil, end.Bytes())
+	s.deleteRange(s.table.PacksNum, nil, end.Bytes())
+
+	if s.cache.PackInfos != nil {
+		s.cache.PackInfos.Purge()
+	}
+
+	return epochs < limit
+}
+
+// PruneBlocksDecidedBy deletes up to limit records about events which decided the blocks before the specified one.
+// Returns true if there are no such records left.
+func (s *Store) PruneBlocksDecidedBy(before idx.Block, limit int) bool {
+	records := 0
+	end := before.Bytes()
+
+	it := s.table.DecisiveEvents.NewIterator()
+	for it.Next() {
+		if idx.BytesToBlock(it.Key()) >= before {
+			break
+		}
+		if records == limit {
+			end = common.CopyBytes(it.Key())
+			break
+		}
+		records++
+	}
+	it.Release()
+
+	// the keys are ordered by block, so the records are deleted by a range
+	if records != 0 {
+		s.deleteRange(s.table.DecisiveEvents, nil, end)
+	}
+
+	return records < limit
+}
+
+// DelTxPosition deletes the transaction position.
+func (s *Store) DelTxPosition(txid common.Hash) {
+	if err := s.table.TxPositions.Delete(txid.Bytes()); err != nil {
+		s.Log.Crit("Failed to erase key-value", "err", err)
+	}
+
+	if s.cache.TxPositions != nil {
+		s.cache.TxPositions.Remove(txid.String())
+	}
+}
@@ -0,0 +1,69 @@
+package gossip
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/logger"
+)
+
+func TestStorePruneHistory(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	store := cachedStore()
+	assertar.Equal(idx.Epoch(1), store.GetFirstEpoch())
+	assertar.Equal(idx.Block(1), store.GetFirstBlock())
+
+	events := make([]*inter.Event, 0, 9)
+	for epoch := idx.Epoch(1); epoch <= 3; epoch++ {
+		for i := 0; i < 3; i++ {
+			e := fakeEvent()
+			e.Epoch = epoch
+			e.Transactions = types.Transactions{
+				types.NewTransaction(uint64(len(events)), common.Address{}, big.NewInt(0), 0, big.NewInt(0), nil),
+			}
+			store.SetEvent(e)
+			store.SetTxPosition(e.Transactions[0].Hash(), &TxPosition{Event: e.Hash()})
+			store.SetEventReceivingTime(e.Hash(), 1)
+			store.AddToPack(epoch, 1, e.Hash())
+			events = append(events, e)
+		}
+		store.SetPacksNum(epoch, 2)
+		store.SetPackInfo(epoch, 1, PackInfo{Index: 1})
+	}
+
+	// by batches
+	assertar.False(store.PruneEvents(3, 4))
+	assertar.True(store.PruneEvents(3, 4))
+	assertar.True(store.PrunePacks(3, 1000))
+
+	for _, e := range events {
+		kept := e.Epoch >= 3
+		assertar.Equal(kept, store.HasEvent(e.Hash()))
+		assertar.Equal(kept, store.GetTxPosition(e.Transactions[0].Hash()) != nil)
+		assertar.Equal(kept, store.GetEventReceivingTime(e.Hash()) != 0)
+		assertar.Equal(kept, store.GetPack(e.Epoch, 1) != nil)
+		assertar.Equal(kept, store.GetPackInfo(e.Epoch, 1) != nil)
+		_, ok := store.GetPacksNum(e.Epoch)
+		assertar.Equal(kept, ok)
+	}
+
+	store.SetBlockDecidedBy(1, events[0].Hash())
+	store.SetBlockDecidedBy(2, events[1].Hash())
+	assertar.True(store.PruneBlocksDecidedBy(2, 1000))
+	assertar.Equal(events[1].Hash(), store.GetBlockDecidedBy(2))
+	assertar.Equal(hash.Event{}, store.GetBlockDecidedBy(1))
+
+	store.SetFirstEpoch(3)
+	store.SetFirstBlock(2)
+	assertar.Equal(idx.Epoch(3), store.GetFirstEpoch())
+	assertar.Equal(idx.Block(2), store.GetFirstBlock())
+}
@@ -0,0 +1,451 @@
+package gossip
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/Fantom-foundation/go-lachesis/app"
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+)
+
+// IntegrityIssue is an inconsistency of the store tables, found by the integrity checks.
+type IntegrityIssue struct {
+	Table string
+	Descr string
+	// Repairable is true for the derived indexes, which are rebuilt on a repair.
+	Repairable bool
+}
+
+func (i IntegrityIssue) String() string {
+	return fmt.Sprintf("%s: %s", i.Table, i.Descr)
+}
+
+// epochDag is a light copy of the stored epoch events.
+type epochDag struct {
+	epoch  idx.Epoch
+	events hash.Events // in the topological order
+	sizes  map[hash.Event]uint32
+	heads  hash.EventsSet
+}
+
+// CheckDag checks that the parents of every stored event exist, that the heads and the event headers
+// of the current epoch match the DAG, and that PackInfos are consistent with Packs.
+// If repair is true, the heads, the event headers and the packs are rebuilt from the events.
+func (s *Store) CheckDag(current idx.Epoch, repair bool, onIssue func(IntegrityIssue)) {
+	for epoch := s.GetFirstEpoch(); epoch <= current; epoch++ {
+		dag := s.readEpochDag(epoch, onIssue)
+
+		if epoch == current {
+			s.checkHeads(dag, repair, onIssue)
+			s.checkEventHeaders(dag, repair, onIssue)
+		}
+		s.checkPacks(dag, epoch < current, repair, onIssue)
+	}
+}
+
+// readEpochDag reads the epoch events and checks that their parents exist.
+func (s *Store) readEpochDag(epoch idx.Epoch, onIssue func(IntegrityIssue)) *epochDag {
+	dag := &epochDag{
+		epoch: epoch,
+		sizes: make(map[hash.Event]uint32),
+		heads: hash.EventsSet{},
+	}
+
+	it := s.table.Events.NewIteratorWithPrefix(epoch.Bytes())
+	defer it.Release()
+	for it.Next() {
+		id := hash.BytesToEvent(it.Key())
+		e := &inter.Event{}
+		if err := rlp.DecodeBytes(it.Value(), e); err != nil {
+			onIssue(IntegrityIssue{Table: "Events", Descr: fmt.Sprintf("event %s isn't decodable: %v", id, err)})
+			continue
+		}
+		if e.Hash() != id {
+			onIssue(IntegrityIssue{Table: "Events", Descr: fmt.Sprintf("event %s is stored under key %s", e.Hash(), id)})
+			continue
+		}
+		for _, p := range e.Parents {
+			// parents go before their children in the (epoch, lamport) order
+			if _, ok := dag.sizes[p]; !ok {
+				onIssue(IntegrityIssue{Table: "Events", Descr: fmt.Sprintf("event %s: parent %s is missing", id, p)})
+			}
+			dag.heads.Erase(p)
+		}
+		dag.events.Add(id)
+		dag.sizes[id] = uint32(e.Size())
+		dag.heads.Add(id)
+	}
+	if it.Error() != nil {
+		s.Log.Crit("Failed to iterate keys", "err", it.Error())
+	}
+
+	return dag
+}
+
+// checkHeads checks that Heads are the epoch events with no descendants.
+func (s *Store) checkHeads(dag *epochDag, repair bool, onIssue func(IntegrityIssue)) {
+	stored := s.GetHeads(dag.epoch).Set()
+
+	for id := range dag.heads {
+		if stored.Contains(id) {
+			continue
+		}
+		if repair {
+			s.AddHead(dag.epoch, id)
+		}
+		onIssue(IntegrityIssue{Table: "Heads", Descr: fmt.Sprintf("head %s is missing", id), Repairable: true})
+	}
+	for id := range stored {
+		if dag.heads.Contains(id) {
+			continue
+		}
+		if repair {
+			s.DelHead(dag.epoch, id)
+		}
+		onIssue(IntegrityIssue{Table: "Heads", Descr: fmt.Sprintf("%s isn't a head", id), Repairable: true})
+	}
+}
+
+// checkEventHeaders checks that every event of the epoch has a header in the epoch DB.
+func (s *Store) checkEventHeaders(dag *epochDag, repair bool, onIssue func(IntegrityIssue)) {
+	for _, id := range dag.events {
+		if s.HasEventHeader(id) {
+			continue
+		}
+		if repair {
+			e := s.GetEvent(id)
+			s.SetEventHeader(dag.epoch, id, &e.EventHeaderData)
+		}
+		onIssue(IntegrityIssue{Table: "EventHeaders", Descr: fmt.Sprintf("header of event %s is missing", id), Repairable: true})
+	}
+}
+
+// checkPacks checks that PackInfos are consistent with Packs and that every epoch event is packed.
+// The last pack of a sealed epoch is pinned, the last pack of the current epoch isn't pinned.
+func (s *Store) checkPacks(dag *epochDag, sealed bool, repair bool, onIssue func(IntegrityIssue)) {
+	issues := make([]string, 0)
+	issue := func(format string, args ...interface{}) {
+		issues = append(issues, fmt.Sprintf("epoch %d: ", dag.epoch)+fmt.Sprintf(format, args...))
+	}
+
+	num := s.GetPacksNumOrDefault(dag.epoch)
+
+	packs := make(map[idx.Pack]hash.Events)
+	it := s.table.Packs.NewIteratorWithPrefix(dag.epoch.Bytes())
+	for it.Next() {
+		if len(it.Key()) != epochSize+packSize+eventIDSize {
+			issue("pack key %x has wrong length", it.Key())
+			continue
+		}
+		pack := idx.BytesToPack(it.Key()[epochSize : epochSize+packSize])
+		packs[pack] = append(packs[pack], hash.BytesToEvent(it.Key()[epochSize+packSize:]))
+	}
+	it.Release()
+
+	infos := make(map[idx.Pack]*PackInfo)
+	it = s.table.PackInfos.NewIteratorWithPrefix(dag.epoch.Bytes())
+	for it.Next() {
+		if len(it.Key()) != epochSize+packSize {
+			issue("pack info key %x has wrong length", it.Key())
+			continue
+		}
+		pack := idx.BytesToPack(it.Key()[epochSize:])
+		info := &PackInfo{}
+		if err := rlp.DecodeBytes(it.Value(), info); err != nil {
+			issue("pack info %d isn't decodable: %v", pack, err)
+			continue
+		}
+		infos[pack] = info
+	}
+	it.Release()
+
+	packed := hash.EventsSet{}
+	for pack, events := range packs {
+		var size uint32
+		for _, id := range events {
+			packed.Add(id)
+			if _, ok := dag.sizes[id]; !ok {
+				issue("pack %d: event %s is missing", pack, id)
+			}
+			size += dag.sizes[id]
+		}
+		if pack == 0 || pack > num {
+			issue("pack %d is out of packs range [1, %d]", pack, num)
+		}
+
+		info := infos[pack]
+		if info == nil {
+			issue("pack %d: info is missing", pack)
+			continue
+		}
+		// the older versions reset the counters of the last pack of epoch
+		legacy := sealed && pack == num-1 && info.NumOfEvents == 0 && info.Size == 0
+		if !legacy && (info.NumOfEvents != uint32(len(events)) || info.Size != size) {
+			issue("pack %d: info has %d events of %d bytes, but pack has %d events of %d bytes",
+				pack, info.NumOfEvents, info.Size, len(events), size)
+		}
+	}
+	for pack, info := range infos {
+		if info.Index != pack {
+			issue("pack %d: info has index %d", pack, info.Index)
+		}
+		if _, ok := packs[pack]; !ok && info.NumOfEvents != 0 {
+			issue("pack %d: info has %d events, but pack is empty", pack, info.NumOfEvents)
+		}
+		for _, id := range info.Heads {
+			if _, ok := dag.sizes[id]; !ok {
+				issue("pack %d: pinned head %s is missing", pack, id)
+			}
+		}
+	}
+	if len(dag.events) != 0 {
+		for pack := idx.Pack(1); pack < num; pack++ {
+			if info := infos[pack]; info == nil || len(info.Heads) == 0 {
+				issue("pack %d isn't pinned", pack)
+			}
+		}
+		if sealed && len(packs[num]) != 0 {
+			issue("last pack %d isn't pinned", num)
+		}
+	}
+	for _, id := range dag.events {
+		if !packed.Contains(id) {
+			issue("event %s isn't packed", id)
+		}
+	}
+
+	if repair && len(issues) != 0 {
+		s.rebuildPacks(dag, sealed)
+	}
+	sort.Strings(issues)
+	for _, descr := range issues {
+		onIssue(IntegrityIssue{Table: "Packs", Descr: descr, Repairable: true})
+	}
+}
+
+// rebuildPacks drops the epoch packs and packs the epoch events again, in the same way as they are packed on arrival.
+func (s *Store) rebuildPacks(dag *epochDag, sealed bool) {
+	s.deleteRange(s.table.Packs, dag.epoch.Bytes(), (dag.epoch + 1).Bytes())
+	s.deleteRange(s.table.PackInfos, dag.epoch.Bytes(), (dag.epoch + 1).Bytes())
+
+	if s.cache.PackInfos != nil {
+		s.cache.PackInfos.Purge()
+	}
+
+	var (
+		pack  = idx.Pack(1)
+		info  = PackInfo{Index: pack}
+		heads = hash.EventsSet{}
+	)
+	for _, id := range dag.events {
+		e := s.GetEvent(id)
+		heads.Erase(e.Parents...)
+		heads.Add(id)
+
+		s.AddToPack(dag.epoch, pack, id)
+		info.NumOfEvents++
+		info.Size += dag.sizes[id]
+		if info.NumOfEvents >= maxPackEventsNum || info.Size >= maxPackSize {
+			info.Heads = heads.Slice()
+			s.SetPackInfo(dag.epoch, pack, info)
+			pack++
+			info = PackInfo{Index: pack}
+		}
+	}
+	if info.NumOfEvents != 0 {
+		s.SetPackInfo(dag.epoch, pack, info)
+	}
+	if sealed {
+		info.Heads = heads.Slice()
+		s.SetPackInfo(dag.epoch, pack, info)
+		pack++
+	}
+	s.SetPacksNum(dag.epoch, pack)
+}
+
+// CheckBlockIndexes checks that BlockHashes and TxPositions point at the stored blocks and events.
+// If repair is true, the wrong records are rebuilt from the blocks.
+func (s *Store) CheckBlockIndexes(repair bool, onIssue func(IntegrityIssue)) {
+	// TxPositions are empty if the index is disabled
+	it := s.table.TxPositions.NewIterator()
+	txIndex := it.Next()
+	it.Release()
+
+	it = s.table.Blocks.NewIterator()
+	if !it.Next() {
+		it.Release()
+		return
+	}
+	n := idx.BytesToBlock(it.Key())
+	it.Release()
+
+	for ; ; n++ {
+		block := s.GetBlock(n)
+		if block == nil {
+			break
+		}
+		s.checkBlockIndex(block, repair, onIssue)
+		if txIndex {
+			s.checkBlockTxPositions(block, repair, onIssue)
+		}
+	}
+
+	it = s.table.Blocks.NewIteratorWithStart(n.Bytes())
+	if it.Next() {
+		onIssue(IntegrityIssue{Table: "Blocks", Descr: fmt.Sprintf("block %d is missing before block %d", n, idx.BytesToBlock(it.Key()))})
+	}
+	it.Release()
+
+	s.checkDanglingBlockIndexes(repair, onIssue)
+	if txIndex {
+		s.checkDanglingTxPositions(repair, onIssue)
+	}
+}
+
+// CheckBlockState checks that the EVM state root and the receipts of the block are stored,
+// i.e. the node may continue from the block. The missing data aren't repairable.
+func (s *Store) CheckBlockState(adb *app.Store, n idx.Block, onIssue func(IntegrityIssue)) {
+	block := s.GetBlock(n)
+	if block == nil {
+		onIssue(IntegrityIssue{Table: "Blocks", Descr: fmt.Sprintf("block %d is missing", n)})
+		return
+	}
+
+	if _, err := adb.OpenStateDB(block.Root); err != nil {
+		onIssue(IntegrityIssue{Table: "EvmState", Descr: fmt.Sprintf("state %s of block %d is missing: %v", block.Root.Hex(), n, err)})
+	}
+
+	// the receipts are written along with the tx positions, if the index is enabled
+	events, _ := s.getBlockEvents(block)
+	for _, e := range events {
+		for _, tx := range e.Transactions {
+			pos := s.GetTxPosition(tx.Hash())
+			if pos == nil || pos.Block != n {
+				continue
+			}
+			if adb.GetReceipts(n) == nil {
+				onIssue(IntegrityIssue{Table: "Receipts", Descr: fmt.Sprintf("receipts of block %d are missing", n)})
+			}
+			return
+		}
+	}
+}
+
+func (s *Store) checkBlockIndex(block *inter.Block, repair bool, onIssue func(IntegrityIssue)) {
+	n := s.GetBlockIndex(block.Atropos)
+	if n != nil && *n == block.Index {
+		return
+	}
+	if repair {
+		s.SetBlockIndex(block.Atropos, block.Index)
+	}
+	onIssue(IntegrityIssue{Table: "BlockHashes", Descr: fmt.Sprintf("block %d isn't indexed by atropos %s", block.Index, block.Atropos), Repairable: true})
+}
+
+// checkBlockTxPositions checks the positions of the block transactions, except the skipped ones.
+func (s *Store) checkBlockTxPositions(block *inter.Block, repair bool, onIssue func(IntegrityIssue)) {
+	events, missing := s.getBlockEvents(block)
+	if missing != nil {
+		if missing.Epoch() >= s.GetFirstEpoch() {
+			onIssue(IntegrityIssue{Table: "Blocks", Descr: fmt.Sprintf("block %d: event %s is missing", block.Index, missing)})
+		}
+		// positions are unknown without the events
+		return
+	}
+
+	txs, positions := blockTxPositions(block, events)
+	for _, tx := range txs {
+		exp := positions[tx.Hash()]
+		pos := s.GetTxPosition(tx.Hash())
+		if pos != nil && *pos == exp {
+			continue
+		}
+		if repair {
+			s.SetTxPosition(tx.Hash(), &exp)
+		}
+		onIssue(IntegrityIssue{Table: "TxPositions", Descr: fmt.Sprintf("tx %s: position is wrong, expected %+v", tx.Hash().String(), exp), Repairable: true})
+	}
+}
+
+// checkDanglingBlockIndexes checks that BlockHashes point at the blocks with the same atropos.
+func (s *Store) checkDanglingBlockIndexes(repair bool, onIssue func(IntegrityIssue)) {
+	dangling := make([]hash.Event, 0)
+
+	it := s.table.BlockHashes.NewIterator()
+	for it.Next() {
+		id := hash.BytesToEvent(it.Key())
+		n := idx.BytesToBlock(it.Value())
+		if block := s.GetBlock(n); block == nil || block.Atropos != id {
+			dangling = append(dangling, id)
+			onIssue(IntegrityIssue{Table: "BlockHashes", Descr: fmt.Sprintf("atropos %s points at wrong block %d", id, n), Repairable: true})
+		}
+	}
+	it.Release()
+
+	if !repair {
+		return
+	}
+	for _, id := range dangling {
+		if err := s.table.BlockHashes.Delete(id.Bytes()); err != nil {
+			s.Log.Crit("Failed to erase key-value", "err", err)
+		}
+		s.cache.BlockHashes.Remove(id)
+	}
+}
+
+// checkDanglingTxPositions checks that TxPositions point at the transactions of the stored blocks.
+func (s *Store) checkDanglingTxPositions(repair bool, onIssue func(IntegrityIssue)) {
+	firstEpoch := s.GetFirstEpoch()
+	dangling := make([]common.Hash, 0)
+
+	it := s.table.TxPositions.NewIterator()
+	for it.Next() {
+		txid := common.BytesToHash(it.Key())
+		pos := &TxPosition{}
+		if err := rlp.DecodeBytes(it.Value(), pos); err != nil {
+			dangling = append(dangling, txid)
+			onIssue(IntegrityIssue{Table: "TxPositions", Descr: fmt.Sprintf("tx %s: position isn't decodable: %v", txid.String(), err), Repairable: true})
+			continue
+		}
+		if !s.isTxPositionValid(txid, pos, firstEpoch) {
+			dangling = append(dangling, txid)
+			onIssue(IntegrityIssue{Table: "TxPositions", Descr: fmt.Sprintf("tx %s: position %+v points at another data", txid.String(), *pos), Repairable: true})
+		}
+	}
+	it.Release()
+
+	if !repair {
+		return
+	}
+	for _, txid := range dangling {
+		s.DelTxPosition(txid)
+	}
+}
+
+func (s *Store) isTxPositionValid(txid common.Hash, pos *TxPosition, firstEpoch idx.Epoch) bool {
+	block := s.GetBlock(pos.Block)
+	if block == nil {
+		return false
+	}
+	found := false
+	for _, id := range block.Events {
+		if id == pos.Event {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return false
+	}
+	e := s.GetEvent(pos.Event)
+	if e == nil {
+		// the event is pruned
+		return pos.Event.Epoch() < firstEpoch
+	}
+	return pos.EventOffset < uint32(len(e.Transactions)) && e.Transactions[pos.EventOffset].Hash() == txid
+}
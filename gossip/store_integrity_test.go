@@ -0,0 +1,205 @@
+package gossip
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/app"
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/logger"
+)
+
+func TestStoreCheckDag(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	store := cachedStore()
+
+	// epoch 1 is sealed, epoch 2 is current
+	events := make([]*inter.Event, 0, 6)
+	for epoch := idx.Epoch(1); epoch <= 2; epoch++ {
+		root := fakeEvent()
+		root.Epoch = epoch
+		root.Lamport = 1
+		events = append(events, root)
+		for i := 0; i < 2; i++ {
+			e := fakeEvent()
+			e.Epoch = epoch
+			e.Lamport = 2
+			e.Parents = hash.Events{root.Hash()}
+			events = append(events, e)
+		}
+	}
+	for _, e := range events {
+		store.SetEvent(e)
+		if e.Epoch == 2 {
+			for _, p := range e.Parents {
+				store.DelHead(e.Epoch, p)
+			}
+			store.AddHead(e.Epoch, e.Hash())
+		}
+	}
+	store.rebuildPacks(store.readEpochDag(1, nil), true)
+	store.rebuildPacks(store.readEpochDag(2, nil), false)
+
+	check := func(repair bool) []IntegrityIssue {
+		issues := make([]IntegrityIssue, 0)
+		store.CheckDag(2, repair, func(issue IntegrityIssue) {
+			issues = append(issues, issue)
+		})
+		return issues
+	}
+
+	assertar.Empty(check(false))
+	assertar.ElementsMatch(hash.Events{events[1].Hash(), events[2].Hash()}, store.GetPackInfo(1, 1).Heads)
+	num, _ := store.GetPacksNum(1)
+	assertar.Equal(idx.Pack(2), num)
+	num, _ = store.GetPacksNum(2)
+	assertar.Equal(idx.Pack(1), num)
+
+	// break the derived indexes
+	store.DelHead(2, events[4].Hash())
+	store.AddHead(2, events[3].Hash())
+	store.DelEventHeader(2, events[5].Hash())
+	store.SetPackInfo(1, 1, PackInfo{Index: 1})
+
+	issues := check(false)
+	assertar.Len(issues, 4)
+	for _, issue := range issues {
+		assertar.True(issue.Repairable, issue.String())
+	}
+	assertar.Len(check(false), 4, "isn't repaired without the flag")
+
+	assertar.Len(check(true), 4)
+	assertar.Empty(check(false))
+
+	// break the events
+	missing := fakeEvent()
+	missing.Epoch = 2
+	missing.Lamport = 1
+	orphan := fakeEvent()
+	orphan.Epoch = 2
+	orphan.Lamport = 2
+	orphan.Parents = hash.Events{missing.Hash()}
+	store.SetEvent(orphan)
+
+	issues = check(true)
+	if assertar.NotEmpty(issues) {
+		assertar.Equal("Events", issues[0].Table)
+		assertar.False(issues[0].Repairable)
+	}
+}
+
+func TestStoreCheckBlockIndexes(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	store := cachedStore()
+
+	txs := make(types.Transactions, 0, 4)
+	for i := 0; i < cap(txs); i++ {
+		txs = append(txs, types.NewTransaction(uint64(i), common.Address{}, big.NewInt(0), 0, big.NewInt(0), nil))
+	}
+	e1 := fakeEvent()
+	e1.Epoch = 1
+	e1.Transactions = txs[:2]
+	e2 := fakeEvent()
+	e2.Epoch = 1
+	e2.Transactions = txs[2:]
+	store.SetEvent(e1)
+	store.SetEvent(e2)
+
+	block := inter.NewBlock(1, 0, e2.Hash(), hash.Event{}, hash.Events{e1.Hash(), e2.Hash()})
+	block.SkippedTxs = []uint{1}
+	store.SetBlock(block)
+	store.SetBlockIndex(block.Atropos, block.Index)
+	store.SetTxPosition(txs[0].Hash(), &TxPosition{Block: 1, Event: e1.Hash(), EventOffset: 0, BlockOffset: 0})
+	store.SetTxPosition(txs[2].Hash(), &TxPosition{Block: 1, Event: e2.Hash(), EventOffset: 0, BlockOffset: 1})
+	store.SetTxPosition(txs[3].Hash(), &TxPosition{Block: 1, Event: e2.Hash(), EventOffset: 1, BlockOffset: 2})
+
+	check := func(repair bool) []IntegrityIssue {
+		issues := make([]IntegrityIssue, 0)
+		store.CheckBlockIndexes(repair, func(issue IntegrityIssue) {
+			issues = append(issues, issue)
+		})
+		return issues
+	}
+
+	assertar.Empty(check(false))
+
+	// break the indexes
+	store.SetBlockIndex(hash.FakeEvent(), 2)
+	store.DelTxPosition(txs[2].Hash())
+	store.SetTxPosition(txs[3].Hash(), &TxPosition{Block: 1, Event: e2.Hash(), EventOffset: 0, BlockOffset: 2})
+	store.SetTxPosition(common.Hash{1}, &TxPosition{Block: 1, Event: e1.Hash()})
+
+	// the wrong position is found by both the block and the position
+	assertar.Len(check(false), 5)
+	assertar.Len(check(true), 4)
+	assertar.Empty(check(false))
+
+	assertar.Equal(&TxPosition{Block: 1, Event: e2.Hash(), EventOffset: 1, BlockOffset: 2}, store.GetTxPosition(txs[3].Hash()))
+	assertar.Nil(store.GetTxPosition(txs[1].Hash()), "skipped tx")
+	assertar.Nil(store.GetTxPosition(common.Hash{1}))
+}
+
+func TestStoreCheckBlockState(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	store := cachedStore()
+	adb := app.NewMemStore()
+
+	statedb := adb.StateDB(common.Hash{})
+	statedb.AddBalance(common.Address{1}, big.NewInt(1))
+	root, err := statedb.Commit(true)
+	if !assertar.NoError(err) {
+		return
+	}
+	assertar.NoError(adb.Commit(nil, true))
+
+	tx := types.NewTransaction(0, common.Address{}, big.NewInt(0), 0, big.NewInt(0), nil)
+	e := fakeEvent()
+	e.Epoch = 1
+	e.Transactions = types.Transactions{tx}
+	store.SetEvent(e)
+
+	block := inter.NewBlock(1, 0, e.Hash(), hash.Event{}, hash.Events{e.Hash()})
+	block.Root = root
+	store.SetBlock(block)
+
+	check := func() []IntegrityIssue {
+		issues := make([]IntegrityIssue, 0)
+		store.CheckBlockState(adb, 1, func(issue IntegrityIssue) {
+			issues = append(issues, issue)
+		})
+		return issues
+	}
+
+	// the receipts aren't expected without the tx index
+	assertar.Empty(check())
+
+	store.SetTxPosition(tx.Hash(), &TxPosition{Block: 1, Event: e.Hash()})
+	issues := check()
+	if assertar.Len(issues, 1) {
+		assertar.Equal("Receipts", issues[0].Table)
+		assertar.False(issues[0].Repairable)
+	}
+	adb.SetReceipts(1, types.Receipts{{Status: types.ReceiptStatusSuccessful, TxHash: tx.Hash()}})
+	assertar.Empty(check())
+
+	// the state isn't stored
+	block.Root = common.Hash{2}
+	store.SetBlock(block)
+	issues = check()
+	if assertar.Len(issues, 1) {
+		assertar.Equal("EvmState", issues[0].Table)
+		assertar.False(issues[0].Repairable)
+	}
+}
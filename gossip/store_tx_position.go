@@ -6,8 +6,10 @@ package gossip
 
 import (
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 
 	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter"
 	"github.com/Fantom-foundation/go-lachesis/inter/idx"
 )
 
@@ -48,3 +50,76 @@ func (s *Store) GetTxPosition(txid common.Hash) *TxPosition {
 
 	return txPosition
 }
+
+// getBlockEvents returns the events of the block.
+// If some event isn't found, then its ID is returned instead.
+func (s *Store) getBlockEvents(block *inter.Block) (inter.Events, *hash.Event) {
+	events := make(inter.Events, 0, len(block.Events))
+	for _, id := range block.Events {
+		e := s.GetEvent(id)
+		if e == nil {
+			return nil, &id
+		}
+		events = append(events, e)
+	}
+	return events, nil
+}
+
+// blockTxPositions returns the not skipped transactions of the block events and their positions,
+// the same as they are indexed when the block is applied.
+func blockTxPositions(block *inter.Block, events inter.Events) (types.Transactions, map[common.Hash]TxPosition) {
+	txs := make(types.Transactions, 0, len(events)*10)
+	positions := make(map[common.Hash]TxPosition)
+
+	var (
+		i         uint
+		skipCount int
+	)
+	for _, e := range events {
+		for j, tx := range e.Transactions {
+			// If tx was met in multiple events, then assign to first ordered event
+			if _, ok := positions[tx.Hash()]; !ok {
+				positions[tx.Hash()] = TxPosition{
+					Event:       e.Hash(),
+					EventOffset: uint32(j),
+				}
+			}
+
+			skipped := skipCount < len(block.SkippedTxs) && block.SkippedTxs[skipCount] == i
+			i++
+			if skipped {
+				skipCount++
+				continue
+			}
+			txs = append(txs, tx)
+		}
+	}
+
+	for i, tx := range txs {
+		position := positions[tx.Hash()]
+		position.Block = block.Index
+		position.BlockOffset = uint32(i)
+		positions[tx.Hash()] = position
+	}
+
+	return txs, positions
+}
+
+// SetTxReindexProgress stores the next block, which transactions are to be reindexed.
+func (s *Store) SetTxReindexProgress(n idx.Block) {
+	if err := s.table.Reindex.Put([]byte("x"), n.Bytes()); err != nil {
+		s.Log.Crit("Failed to put key-value", "err", err)
+	}
+}
+
+// GetTxReindexProgress returns the next block, which transactions are to be reindexed.
+func (s *Store) GetTxReindexProgress() idx.Block {
+	buf, err := s.table.Reindex.Get([]byte("x"))
+	if err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	}
+	if buf == nil {
+		return 1
+	}
+	return idx.BytesToBlock(buf)
+}
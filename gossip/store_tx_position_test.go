@@ -0,0 +1,65 @@
+package gossip
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/logger"
+)
+
+func TestBlockTxPositions(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	store := cachedStore()
+
+	txs := make(types.Transactions, 0, 3)
+	for i := 0; i < cap(txs); i++ {
+		txs = append(txs, types.NewTransaction(uint64(i), common.Address{}, big.NewInt(0), 0, big.NewInt(0), nil))
+	}
+	e1 := fakeEvent()
+	e1.Transactions = types.Transactions{txs[0], txs[1]}
+	e2 := fakeEvent()
+	// txs[1] is duplicated, so it's skipped in the second event
+	e2.Transactions = types.Transactions{txs[1], txs[2]}
+	store.SetEvent(e1)
+	store.SetEvent(e2)
+
+	block := inter.NewBlock(1, 0, e2.Hash(), hash.Event{}, hash.Events{e1.Hash(), e2.Hash()})
+	block.SkippedTxs = []uint{2}
+
+	events, missing := store.getBlockEvents(block)
+	assertar.Nil(missing)
+	assertar.Equal(inter.Events{e1, e2}, events)
+
+	got, positions := blockTxPositions(block, events)
+	assertar.Equal(types.Transactions{txs[0], txs[1], txs[2]}, got)
+	assertar.Equal(TxPosition{Block: 1, Event: e1.Hash(), EventOffset: 0, BlockOffset: 0}, positions[txs[0].Hash()])
+	assertar.Equal(TxPosition{Block: 1, Event: e1.Hash(), EventOffset: 1, BlockOffset: 1}, positions[txs[1].Hash()])
+	assertar.Equal(TxPosition{Block: 1, Event: e2.Hash(), EventOffset: 1, BlockOffset: 2}, positions[txs[2].Hash()])
+
+	block.Events = append(block.Events, hash.FakeEvent())
+	events, missing = store.getBlockEvents(block)
+	assertar.Nil(events)
+	if assertar.NotNil(missing) {
+		assertar.Equal(block.Events[2], *missing)
+	}
+}
+
+func TestStoreTxReindexProgress(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	store := cachedStore()
+
+	assertar.Equal(idx.Block(1), store.GetTxReindexProgress())
+	store.SetTxReindexProgress(100)
+	assertar.Equal(idx.Block(100), store.GetTxReindexProgress())
+}
@@ -2,6 +2,8 @@ package integration
 
 import (
 	"crypto/ecdsa"
+	"os"
+	"path/filepath"
 
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
@@ -11,13 +13,15 @@ import (
 
 	"github.com/Fantom-foundation/go-lachesis/app"
 	"github.com/Fantom-foundation/go-lachesis/gossip"
+	"github.com/Fantom-foundation/go-lachesis/gossip/extsigner"
+	"github.com/Fantom-foundation/go-lachesis/gossip/slashprotection"
 	"github.com/Fantom-foundation/go-lachesis/kvdb/flushable"
 	"github.com/Fantom-foundation/go-lachesis/poset"
 )
 
 // MakeEngine makes consensus engine from config.
 func MakeEngine(dataDir string, gossipCfg *gossip.Config) (*poset.Poset, *app.Store, *gossip.Store) {
-	dbs := flushable.NewSyncedPool(dbProducer(dataDir))
+	dbs := flushable.NewSyncedPool(DbProducer(dataDir, gossipCfg.DBEngine))
 
 	appStoreConfig := app.StoreConfig{
 		ReceiptsCacheSize:   gossipCfg.ReceiptsCacheSize,
@@ -60,10 +64,42 @@ func MakeEngine(dataDir string, gossipCfg *gossip.Config) (*poset.Poset, *app.St
 
 	// create consensus
 	engine := poset.New(gossipCfg.Net.Dag, cdb, gdb)
+	engine.SetElectionsIndex(gossipCfg.ElectionsIndex)
 
 	return engine, adb, gdb
 }
 
+// SlashingProtectionDir is the subdir of the datadir with the slashing-protection DB.
+// It's apart from the chain DBs, so it survives the chain re-sync.
+const SlashingProtectionDir = "slashing-protection"
+
+// MakeSlashingProtection opens the slashing-protection DB of the validator keys.
+func MakeSlashingProtection(dataDir string, gossipCfg *gossip.Config) *slashprotection.DB {
+	dir := dataDir
+	if dataDir != "inmemory" && dataDir != "" {
+		dir = filepath.Join(dataDir, SlashingProtectionDir)
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			utils.Fatalf("Failed to create slashing-protection dir: %v", err)
+		}
+	}
+	db := DbProducer(dir, gossipCfg.DBEngine).OpenDb("protection")
+
+	return slashprotection.New(db, gossipCfg.Net.GenesisAtropos())
+}
+
+// MakeEventSigner connects to the external events signer, it returns nil if the signer isn't configured.
+func MakeEventSigner(gossipCfg *gossip.Config) *extsigner.Client {
+	cfg := gossipCfg.Emitter.ExternalSigner
+	if cfg.Endpoint == "" {
+		return nil
+	}
+	signer, err := extsigner.Dial(cfg.Endpoint, cfg.Timeout)
+	if err != nil {
+		utils.Fatalf("Failed to connect to the external signer: %v", err)
+	}
+	return signer
+}
+
 // SetAccountKey sets key into accounts manager and unlocks it with pswd.
 func SetAccountKey(
 	am *accounts.Manager, key *ecdsa.PrivateKey, pswd string,
@@ -1,15 +1,52 @@
 package integration
 
 import (
+	"strings"
+
+	"github.com/ethereum/go-ethereum/cmd/utils"
+
 	"github.com/Fantom-foundation/go-lachesis/kvdb"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/badger"
 	"github.com/Fantom-foundation/go-lachesis/kvdb/leveldb"
 	"github.com/Fantom-foundation/go-lachesis/kvdb/memorydb"
 )
 
-func dbProducer(dbdir string) kvdb.DbProducer {
+// Supported engines of the on-disk databases.
+const (
+	LevelDbEngine  = "leveldb"
+	BadgerDbEngine = "badger"
+)
+
+// DbEngines lists the supported engines of the on-disk databases.
+var DbEngines = []string{LevelDbEngine, BadgerDbEngine}
+
+// DbProducer makes the producer of the DBs in dbdir, which are stored by the engine.
+// The DBs are in memory if dbdir is "inmemory" or empty.
+func DbProducer(dbdir, engine string) kvdb.DbProducer {
 	if dbdir == "inmemory" || dbdir == "" {
 		return memorydb.NewProducer("")
 	}
 
-	return leveldb.NewProducer(dbdir)
+	producers := map[string]kvdb.DbProducer{
+		LevelDbEngine:  leveldb.NewProducer(dbdir),
+		BadgerDbEngine: badger.NewProducer(dbdir),
+	}
+	if engine == "" {
+		engine = LevelDbEngine
+	}
+	producer, ok := producers[engine]
+	if !ok {
+		utils.Fatalf("Unknown DB engine %q, supported are: %s", engine, strings.Join(DbEngines, ", "))
+	}
+
+	// the DBs of another engine would be ignored silently, so don't start a new chain over them
+	if len(producer.Names()) == 0 {
+		for other, p := range producers {
+			if other != engine && len(p.Names()) != 0 {
+				utils.Fatalf("Datadir %s contains %s DBs, but %s engine is chosen", dbdir, other, engine)
+			}
+		}
+	}
+
+	return producer
 }
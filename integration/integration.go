@@ -29,6 +29,7 @@ func NewIntegration(ctx *adapters.ServiceContext, network lachesis.Config) *goss
 	if err != nil {
 		panic(err)
 	}
+	svc.SetSlashingProtection(MakeSlashingProtection(ctx.Config.DataDir, &gossipCfg))
 
 	return svc
 }
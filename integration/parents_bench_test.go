@@ -0,0 +1,85 @@
+package integration
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/gossip"
+	"github.com/Fantom-foundation/go-lachesis/logger"
+)
+
+// parentsBenchConfig is a network of validators with the different links latency.
+func parentsBenchConfig(strategy string, seed int64) SimConfig {
+	cfg := DefaultSimConfig(10)
+	cfg.ParentsStrategy = strategy
+	cfg.Seed = seed
+	cfg.Jitter = 100 * time.Millisecond
+	cfg.Loss = 0.01
+	for i := range cfg.Nodes {
+		cfg.Nodes[i].Latency = time.Duration(i*i) * 5 * time.Millisecond
+	}
+	return cfg
+}
+
+// BenchmarkParentsStrategies measures the time-to-finality of the events for each parent-selection strategy.
+// The reported metrics are in the virtual time, the ns/op is the simulation time.
+func BenchmarkParentsStrategies(b *testing.B) {
+	for _, strategy := range gossip.ParentsStrategies {
+		b.Run(strategy, func(b *testing.B) {
+			var finality []time.Duration
+			for i := 0; i < b.N; i++ {
+				sim, err := NewSimulator(parentsBenchConfig(strategy, int64(i)))
+				if err != nil {
+					b.Fatal(err)
+				}
+				sim.Run(time.Minute)
+				sim.Stop()
+				if d := sim.CompareBlocks(); d != nil {
+					b.Fatal(d)
+				}
+				finality = append(finality, sim.Finality()...)
+			}
+			if len(finality) == 0 {
+				b.Fatal("no events are finalized")
+			}
+
+			sort.Slice(finality, func(i, j int) bool {
+				return finality[i] < finality[j]
+			})
+			var sum time.Duration
+			for _, d := range finality {
+				sum += d
+			}
+			ms := func(d time.Duration) float64 {
+				return float64(d) / float64(time.Millisecond)
+			}
+			b.ReportMetric(ms(sum/time.Duration(len(finality))), "ms-mean")
+			b.ReportMetric(ms(finality[len(finality)/2]), "ms-p50")
+			b.ReportMetric(ms(finality[len(finality)*95/100]), "ms-p95")
+			b.ReportMetric(ms(finality[len(finality)-1]), "ms-max")
+		})
+	}
+}
+
+func TestSimulatorParentsStrategies(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	for _, strategy := range gossip.ParentsStrategies {
+		sim, err := NewSimulator(parentsBenchConfig(strategy, 1))
+		if !assertar.NoError(err) {
+			return
+		}
+		sim.Run(20 * time.Second)
+		sim.Stop()
+
+		assertar.NotEmpty(sim.Finality(), strategy)
+		assertar.Nil(sim.CompareBlocks(), strategy)
+	}
+
+	_, err := NewSimulator(parentsBenchConfig("unknown", 1))
+	assertar.Error(err)
+}
@@ -0,0 +1,103 @@
+package integration
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/Fantom-foundation/go-lachesis/gossip"
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/poset"
+)
+
+// ErrReindexPruned is returned if the events of the first epochs aren't stored, so they can't be replayed.
+var ErrReindexPruned = errors.New("events of the first epochs are pruned or imported from a snapshot")
+
+// ReindexDecisiveEvents fills DecisiveEvents of the applied blocks, by replaying the recorded events on a fresh in-memory node.
+// The events are connected in the stored order, so a block is indexed by the event which decides it in that order,
+// and it may differ from the event which decided it when the node processed the events.
+// The indexed blocks aren't overwritten, and the replay is skipped if all the blocks are indexed.
+// Returns the number of the indexed blocks.
+func ReindexDecisiveEvents(gossipCfg *gossip.Config, recorded *poset.Poset, gdb *gossip.Store, stop <-chan struct{}) (int, error) {
+	last := recorded.GetLastState().Checkpoint.LastBlockN
+	from := idx.Block(1)
+	for from <= last && gdb.GetBlockDecidedBy(from) != hash.ZeroEvent {
+		from++
+	}
+	if from > last {
+		return 0, nil
+	}
+	if gdb.GetFirstEpoch() > 1 {
+		return 0, ErrReindexPruned
+	}
+
+	cfg := *gossipCfg
+	cfg.DecisiveEventsIndex = true
+	srv, engine, replayed, err := makeReplayNode(&cfg, recorded)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		start    = time.Now()
+		reported = start
+		indexed  int
+		next     = idx.Block(1)
+	)
+	log.Info("Reindexing decisive events", "from", from, "last", last)
+	for epoch := idx.Epoch(1); next <= last; epoch++ {
+		gdb.ForEachEvent(epoch, func(e *inter.Event) bool {
+			select {
+			case <-stop:
+				err = ErrReplayInterrupted
+				return false
+			default:
+			}
+			err = srv.ProcessEvent(e)
+			return err == nil
+		})
+		if err != nil {
+			break
+		}
+
+		blockN, _ := engine.LastBlock()
+		if blockN < next {
+			err = errors.New("no blocks are replayed, probably the events are pruned")
+			break
+		}
+		for ; next <= blockN && next <= last; next++ {
+			recordedBlock := gdb.GetBlock(next)
+			if recordedBlock == nil || recordedBlock.Atropos != replayed.GetBlock(next).Atropos {
+				err = errors.New("replayed blocks don't match the recorded ones, run the replay command to find the divergence")
+				break
+			}
+			if gdb.GetBlockDecidedBy(next) == hash.ZeroEvent {
+				gdb.SetBlockDecidedBy(next, replayed.GetBlockDecidedBy(next))
+				indexed++
+			}
+		}
+		if err != nil {
+			break
+		}
+		if err = gdb.Commit(nil, false); err != nil {
+			break
+		}
+
+		if time.Since(reported) >= replayReportInterval {
+			log.Info("Reindexing decisive events", "epoch", epoch, "block", next-1, "last", last, "indexed", indexed, "elapsed", common.PrettyDuration(time.Since(start)))
+			reported = time.Now()
+		}
+	}
+	if err != nil {
+		log.Warn("Decisive events reindexing is stopped", "block", next, "indexed", indexed, "err", err)
+		_ = gdb.Commit(nil, true)
+		return indexed, err
+	}
+
+	log.Info("Decisive events are reindexed", "indexed", indexed, "elapsed", common.PrettyDuration(time.Since(start)))
+	return indexed, gdb.Commit(nil, true)
+}
@@ -0,0 +1,95 @@
+package integration
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/gossip"
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/inter/pos"
+	"github.com/Fantom-foundation/go-lachesis/lachesis"
+	"github.com/Fantom-foundation/go-lachesis/lachesis/genesis"
+	"github.com/Fantom-foundation/go-lachesis/logger"
+)
+
+func TestReindex(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	net := lachesis.FakeNetConfig(genesis.FakeValidators(3, big.NewInt(0), pos.StakeToBalance(1)))
+	net.Dag.MaxEpochBlocks = 5
+	cfg := gossip.DefaultConfig(net)
+	cfg.TxPool.Journal = ""
+	cfg.TxIndex = false
+	cfg.DecisiveEventsIndex = false
+
+	// record the events and the blocks without the indexes
+	engine, adb, gdb := MakeEngine("inmemory", &cfg)
+	defer gdb.Close()
+	srv, err := gossip.NewService(&node.ServiceContext{}, &cfg, gdb, engine, adb)
+	if !assertar.NoError(err) {
+		return
+	}
+	const epochs = 3
+	for epoch := idx.Epoch(1); epoch <= epochs; epoch++ {
+		inter.ForEachRandEvent(net.Genesis.Alloc.Validators.Validators().IDs(), 30, 3, nil, inter.ForEachEvent{
+			Process: func(e *inter.Event, name string) {
+				assertar.NoError(srv.ProcessEvent(e))
+			},
+			Build: func(e *inter.Event, name string) *inter.Event {
+				if engine.GetEpoch() != epoch {
+					return nil
+				}
+				e.Epoch = epoch
+				return engine.Prepare(e)
+			},
+		})
+	}
+	last, _ := engine.LastBlock()
+	if !assertar.Equal(idx.Epoch(epochs+1), engine.GetEpoch()) {
+		return
+	}
+
+	// the decisive events
+	indexed, err := ReindexDecisiveEvents(&cfg, engine, gdb, nil)
+	assertar.NoError(err)
+	assertar.Equal(int(last), indexed)
+	for n := idx.Block(1); n <= last; n++ {
+		block := gdb.GetBlock(n)
+		decisive := gdb.GetEvent(gdb.GetBlockDecidedBy(n))
+		if !assertar.NotNil(decisive, n) {
+			continue
+		}
+		assertar.Equal(block.Atropos.Epoch(), decisive.Epoch, n)
+		assertar.True(decisive.Lamport > block.Atropos.Lamport(), n)
+	}
+	indexed, err = ReindexDecisiveEvents(&cfg, engine, gdb, nil)
+	assertar.NoError(err)
+	assertar.Equal(0, indexed, "already indexed")
+
+	// the blocks are replayed after the missing one
+	gdb.SetBlockDecidedBy(7, hash.ZeroEvent)
+	exp := gdb.GetBlockDecidedBy(8)
+	gdb.SetBlockDecidedBy(8, hash.ZeroEvent)
+	indexed, err = ReindexDecisiveEvents(&cfg, engine, gdb, nil)
+	assertar.NoError(err)
+	assertar.Equal(2, indexed)
+	assertar.Equal(exp, gdb.GetBlockDecidedBy(8))
+
+	// the transactions reindexing is aborted by the block which doesn't match the replay
+	block := *gdb.GetBlock(4)
+	block.GasUsed++
+	gdb.SetBlock(&block)
+	assertar.Error(srv.ReindexTxs(nil))
+	assertar.Equal(idx.Block(4), gdb.GetTxReindexProgress())
+
+	block.GasUsed--
+	gdb.SetBlock(&block)
+	assertar.NoError(srv.ReindexTxs(nil))
+	assertar.Equal(last+1, gdb.GetTxReindexProgress())
+}
@@ -0,0 +1,222 @@
+package integration
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/node"
+
+	"github.com/Fantom-foundation/go-lachesis/gossip"
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/poset"
+)
+
+// replayReportInterval is the time limit during replay after which the progress is printed out.
+const replayReportInterval = 8 * time.Second
+
+var (
+	ErrReplayGenesis     = errors.New("datadir has another genesis")
+	ErrReplayInterrupted = errors.New("replay is interrupted")
+)
+
+// Divergence is the first difference between the replayed consensus and the recorded one.
+type Divergence struct {
+	Epoch    idx.Epoch
+	Block    idx.Block
+	Field    string
+	Recorded string
+	Replayed string
+}
+
+// String returns human readable description.
+func (d *Divergence) String() string {
+	return fmt.Sprintf("block %d of epoch %d: %s is %s, but replayed %s", d.Block, d.Epoch, d.Field, d.Recorded, d.Replayed)
+}
+
+// Replay builds a fresh in-memory node with the same genesis, and connects the recorded events to it epoch by epoch.
+// Each replayed block is compared with the recorded one (Atropos, events, skipped txs, state root),
+// and the checkpoint AppHash is compared with the recorded one at the beginning of each epoch and at the end.
+// The epochs up to lastEpoch are replayed, or up to the recorded one if lastEpoch is 0.
+// Returns the first divergence, or nil if the replay matches the records.
+func Replay(gossipCfg *gossip.Config, recorded *poset.Poset, gdb *gossip.Store, lastEpoch idx.Epoch, stop <-chan struct{}) (*Divergence, error) {
+	last := recorded.GetLastState()
+	if lastEpoch == 0 || lastEpoch > last.Epoch.EpochN {
+		lastEpoch = last.Epoch.EpochN
+	}
+
+	// the replaying node neither indexes txs nor traces the events processing
+	cfg := *gossipCfg
+	cfg.DecisiveEventsIndex = false
+	srv, engine, replayed, err := makeReplayNode(&cfg, recorded)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		start    = time.Now()
+		reported = start
+		compared = idx.Block(0)
+		events   int
+	)
+	for epoch := idx.Epoch(1); epoch <= lastEpoch; epoch++ {
+		var (
+			connected   int
+			interrupted bool
+			rejected    *Divergence
+		)
+		gdb.ForEachEvent(epoch, func(e *inter.Event) bool {
+			select {
+			case <-stop:
+				interrupted = true
+				return false
+			default:
+			}
+
+			err := srv.ProcessEvent(e)
+			if err != nil {
+				blockN, _ := engine.LastBlock()
+				rejected = &Divergence{
+					Epoch:    epoch,
+					Block:    blockN + 1,
+					Field:    "event " + e.Hash().String(),
+					Recorded: "connected",
+					Replayed: "rejected (" + err.Error() + ")",
+				}
+				return false
+			}
+			connected++
+
+			if time.Since(reported) >= replayReportInterval {
+				blockN, _ := engine.LastBlock()
+				log.Info("Replaying events", "epoch", epoch, "block", blockN, "events", events+connected, "elapsed", common.PrettyDuration(time.Since(start)))
+				reported = time.Now()
+			}
+			return true
+		})
+		events += connected
+
+		// the blocks are compared first, because a rejected event is usually a consequence of a diverged block
+		blockN, _ := engine.LastBlock()
+		if d := compareBlocks(gdb, replayed, epoch, compared+1, blockN); d != nil {
+			return d, nil
+		}
+		compared = blockN
+		if rejected != nil {
+			return rejected, nil
+		}
+		if interrupted {
+			return nil, ErrReplayInterrupted
+		}
+		if connected == 0 && epoch < last.Epoch.EpochN {
+			return nil, fmt.Errorf("events of epoch %d aren't found, probably they are pruned", epoch)
+		}
+
+		if epoch == last.Epoch.EpochN {
+			continue
+		}
+		if engine.GetEpoch() == epoch {
+			return &Divergence{epoch, blockN, "epoch", "sealed", "not sealed"}, nil
+		}
+		// the checkpoint is captured by both the nodes at the beginning of each epoch
+		if d := compareAppHash(recorded.GetSnapshot(epoch+1), engine.GetSnapshot(epoch+1)); d != nil {
+			return d, nil
+		}
+	}
+
+	if lastEpoch == last.Epoch.EpochN {
+		// the replay of the not sealed epoch may produce less blocks than recorded
+		if compared < last.Checkpoint.LastBlockN {
+			return &Divergence{lastEpoch, compared + 1, "block", "produced", "not found"}, nil
+		}
+		if d := compareAppHash(last, engine.GetLastState()); d != nil {
+			return d, nil
+		}
+	}
+	log.Info("Replayed events", "epochs", lastEpoch, "blocks", compared, "events", events, "elapsed", common.PrettyDuration(time.Since(start)))
+
+	return nil, nil
+}
+
+// makeReplayNode builds a fresh in-memory node with the same genesis as the recorded one.
+// The node neither indexes txs nor traces the events processing, except the DecisiveEvents if they are enabled by the config.
+func makeReplayNode(gossipCfg *gossip.Config, recorded *poset.Poset) (*gossip.Service, *poset.Poset, *gossip.Store, error) {
+	cfg := *gossipCfg
+	cfg.TxIndex = false
+	cfg.EventLocalTimeIndex = false
+	cfg.ElectionsIndex = false
+	cfg.TxPool.Journal = ""
+
+	// the in-memory DBs aren't closed, because the txpool of the service may still read them after the return
+	engine, adb, replayed := MakeEngine("inmemory", &cfg)
+	if engine.GetGenesisHash() != recorded.GetGenesisHash() {
+		return nil, nil, nil, ErrReplayGenesis
+	}
+	srv, err := gossip.NewService(&node.ServiceContext{}, &cfg, replayed, engine, adb)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return srv, engine, replayed, nil
+}
+
+// compareBlocks returns the first difference of the replayed blocks in range [from, to] with the recorded ones.
+func compareBlocks(recordedDb, replayedDb *gossip.Store, epoch idx.Epoch, from, to idx.Block) *Divergence {
+	for n := from; n <= to; n++ {
+		replayed := replayedDb.GetBlock(n)
+		recorded := recordedDb.GetBlock(n)
+		if recorded == nil {
+			return &Divergence{epoch, n, "block", "not found", "produced"}
+		}
+
+		if recorded.Atropos != replayed.Atropos {
+			return &Divergence{epoch, n, "Atropos", recorded.Atropos.String(), replayed.Atropos.String()}
+		}
+		if i := firstDifferentEvent(recorded.Events, replayed.Events); i >= 0 {
+			return &Divergence{epoch, n, fmt.Sprintf("event #%d", i), eventAt(recorded.Events, i), eventAt(replayed.Events, i)}
+		}
+		if fmt.Sprint(recorded.SkippedTxs) != fmt.Sprint(replayed.SkippedTxs) {
+			return &Divergence{epoch, n, "SkippedTxs", fmt.Sprint(recorded.SkippedTxs), fmt.Sprint(replayed.SkippedTxs)}
+		}
+		if recorded.Root != replayed.Root {
+			return &Divergence{epoch, n, "Root", recorded.Root.String(), replayed.Root.String()}
+		}
+	}
+	return nil
+}
+
+// firstDifferentEvent returns position of the first difference of the events lists, or -1 if they are equal.
+func firstDifferentEvent(recorded, replayed hash.Events) int {
+	for i := 0; i < len(recorded) || i < len(replayed); i++ {
+		if i >= len(recorded) || i >= len(replayed) || recorded[i] != replayed[i] {
+			return i
+		}
+	}
+	return -1
+}
+
+func eventAt(events hash.Events, i int) string {
+	if i >= len(events) {
+		return "none"
+	}
+	return events[i].String()
+}
+
+// compareAppHash returns the difference of the checkpoints, if the recorded one is known.
+func compareAppHash(recorded, replayed *poset.Snapshot) *Divergence {
+	if recorded == nil || replayed == nil {
+		return nil // the epoch wasn't sealed by the node, e.g. it was imported from snapshot
+	}
+	if recorded.Checkpoint.LastBlockN != replayed.Checkpoint.LastBlockN {
+		return &Divergence{replayed.Epoch.EpochN, replayed.Checkpoint.LastBlockN, "last block of the epoch",
+			fmt.Sprint(recorded.Checkpoint.LastBlockN), fmt.Sprint(replayed.Checkpoint.LastBlockN)}
+	}
+	if recorded.Checkpoint.AppHash != replayed.Checkpoint.AppHash {
+		return &Divergence{replayed.Epoch.EpochN, replayed.Checkpoint.LastBlockN, "AppHash",
+			recorded.Checkpoint.AppHash.String(), replayed.Checkpoint.AppHash.String()}
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package integration
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/gossip"
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/inter/pos"
+	"github.com/Fantom-foundation/go-lachesis/lachesis"
+	"github.com/Fantom-foundation/go-lachesis/lachesis/genesis"
+	"github.com/Fantom-foundation/go-lachesis/logger"
+)
+
+func TestReplay(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	net := lachesis.FakeNetConfig(genesis.FakeValidators(3, big.NewInt(0), pos.StakeToBalance(1)))
+	net.Dag.MaxEpochBlocks = 5
+	cfg := gossip.DefaultConfig(net)
+	cfg.TxPool.Journal = ""
+
+	// record the events and the blocks
+	engine, adb, gdb := MakeEngine("inmemory", &cfg)
+	defer gdb.Close()
+	srv, err := gossip.NewService(&node.ServiceContext{}, &cfg, gdb, engine, adb)
+	if !assertar.NoError(err) {
+		return
+	}
+	const epochs = 3
+	for epoch := idx.Epoch(1); epoch <= epochs; epoch++ {
+		inter.ForEachRandEvent(net.Genesis.Alloc.Validators.Validators().IDs(), 30, 3, nil, inter.ForEachEvent{
+			Process: func(e *inter.Event, name string) {
+				assertar.NoError(srv.ProcessEvent(e))
+			},
+			Build: func(e *inter.Event, name string) *inter.Event {
+				if engine.GetEpoch() != epoch {
+					return nil
+				}
+				e.Epoch = epoch
+				return engine.Prepare(e)
+			},
+		})
+	}
+	if !assertar.Equal(idx.Epoch(epochs+1), engine.GetEpoch()) {
+		return
+	}
+
+	divergence, err := Replay(&cfg, engine, gdb, 0, nil)
+	assertar.NoError(err)
+	assertar.Nil(divergence)
+
+	// break the records
+	original := gdb.GetBlock(7)
+	block := *original
+	block.Root = common.Hash{1}
+	gdb.SetBlock(&block)
+
+	divergence, err = Replay(&cfg, engine, gdb, 0, nil)
+	assertar.NoError(err)
+	if assertar.NotNil(divergence) {
+		assertar.Equal(idx.Block(7), divergence.Block)
+		assertar.Equal(idx.Epoch(2), divergence.Epoch)
+		assertar.Equal("Root", divergence.Field)
+	}
+
+	divergence, err = Replay(&cfg, engine, gdb, 1, nil)
+	assertar.NoError(err)
+	assertar.Nil(divergence, "the block is out of the replayed epochs")
+
+	// restore the records and produce a few blocks of the not sealed epoch
+	gdb.SetBlock(original)
+	var (
+		lastEpoch = engine.GetEpoch()
+		sealed, _ = engine.LastBlock()
+		events    hash.Events
+	)
+	inter.ForEachRandEvent(net.Genesis.Alloc.Validators.Validators().IDs(), 5, 3, nil, inter.ForEachEvent{
+		Process: func(e *inter.Event, name string) {
+			assertar.NoError(srv.ProcessEvent(e))
+			events.Add(e.Hash())
+		},
+		Build: func(e *inter.Event, name string) *inter.Event {
+			if engine.GetEpoch() != lastEpoch {
+				return nil
+			}
+			e.Epoch = lastEpoch
+			return engine.Prepare(e)
+		},
+	})
+	if blockN, _ := engine.LastBlock(); !assertar.Equal(lastEpoch, engine.GetEpoch()) || !assertar.Less(uint64(sealed), uint64(blockN)) {
+		return
+	}
+
+	divergence, err = Replay(&cfg, engine, gdb, 0, nil)
+	assertar.NoError(err)
+	assertar.Nil(divergence)
+
+	// lose the events of the not sealed epoch
+	for _, id := range events {
+		gdb.DeleteEvent(lastEpoch, id)
+	}
+
+	divergence, err = Replay(&cfg, engine, gdb, 0, nil)
+	assertar.NoError(err)
+	if assertar.NotNil(divergence) {
+		assertar.Equal(sealed+1, divergence.Block)
+		assertar.Equal(lastEpoch, divergence.Epoch)
+		assertar.Equal("block", divergence.Field)
+		assertar.Equal("produced", divergence.Recorded)
+		assertar.Equal("not found", divergence.Replayed)
+	}
+}
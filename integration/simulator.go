@@ -0,0 +1,597 @@
+package integration
+
+import (
+	"bytes"
+	"container/heap"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/node"
+
+	"github.com/Fantom-foundation/go-lachesis/app"
+	"github.com/Fantom-foundation/go-lachesis/gossip"
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/inter/pos"
+	"github.com/Fantom-foundation/go-lachesis/lachesis"
+	"github.com/Fantom-foundation/go-lachesis/lachesis/genesis"
+	"github.com/Fantom-foundation/go-lachesis/poset"
+)
+
+type (
+	// SimNodeConfig describes a node of the simulated network.
+	SimNodeConfig struct {
+		// Validator is the genesis validator which the node emits events for, or 0 for a non-validator node.
+		// Several nodes of the same validator are the forking instances of it.
+		Validator idx.StakerID
+		// Standby is the takeover time of the hot-standby node of the validator, or 0 for a primary node.
+		Standby time.Duration
+		// ClockSkew is added to the virtual time to get the node's local time.
+		ClockSkew time.Duration
+		// Latency is added to the delivery time of the messages from and to the node.
+		Latency time.Duration
+		// Withhold returns the delay before the node's own event is sent to the peers,
+		// a negative delay withholds the event forever. All the events are sent without a delay if it's nil.
+		Withhold func(e *inter.Event) time.Duration
+		// Signer signs the validator's events instead of the node's accounts manager, the key isn't unlocked on the node then.
+		Signer gossip.EventSigner
+		// Snapshot is the snapshot file which the node state is applied from over the genesis, see WriteSnapshot.
+		Snapshot []byte
+	}
+
+	// SimConfig is the configuration of the in-process network simulation.
+	SimConfig struct {
+		Net   lachesis.Config
+		Nodes []SimNodeConfig
+
+		// EmitIntervals of the nodes emitters. SelfForkProtection is always disabled,
+		// because the simulated nodes have no peers to sync with.
+		EmitIntervals gossip.EmitIntervals
+		// TickInterval is the period of the emitters ticks.
+		TickInterval time.Duration
+		// ParentsStrategy is the parent-selection strategy of the emitters, the default one if empty.
+		ParentsStrategy string
+
+		// Seed of the links randomness.
+		Seed int64
+		// Latency is the minimal delivery time of a message, Jitter is the maximal random addition to it.
+		// Latency must be positive, otherwise the forking instances of a validator detect each other.
+		Latency time.Duration
+		Jitter  time.Duration
+		// Loss is the probability of a message to be lost. A lost message is sent again after the Retransmit timeout.
+		Loss       float64
+		Retransmit time.Duration
+	}
+
+	// SimNode is a node of the simulated network.
+	SimNode struct {
+		Config  SimNodeConfig
+		Service *gossip.Service
+		Engine  *poset.Poset
+		Store   *gossip.Store
+		App     *app.Store
+
+		// Rejected is the number of the received events which didn't pass the checks.
+		Rejected int
+		// Crashed node neither emits nor receives events.
+		Crashed bool
+
+		emitter *gossip.Emitter
+		known   map[hash.Event]bool
+		waiting map[hash.Event][]*inter.Event // received events by their missing parent
+		future  []*inter.Event                // received events of the next epochs
+	}
+
+	// Simulator runs the full nodes in one process. The nodes are connected by the in-memory links,
+	// and their emitters are driven by the virtual clock.
+	Simulator struct {
+		Nodes []*SimNode
+
+		cfg  SimConfig
+		now  time.Time
+		rand *rand.Rand
+
+		queue simQueue
+		seq   uint64
+
+		groups []int      // partition group of each node, nil if the network isn't partitioned
+		held   []*simTask // messages between partitions, they are delivered after the healing
+
+		// time-to-finality of the events, measured on the first running node
+		emittedAt map[hash.Event]time.Time
+		finalized idx.Block
+		finality  []time.Duration
+	}
+
+	simTask struct {
+		at   time.Time
+		seq  uint64
+		kind simTaskKind
+
+		from, to int
+		event    *inter.Event
+	}
+
+	simTaskKind int
+
+	simQueue []*simTask
+)
+
+const (
+	simTick    simTaskKind = iota // an emitter tick of the node
+	simDeliver                    // a delivery of the event from a node to another one
+	simRelease                    // a broadcast of the withheld event by its creator
+)
+
+// DefaultSimConfig returns the configuration of a fakenet of n validators, a node per validator.
+func DefaultSimConfig(validators int) SimConfig {
+	cfg := SimConfig{
+		Net:   lachesis.FakeNetConfig(genesis.FakeValidators(validators, big.NewInt(0), pos.StakeToBalance(1))),
+		Nodes: make([]SimNodeConfig, validators),
+
+		EmitIntervals: gossip.EmitIntervals{
+			Min:        100 * time.Millisecond,
+			Max:        time.Second,
+			Confirming: 100 * time.Millisecond,
+		},
+		TickInterval: 100 * time.Millisecond,
+
+		Latency:    50 * time.Millisecond,
+		Jitter:     50 * time.Millisecond,
+		Retransmit: time.Second,
+	}
+	for i := range cfg.Nodes {
+		cfg.Nodes[i].Validator = idx.StakerID(i + 1)
+	}
+	return cfg
+}
+
+// NewSimulator creates the nodes with the in-memory DBs and the genesis of cfg.Net.
+// The virtual time starts a minute after the genesis.
+func NewSimulator(cfg SimConfig) (*Simulator, error) {
+	if cfg.Latency <= 0 {
+		return nil, fmt.Errorf("latency must be positive")
+	}
+
+	s := &Simulator{
+		cfg:  cfg,
+		now:  cfg.Net.Genesis.Time.Time().Add(time.Minute),
+		rand: rand.New(rand.NewSource(cfg.Seed)),
+
+		emittedAt: make(map[hash.Event]time.Time),
+	}
+
+	for i, nodeCfg := range cfg.Nodes {
+		n, err := s.newNode(nodeCfg)
+		if err != nil {
+			return nil, err
+		}
+		s.Nodes = append(s.Nodes, n)
+		if n.emitter != nil {
+			// spread the first ticks of the nodes
+			s.schedule(&simTask{
+				at:   s.now.Add(time.Duration(s.rand.Int63n(int64(cfg.TickInterval)))),
+				kind: simTick,
+				to:   i,
+			})
+		}
+	}
+
+	return s, nil
+}
+
+func (s *Simulator) newNode(nodeCfg SimNodeConfig) (*SimNode, error) {
+	gossipCfg := gossip.DefaultConfig(s.cfg.Net)
+	gossipCfg.TxPool.Journal = ""
+	gossipCfg.Emitter.EmitIntervals = s.cfg.EmitIntervals
+	gossipCfg.Emitter.EmitIntervals.SelfForkProtection = 0
+	if s.cfg.ParentsStrategy != "" {
+		gossipCfg.Emitter.ParentsStrategy = s.cfg.ParentsStrategy
+	}
+
+	var validator common.Address
+	if nodeCfg.Validator != 0 {
+		for _, v := range s.cfg.Net.Genesis.Alloc.Validators {
+			if v.ID == nodeCfg.Validator {
+				validator = v.Address
+			}
+		}
+		if validator == (common.Address{}) {
+			return nil, fmt.Errorf("validator %d isn't found in genesis", nodeCfg.Validator)
+		}
+	}
+	gossipCfg.Emitter.Validator = validator
+	if nodeCfg.Standby != 0 {
+		gossipCfg.Emitter.HotStandby = gossip.HotStandbyConfig{
+			Enabled:  true,
+			Takeover: nodeCfg.Standby,
+		}
+	}
+
+	unlocked := validator
+	if nodeCfg.Signer != nil {
+		unlocked = common.Address{}
+	}
+
+	engine, adb, gdb := MakeEngine("inmemory", &gossipCfg)
+	if nodeCfg.Snapshot != nil {
+		if _, err := ApplySnapshot(bytes.NewReader(nodeCfg.Snapshot), engine, adb, gdb); err != nil {
+			return nil, err
+		}
+	}
+	ctx := &node.ServiceContext{
+		AccountManager: accounts.NewManager(
+			&accounts.Config{InsecureUnlockAllowed: true},
+			genesis.NewAccountsBackend(s.cfg.Net.Genesis.Alloc.Accounts, unlocked),
+		),
+	}
+	srv, err := gossip.NewService(ctx, &gossipCfg, gdb, engine, adb)
+	if err != nil {
+		return nil, err
+	}
+	srv.SetSlashingProtection(MakeSlashingProtection("inmemory", &gossipCfg))
+	if nodeCfg.Signer != nil {
+		srv.SetEventSigner(nodeCfg.Signer)
+	}
+
+	n := &SimNode{
+		Config:  nodeCfg,
+		Service: srv,
+		Engine:  engine,
+		Store:   gdb,
+		App:     adb,
+		known:   make(map[hash.Event]bool),
+		waiting: make(map[hash.Event][]*inter.Event),
+	}
+	if nodeCfg.Validator != 0 {
+		skew := nodeCfg.ClockSkew
+		n.emitter = srv.ManualEmitter(func() time.Time {
+			return s.now.Add(skew)
+		})
+	}
+	return n, nil
+}
+
+// AddNode starts a new node of the simulated network, e.g. the one which is bootstrapped from a snapshot.
+// The node gets the events which are emitted after its start only, see Sync.
+func (s *Simulator) AddNode(nodeCfg SimNodeConfig) (*SimNode, error) {
+	n, err := s.newNode(nodeCfg)
+	if err != nil {
+		return nil, err
+	}
+	s.Nodes = append(s.Nodes, n)
+	if n.emitter != nil {
+		s.schedule(&simTask{
+			at:   s.now.Add(s.cfg.TickInterval),
+			kind: simTick,
+			to:   len(s.Nodes) - 1,
+		})
+	}
+	return n, nil
+}
+
+// Sync connects the events which the node misses from another node, starting from the node's epoch.
+func (s *Simulator) Sync(to, from int) {
+	n := s.Nodes[to]
+	for epoch := n.Engine.GetEpoch(); epoch <= s.Nodes[from].Engine.GetEpoch(); epoch++ {
+		s.Nodes[from].Store.ForEachEventFrom(epoch, 0, func(e *inter.Event) bool {
+			s.deliver(to, e)
+			return true
+		})
+	}
+}
+
+// Stop stops the routines of the nodes services.
+func (s *Simulator) Stop() {
+	for _, n := range s.Nodes {
+		n.Service.StopManual()
+	}
+}
+
+// Now returns the virtual time.
+func (s *Simulator) Now() time.Time {
+	return s.now
+}
+
+// Run processes the emitters ticks and the messages for the d duration of the virtual time.
+func (s *Simulator) Run(d time.Duration) {
+	s.RunUntil(func() bool {
+		return false
+	}, d)
+}
+
+// RunUntil runs the simulation until the condition is true, but no longer than the limit of the virtual time.
+// Returns true if the condition is met.
+func (s *Simulator) RunUntil(cond func() bool, limit time.Duration) bool {
+	end := s.now.Add(limit)
+	for len(s.queue) != 0 && !s.queue[0].at.After(end) {
+		task := heap.Pop(&s.queue).(*simTask)
+		s.now = task.at
+		switch task.kind {
+		case simTick:
+			s.tick(task.to)
+		case simDeliver:
+			if s.groups != nil && s.groups[task.from] != s.groups[task.to] {
+				s.held = append(s.held, task)
+				break
+			}
+			s.deliver(task.to, task.event)
+		case simRelease:
+			if !s.Nodes[task.from].Crashed {
+				s.broadcast(task.from, task.event)
+			}
+		}
+		s.trackFinality()
+		if cond() {
+			return true
+		}
+	}
+	s.now = end
+	return cond()
+}
+
+// Partition splits the network into the groups of nodes, the messages between the groups are held until Heal.
+// The nodes which aren't listed are in a separate group.
+func (s *Simulator) Partition(groups ...[]int) {
+	s.groups = make([]int, len(s.Nodes))
+	for i, group := range groups {
+		for _, n := range group {
+			s.groups[n] = i + 1
+		}
+	}
+}
+
+// Crash stops the node forever.
+func (s *Simulator) Crash(i int) {
+	s.Nodes[i].Crashed = true
+}
+
+// Heal restores the links between the partitions and sends the held messages.
+func (s *Simulator) Heal() {
+	s.groups = nil
+	for _, task := range s.held {
+		s.send(task.from, task.to, task.event)
+	}
+	s.held = nil
+}
+
+// Finality returns the time-to-finality of the events which are decided by the first running node,
+// i.e. the virtual time from the event emission until its block decision.
+func (s *Simulator) Finality() []time.Duration {
+	return s.finality
+}
+
+// trackFinality measures the time-to-finality of the events of the new blocks.
+func (s *Simulator) trackFinality() {
+	running := s.running()
+	if len(running) == 0 {
+		return
+	}
+	n := running[0]
+	last, _ := n.Engine.LastBlock()
+	if last == s.finalized {
+		return
+	}
+	s.finalized = last
+
+	epoch := n.Engine.GetEpoch()
+	for id, emittedAt := range s.emittedAt {
+		if n.Engine.GetEventConfirmedOn(id) != 0 {
+			s.finality = append(s.finality, s.now.Sub(emittedAt))
+			delete(s.emittedAt, id)
+		} else if id.Epoch() < epoch {
+			// the events of the sealed epochs will never be confirmed
+			delete(s.emittedAt, id)
+		}
+	}
+}
+
+// CompareBlocks compares the blocks of the running nodes, up to the last block which is decided by all of them.
+// Returns the first difference from the blocks of the first running node, or nil if they are equal.
+func (s *Simulator) CompareBlocks() *Divergence {
+	last := s.LastDecidedBlock()
+	running := s.running()
+	if len(running) == 0 {
+		return nil
+	}
+	ref := running[0].Store
+	for n := idx.Block(1); n <= last; n++ {
+		epoch := ref.GetBlock(n).Atropos.Epoch()
+		for _, other := range running[1:] {
+			if d := compareBlocks(ref, other.Store, epoch, n, n); d != nil {
+				return d
+			}
+		}
+	}
+	return nil
+}
+
+// LastDecidedBlock returns the last block which is decided by all the running nodes.
+func (s *Simulator) LastDecidedBlock() idx.Block {
+	var last idx.Block
+	for i, n := range s.running() {
+		blockN, _ := n.Engine.LastBlock()
+		if i == 0 || blockN < last {
+			last = blockN
+		}
+	}
+	return last
+}
+
+func (s *Simulator) running() []*SimNode {
+	running := make([]*SimNode, 0, len(s.Nodes))
+	for _, n := range s.Nodes {
+		if !n.Crashed {
+			running = append(running, n)
+		}
+	}
+	return running
+}
+
+func (s *Simulator) tick(i int) {
+	n := s.Nodes[i]
+	if n.Crashed {
+		return
+	}
+	s.schedule(&simTask{
+		at:   s.now.Add(s.cfg.TickInterval),
+		kind: simTick,
+		to:   i,
+	})
+
+	e := n.emitter.Tick()
+	if e == nil {
+		return
+	}
+	s.emittedAt[e.Hash()] = s.now
+	n.known[e.Hash()] = true
+	s.connected(i, n.onConnected(e))
+
+	delay := time.Duration(0)
+	if n.Config.Withhold != nil {
+		delay = n.Config.Withhold(e)
+	}
+	if delay < 0 {
+		return
+	}
+	if delay == 0 {
+		s.broadcast(i, e)
+		return
+	}
+	s.schedule(&simTask{
+		at:    s.now.Add(delay),
+		kind:  simRelease,
+		from:  i,
+		event: e,
+	})
+}
+
+func (s *Simulator) deliver(to int, e *inter.Event) {
+	n := s.Nodes[to]
+	if n.Crashed || n.known[e.Hash()] {
+		return
+	}
+	n.known[e.Hash()] = true
+	s.connected(to, []*inter.Event{e})
+}
+
+// connected connects the ready events and the buffered ones which they unlock, and relays them.
+func (s *Simulator) connected(i int, ready []*inter.Event) {
+	n := s.Nodes[i]
+	for len(ready) != 0 {
+		e := ready[0]
+		ready = ready[1:]
+
+		if !n.connect(e) {
+			continue
+		}
+		s.broadcast(i, e)
+		ready = append(ready, n.onConnected(e)...)
+	}
+}
+
+// connect validates and processes the event, or buffers it until the parents or the epoch are known.
+// Returns true if the event is connected.
+func (n *SimNode) connect(e *inter.Event) bool {
+	epoch := n.Engine.GetEpoch()
+	if e.Epoch < epoch {
+		return false
+	}
+	if e.Epoch > epoch {
+		n.future = append(n.future, e)
+		return false
+	}
+	for _, p := range e.Parents {
+		if !n.Store.HasEventHeader(p) {
+			n.waiting[p] = append(n.waiting[p], e)
+			return false
+		}
+	}
+
+	err := n.Service.ImportEvent(e)
+	if err != nil {
+		log.Warn("Simulated event rejected", "event", e.Hash(), "creator", e.Creator, "err", err)
+		n.Rejected++
+		return false
+	}
+	return true
+}
+
+// onConnected returns the buffered events which may be connected after the event.
+func (n *SimNode) onConnected(e *inter.Event) []*inter.Event {
+	ready := n.waiting[e.Hash()]
+	delete(n.waiting, e.Hash())
+
+	if n.Engine.GetEpoch() != e.Epoch {
+		// the events of the sealed epoch will never be connected
+		n.waiting = make(map[hash.Event][]*inter.Event)
+		ready = append(ready, n.future...)
+		n.future = nil
+	}
+	return ready
+}
+
+// broadcast sends the event from the node to all the other nodes.
+func (s *Simulator) broadcast(from int, e *inter.Event) {
+	for to := range s.Nodes {
+		if to != from {
+			s.send(from, to, e)
+		}
+	}
+}
+
+// send schedules the event delivery with the link latency, the lost messages are delivered after retransmissions.
+func (s *Simulator) send(from, to int, e *inter.Event) {
+	delay := s.cfg.Latency + s.Nodes[from].Config.Latency + s.Nodes[to].Config.Latency
+	if s.cfg.Jitter > 0 {
+		delay += time.Duration(s.rand.Int63n(int64(s.cfg.Jitter)))
+	}
+	for s.cfg.Loss > 0 && s.rand.Float64() < s.cfg.Loss {
+		delay += s.cfg.Retransmit
+	}
+	s.schedule(&simTask{
+		at:    s.now.Add(delay),
+		kind:  simDeliver,
+		from:  from,
+		to:    to,
+		event: e,
+	})
+}
+
+func (s *Simulator) schedule(task *simTask) {
+	s.seq++
+	task.seq = s.seq
+	heap.Push(&s.queue, task)
+}
+
+func (q simQueue) Len() int {
+	return len(q)
+}
+
+func (q simQueue) Less(i, j int) bool {
+	if !q[i].at.Equal(q[j].at) {
+		return q[i].at.Before(q[j].at)
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q simQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+func (q *simQueue) Push(x interface{}) {
+	*q = append(*q, x.(*simTask))
+}
+
+func (q *simQueue) Pop() interface{} {
+	old := *q
+	task := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return task
+}
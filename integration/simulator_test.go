@@ -0,0 +1,584 @@
+package integration
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/gossip"
+	"github.com/Fantom-foundation/go-lachesis/gossip/extsigner"
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/lachesis"
+	"github.com/Fantom-foundation/go-lachesis/lachesis/genesis"
+	"github.com/Fantom-foundation/go-lachesis/lachesis/genesis/sfc"
+	"github.com/Fantom-foundation/go-lachesis/light"
+	"github.com/Fantom-foundation/go-lachesis/logger"
+)
+
+func TestSimulatorPartition(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	cfg := DefaultSimConfig(7)
+	cfg.Loss = 0.1
+	sim, err := NewSimulator(cfg)
+	if !assertar.NoError(err) {
+		return
+	}
+	defer sim.Stop()
+
+	sim.Run(10 * time.Second)
+	before := sim.LastDecidedBlock()
+	assertar.NotZero(before)
+
+	// the minority doesn't decide blocks while it's separated, the majority has more than 2/3 of stake
+	sim.Partition([]int{0, 1, 2, 3, 4}, []int{5, 6})
+	sim.Run(10 * time.Second)
+	minority, _ := sim.Nodes[6].Engine.LastBlock()
+	majority, _ := sim.Nodes[0].Engine.LastBlock()
+	assertar.True(minority <= before+1, "minority decided blocks")
+	assertar.True(majority > before+1, "majority is stuck")
+
+	// the minority catches up after the healing
+	sim.Heal()
+	assertar.True(sim.RunUntil(func() bool {
+		return sim.LastDecidedBlock() > majority
+	}, 30*time.Second))
+	assertar.Nil(sim.CompareBlocks())
+	for _, n := range sim.Nodes {
+		assertar.Zero(n.Rejected)
+	}
+}
+
+func TestSimulatorForkingValidator(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	cfg := DefaultSimConfig(5)
+	const cheater = idx.StakerID(5)
+	// the second instance of the validator
+	cfg.Nodes = append(cfg.Nodes, SimNodeConfig{
+		Validator: cheater,
+	})
+	sim, err := NewSimulator(cfg)
+	if !assertar.NoError(err) {
+		return
+	}
+	defer sim.Stop()
+
+	detected := func() bool {
+		for _, n := range sim.Nodes {
+			if len(n.Store.GetForkEvidences(cheater)) == 0 {
+				return false
+			}
+		}
+		return true
+	}
+	if !assertar.True(sim.RunUntil(detected, time.Minute), "cheater isn't detected") {
+		return
+	}
+
+	// the finality goes on without the cheater
+	last := sim.LastDecidedBlock()
+	assertar.True(sim.RunUntil(func() bool {
+		return sim.LastDecidedBlock() > last+5
+	}, time.Minute))
+	assertar.Nil(sim.CompareBlocks())
+}
+
+func TestSimulatorWithholdAndSkew(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	cfg := DefaultSimConfig(5)
+	cfg.Seed = 1
+	// validator 4 releases its events 3 seconds late
+	cfg.Nodes[3].Withhold = func(e *inter.Event) time.Duration {
+		return 3 * time.Second
+	}
+	// validator 5 never releases its events, after the first one
+	cfg.Nodes[4].Withhold = func(e *inter.Event) time.Duration {
+		if e.Seq > 1 {
+			return -1
+		}
+		return 0
+	}
+	// clocks of validators 1 and 2 are skewed
+	cfg.Nodes[0].ClockSkew = 2 * time.Second
+	cfg.Nodes[1].ClockSkew = -time.Second
+	sim, err := NewSimulator(cfg)
+	if !assertar.NoError(err) {
+		return
+	}
+	defer sim.Stop()
+
+	assertar.True(sim.RunUntil(func() bool {
+		return sim.LastDecidedBlock() >= 10
+	}, time.Minute))
+	assertar.Nil(sim.CompareBlocks())
+
+	// the claimed times of the events are the local times of the nodes
+	for n := idx.Block(1); n <= 10; n++ {
+		block := sim.Nodes[2].Store.GetBlock(n)
+		atropos := sim.Nodes[2].Store.GetEventHeader(block.Atropos.Epoch(), block.Atropos)
+		if atropos.Creator == 1 {
+			assertar.True(atropos.ClaimedTime > atropos.MedianTime)
+		}
+		assertar.NotEqual(idx.StakerID(5), atropos.Creator)
+	}
+}
+
+func TestSimulatorFinalityProof(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	cfg := DefaultSimConfig(5)
+	cfg.Net.Dag.MaxEpochBlocks = 10
+	sim, err := NewSimulator(cfg)
+	if !assertar.NoError(err) {
+		return
+	}
+	defer sim.Stop()
+
+	assertar.True(sim.RunUntil(func() bool {
+		return sim.LastDecidedBlock() >= 5
+	}, time.Minute))
+
+	// the proofs of the current epoch
+	svc := sim.Nodes[0].Service
+	for n := idx.Block(1); n <= 3; n++ {
+		proof, err := svc.GetFinalityProof(n)
+		if !assertar.NoError(err) {
+			return
+		}
+		assertar.NoError(proof.Verify())
+		assertar.Equal(sim.Nodes[0].Store.GetBlock(n).Atropos, proof.Atropos)
+		assertar.Equal(idx.Block(0), proof.EpochStart)
+	}
+
+	// the proofs of a sealed epoch are the same
+	assertar.True(sim.RunUntil(func() bool {
+		return sim.Nodes[1].Engine.GetEpoch() >= 3
+	}, time.Minute))
+	for n := idx.Block(1); n <= 3; n++ {
+		proof, err := sim.Nodes[1].Service.GetFinalityProof(n)
+		if !assertar.NoError(err) {
+			return
+		}
+		assertar.NoError(proof.Verify())
+		exp, _ := svc.GetFinalityProof(n)
+		assertar.Equal(exp, proof)
+	}
+	proof, err := sim.Nodes[1].Service.GetFinalityProof(15)
+	if assertar.NoError(err) {
+		assertar.NoError(proof.Verify())
+		assertar.Equal(idx.Block(10), proof.EpochStart)
+	}
+
+	_, err = svc.GetFinalityProof(0)
+	assertar.Error(err)
+	_, err = svc.GetFinalityProof(1000)
+	assertar.Error(err)
+}
+
+func TestSimulatorSnapshotRequest(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	cfg := DefaultSimConfig(3)
+	cfg.Net.Dag.MaxEpochBlocks = 5
+	sim, err := NewSimulator(cfg)
+	if !assertar.NoError(err) {
+		return
+	}
+	defer sim.Stop()
+
+	node := sim.Nodes[0]
+	api := gossip.NewPrivateSnapshotAPI(node.Service)
+
+	// the epochs aren't captured by default
+	assertar.True(sim.RunUntil(func() bool {
+		return node.Engine.GetEpoch() >= 2
+	}, time.Minute))
+	assertar.Nil(node.Store.GetSnapshot())
+	assertar.Equal(false, api.SnapshotStatus()["requested"])
+
+	epoch := idx.Epoch(api.RequestSnapshot())
+	assertar.Equal(true, api.SnapshotStatus()["requested"])
+	assertar.True(sim.RunUntil(func() bool {
+		return node.Engine.GetEpoch() >= epoch+1
+	}, time.Minute))
+
+	// only the requested epoch is captured
+	snap := node.Store.GetSnapshot()
+	if assertar.NotNil(snap) {
+		assertar.Equal(epoch, snap.Epoch)
+		assertar.NotNil(node.Engine.GetSnapshot(snap.Epoch))
+	}
+	assertar.Equal(false, api.SnapshotStatus()["requested"])
+}
+
+func TestSimulatorLightClient(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	cfg := DefaultSimConfig(4)
+	cfg.Net.Dag.MaxEpochBlocks = 10
+	sim, err := NewSimulator(cfg)
+	if !assertar.NoError(err) {
+		return
+	}
+	defer sim.Stop()
+
+	node := sim.Nodes[0]
+	if !assertar.True(sim.RunUntil(func() bool {
+		return node.Engine.GetEpoch() >= 4
+	}, time.Minute)) {
+		return
+	}
+	// the first events of the last sealed epoch's successor are needed
+	sim.Run(5 * time.Second)
+
+	client := light.NewClient(light.GenesisCheckpoint(&cfg.Net))
+	for epoch := idx.Epoch(1); epoch < 4; epoch++ {
+		proof, err := node.Service.GetEpochProof(epoch)
+		if !assertar.NoError(err) {
+			return
+		}
+		// the client receives the proof encoded
+		raw, err := rlp.EncodeToBytes(proof)
+		if !assertar.NoError(err) {
+			return
+		}
+		decoded := &light.EpochProof{}
+		if !assertar.NoError(rlp.DecodeBytes(raw, decoded)) {
+			return
+		}
+		if !assertar.NoError(client.ApplyEpoch(decoded)) {
+			return
+		}
+	}
+	cp := client.Checkpoint()
+	assertar.Equal(idx.Epoch(4), cp.Epoch)
+	assertar.Equal(node.Engine.GetValidators().SortedIDs(), cp.Validators.SortedIDs())
+
+	// a block of a verified epoch
+	n := cp.LastBlock - 3
+	block := node.Store.GetBlock(n)
+	assertar.Equal(block.Root, client.Block(n).Root)
+	statedb := node.App.StateDB(block.Root)
+
+	address := sfc.ContractAddress
+	accountProof, err := statedb.GetProof(address)
+	if !assertar.NoError(err) {
+		return
+	}
+	account, err := client.VerifyAccount(n, address, accountProof)
+	if !assertar.NoError(err) || !assertar.NotNil(account) {
+		return
+	}
+	assertar.Equal(statedb.GetBalance(address), account.Balance)
+	assertar.Equal(statedb.GetNonce(address), account.Nonce)
+
+	key := common.Hash{}
+	storageProof, err := statedb.GetStorageProof(address, key)
+	if !assertar.NoError(err) {
+		return
+	}
+	value, err := light.VerifyStorage(account.Root, key, storageProof)
+	assertar.NoError(err)
+	assertar.Equal(statedb.GetState(address, key), value)
+
+	// the validators' accounts have no balance, so they don't exist
+	empty := cfg.Net.Genesis.Alloc.Validators[0].Address
+	emptyProof, err := statedb.GetProof(empty)
+	if !assertar.NoError(err) {
+		return
+	}
+	account, err = client.VerifyAccount(n, empty, emptyProof)
+	assertar.NoError(err)
+	assertar.Nil(account)
+
+	// a block of not verified epoch
+	_, err = client.VerifyAccount(cp.LastBlock+1, address, accountProof)
+	assertar.Equal(light.ErrUnknownBlock, err)
+	// a proof of other block
+	_, err = client.VerifyAccount(cp.LastBlock, address, accountProof)
+	assertar.Error(err)
+
+	// the current epoch isn't sealed
+	_, err = node.Service.GetEpochProof(node.Engine.GetEpoch())
+	assertar.Error(err)
+}
+
+func TestSimulatorEpochSealingPolicy(t *testing.T) {
+	logger.SetTestMode(t)
+
+	run := func(policy string) idx.Epoch {
+		assertar := assert.New(t)
+
+		cfg := DefaultSimConfig(4)
+		cfg.Net.Dag.MaxEpochBlocks = 1000
+		cfg.Net.Dag.MaxEpochDuration = time.Second
+		cfg.Net.Dag.EpochSealing.Policy = policy
+		sim, err := NewSimulator(cfg)
+		if !assertar.NoError(err) {
+			t.FailNow()
+		}
+		defer sim.Stop()
+
+		sim.Run(20 * time.Second)
+		assertar.NotZero(sim.LastDecidedBlock())
+		assertar.Nil(sim.CompareBlocks())
+		return sim.Nodes[0].Engine.GetEpoch()
+	}
+
+	// the epoch duration is ignored by the blocks policy
+	assert.True(t, run(lachesis.DefaultSealingPolicy) > 2)
+	assert.Equal(t, idx.Epoch(1), run(lachesis.BlocksSealingPolicy))
+	// the stakers don't change
+	assert.Equal(t, idx.Epoch(1), run(lachesis.StakersSealingPolicy))
+}
+
+func TestSimulatorUpgrade(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	cfg := DefaultSimConfig(4)
+	cfg.Net.Dag.MaxEpochBlocks = 10
+	// longer epochs and fewer parents from the epoch 3
+	dag := cfg.Net.Dag
+	dag.MaxEpochBlocks = 1000
+	dag.MaxParents = 3
+	// the light client proofs are available since the epoch 2
+	cfg.Net.Upgrades = []lachesis.Upgrade{
+		lachesis.LightClientUpgrade(2),
+		{
+			Name:  "long epochs",
+			Epoch: 3,
+			Dag:   &dag,
+		},
+	}
+	sim, err := NewSimulator(cfg)
+	if !assertar.NoError(err) {
+		return
+	}
+	defer sim.Stop()
+
+	if !assertar.True(sim.RunUntil(func() bool {
+		return sim.Nodes[0].Engine.GetEpoch() >= 3
+	}, time.Minute)) {
+		return
+	}
+	before := sim.LastDecidedBlock()
+	sim.Run(30 * time.Second)
+	assertar.True(sim.LastDecidedBlock() > before+10, "blocks aren't decided after the upgrade")
+	assertar.Equal(idx.Epoch(3), sim.Nodes[0].Engine.GetEpoch())
+	assertar.Nil(sim.CompareBlocks())
+	_, err = sim.Nodes[0].Service.GetEpochProof(1)
+	assertar.Error(err)
+	_, err = sim.Nodes[0].Service.GetEpochProof(2)
+	assertar.NoError(err)
+	for _, n := range sim.Nodes {
+		assertar.Zero(n.Rejected)
+		// the events of the upgraded epoch follow the new rules
+		n.Store.ForEachEvent(3, func(e *inter.Event) bool {
+			assertar.True(len(e.Parents) <= 3)
+			return true
+		})
+	}
+}
+
+func TestSimulatorHotStandby(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	// no finality without any of the validators
+	cfg := DefaultSimConfig(3)
+	const primary = 2
+	validator := cfg.Nodes[primary].Validator
+	cfg.Nodes = append(cfg.Nodes, SimNodeConfig{
+		Validator: validator,
+		Standby:   5 * time.Second,
+	})
+	sim, err := NewSimulator(cfg)
+	if !assertar.NoError(err) {
+		return
+	}
+	defer sim.Stop()
+
+	// the standby doesn't emit while the primary is online
+	sim.Run(20 * time.Second)
+	before := sim.LastDecidedBlock()
+	assertar.True(before > 5, "no finality")
+	assertar.Empty(sim.Nodes[0].Store.GetForkEvidences(validator))
+
+	sim.Crash(primary)
+	sim.Run(3 * time.Second)
+	stalled := sim.LastDecidedBlock()
+
+	// the standby takes over, and the finality goes on
+	assertar.True(sim.RunUntil(func() bool {
+		return sim.LastDecidedBlock() > stalled+5
+	}, time.Minute), "standby hasn't taken over")
+	assertar.Nil(sim.CompareBlocks())
+	for i, n := range sim.Nodes {
+		if i == primary {
+			continue
+		}
+		assertar.Empty(n.Store.GetForkEvidences(validator))
+		assertar.Zero(n.Rejected)
+	}
+}
+
+func TestSimulatorEmitterControl(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	// no finality without any of the validators
+	cfg := DefaultSimConfig(3)
+	sim, err := NewSimulator(cfg)
+	if !assertar.NoError(err) {
+		return
+	}
+	defer sim.Stop()
+
+	api := gossip.NewPrivateEmitterAPI(sim.Nodes[0].Service)
+	status, err := api.EmitterStatus()
+	if !assertar.NoError(err) {
+		return
+	}
+	validator := status["validator"].(common.Address)
+	assertar.Equal(idx.StakerID(1), status["stakerID"])
+	assertar.Equal(true, status["synced"])
+
+	sim.Run(10 * time.Second)
+	status, err = api.EmitterStatus()
+	assertar.NoError(err)
+	assertar.NotNil(status["lastEvent"])
+	assertar.NotNil(status["gasPowerLeft"])
+
+	// drain the validator
+	_, err = api.EmitterSetValidator(common.Address{}, nil)
+	assertar.NoError(err)
+	status, err = api.EmitterStatus()
+	assertar.NoError(err)
+	assertar.Equal(idx.StakerID(0), status["stakerID"])
+	sim.Run(3 * time.Second)
+	stalled := sim.LastDecidedBlock()
+	sim.Run(10 * time.Second)
+	assertar.True(sim.LastDecidedBlock() <= stalled+1, "finality without the validator")
+
+	// unknown key
+	_, err = api.EmitterSetValidator(common.Address{1}, nil)
+	assertar.Error(err)
+
+	_, err = api.EmitterSetValidator(validator, nil)
+	assertar.NoError(err)
+	assertar.True(sim.RunUntil(func() bool {
+		return sim.LastDecidedBlock() > stalled+5
+	}, time.Minute), "validator hasn't resumed")
+
+	// intervals
+	min, max := "50ms", "1s"
+	intervals, err := api.EmitterSetIntervals(gossip.EmitIntervalsArgs{Min: &min})
+	assertar.NoError(err)
+	assertar.Equal("50ms", intervals["min"])
+	min = "2s"
+	_, err = api.EmitterSetIntervals(gossip.EmitIntervalsArgs{Min: &min, Max: &max})
+	assertar.Error(err)
+	min = "-1s"
+	_, err = api.EmitterSetIntervals(gossip.EmitIntervalsArgs{Min: &min})
+	assertar.Error(err)
+	assertar.Equal(50*time.Millisecond, sim.Nodes[0].emitter.GetIntervals().Min)
+
+	// thresholds
+	noTxs := hexutil.Uint64(1000)
+	thresholds, err := api.EmitterSetGasPowerThresholds(gossip.GasPowerThresholdsArgs{NoTxs: &noTxs})
+	assertar.NoError(err)
+	assertar.Equal(noTxs, thresholds["noTxs"])
+	assertar.Equal(gossip.DefaultEmitterConfig().EmergencyThreshold, uint64(thresholds["emergency"].(hexutil.Uint64)))
+}
+
+func TestSimulatorExternalSigner(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	cfg := DefaultSimConfig(3)
+	v := cfg.Net.Genesis.Alloc.Validators[0]
+	assertar.Equal(cfg.Nodes[0].Validator, v.ID)
+
+	// the stand-in signer keeps the key instead of the nodes
+	am := accounts.NewManager(&accounts.Config{}, genesis.NewAccountsBackend(cfg.Net.Genesis.Alloc.Accounts, v.Address))
+	gossipCfg := gossip.DefaultConfig(cfg.Net)
+	signer := rpc.NewServer()
+	defer signer.Stop()
+	if !assertar.NoError(signer.RegisterName(extsigner.Namespace, extsigner.NewService(am, MakeSlashingProtection("inmemory", &gossipCfg)))) {
+		return
+	}
+	client := extsigner.NewClient(rpc.DialInProc(signer), time.Second)
+	defer client.Close()
+
+	// the forking instance of the validator is refused by the signer
+	cfg.Nodes[0].Signer = client
+	cfg.Nodes = append(cfg.Nodes, SimNodeConfig{
+		Validator: v.ID,
+		Signer:    client,
+	})
+	sim, err := NewSimulator(cfg)
+	if !assertar.NoError(err) {
+		return
+	}
+	defer sim.Stop()
+
+	assertar.True(sim.RunUntil(func() bool {
+		return sim.LastDecidedBlock() > 5
+	}, time.Minute), "no finality")
+	assertar.Nil(sim.CompareBlocks())
+	for _, n := range sim.Nodes {
+		assertar.Empty(n.Store.GetForkEvidences(v.ID))
+		assertar.Zero(n.Rejected)
+	}
+
+	// the key isn't on the node
+	password := "fakepassword"
+	_, err = gossip.NewPrivateEmitterAPI(sim.Nodes[0].Service).EmitterSetValidator(v.Address, &password)
+	assertar.Error(err)
+}
+
+type failingSigner struct {
+	calls int
+}
+
+func (s *failingSigner) SignEvent(validator common.Address, e *inter.EventHeaderData) ([]byte, error) {
+	s.calls++
+	return nil, errors.New("signer is unavailable")
+}
+
+func TestSimulatorExternalSignerBackoff(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	cfg := DefaultSimConfig(3)
+	signer := &failingSigner{}
+	cfg.Nodes[0].Signer = signer
+	sim, err := NewSimulator(cfg)
+	if !assertar.NoError(err) {
+		return
+	}
+	defer sim.Stop()
+
+	// the emitter doesn't retry on every tick after the failures
+	sim.Run(10 * time.Second)
+	assertar.NotZero(signer.calls)
+	assertar.True(signer.calls <= 6, signer.calls)
+	assertar.Nil(sim.Nodes[0].Store.GetLastEvent(sim.Nodes[0].Engine.GetEpoch(), cfg.Nodes[0].Validator))
+}
@@ -0,0 +1,199 @@
+package integration
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/Fantom-foundation/go-lachesis/app"
+	"github.com/Fantom-foundation/go-lachesis/gossip"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/poset"
+)
+
+const (
+	// snapshotFileVersion is the current version of the snapshot file format.
+	snapshotFileVersion = 1
+
+	appRecord = 1
+	evmRecord = 2
+)
+
+var (
+	// snapshotFileMagic is an eyecatcher at the start of the snapshot file.
+	snapshotFileMagic = [4]byte{'L', 'S', 'N', 'P'}
+
+	ErrNotSnapshotFile     = errors.New("not a snapshot file")
+	ErrSnapshotFileVersion = errors.New("unsupported snapshot file version")
+	ErrSnapshotFileGenesis = errors.New("snapshot file is for another genesis")
+	ErrNoSnapshot          = errors.New("no snapshot is captured, enable epoch snapshots and wait for the next epoch")
+)
+
+type (
+	// snapshotFileHeader is written at the start of the snapshot file.
+	snapshotFileHeader struct {
+		Magic   [4]byte
+		Version uint32
+		Genesis common.Hash
+		Epoch   idx.Epoch
+	}
+
+	// snapshotRecord is a raw app or EVM record of the snapshot file.
+	snapshotRecord struct {
+		Kind  uint8
+		Key   []byte
+		Value []byte
+	}
+)
+
+func writeSnapshotFileHeader(w io.Writer, genesis common.Hash, epoch idx.Epoch) error {
+	return rlp.Encode(w, &snapshotFileHeader{
+		Magic:   snapshotFileMagic,
+		Version: snapshotFileVersion,
+		Genesis: genesis,
+		Epoch:   epoch,
+	})
+}
+
+func readSnapshotFileHeader(stream *rlp.Stream, genesis common.Hash) (idx.Epoch, error) {
+	var header snapshotFileHeader
+	if err := stream.Decode(&header); err != nil {
+		return 0, ErrNotSnapshotFile
+	}
+	if header.Magic != snapshotFileMagic {
+		return 0, ErrNotSnapshotFile
+	}
+	if header.Version != snapshotFileVersion {
+		return 0, ErrSnapshotFileVersion
+	}
+	if header.Genesis != genesis {
+		return 0, ErrSnapshotFileGenesis
+	}
+	return header.Epoch, nil
+}
+
+// WriteSnapshot writes the state at the beginning of the last captured epoch.
+// The snapshot consists of:
+// - poset Checkpoint and EpochState,
+// - gossip last block, epoch stats and last headers,
+// - app SFC/economy tables,
+// - EVM state trie of the last block.
+func WriteSnapshot(w io.Writer, engine *poset.Poset, adb *app.Store, gdb *gossip.Store) (idx.Epoch, error) {
+	gsnap := gdb.GetSnapshot()
+	if gsnap == nil {
+		return 0, ErrNoSnapshot
+	}
+	psnap := engine.GetSnapshot(gsnap.Epoch)
+	if psnap == nil {
+		return 0, fmt.Errorf("no poset state of epoch %d", gsnap.Epoch)
+	}
+
+	err := writeSnapshotFileHeader(w, engine.GetGenesisHash(), gsnap.Epoch)
+	if err != nil {
+		return 0, err
+	}
+	if err = rlp.Encode(w, psnap); err != nil {
+		return 0, err
+	}
+	if err = rlp.Encode(w, gsnap); err != nil {
+		return 0, err
+	}
+
+	captured := adb.ForEachSnapshotRecord(gsnap.Epoch, func(key, value []byte) bool {
+		err = rlp.Encode(w, &snapshotRecord{appRecord, key, value})
+		return err == nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if !captured {
+		return 0, fmt.Errorf("no app state of epoch %d", gsnap.Epoch)
+	}
+
+	statedb, err := state.New(gsnap.Block.Root, state.NewDatabase(adb.EvmTable()))
+	if err != nil {
+		return 0, err
+	}
+	it := state.NewNodeIterator(statedb)
+	for it.Next() {
+		if it.Hash == (common.Hash{}) {
+			// embedded node
+			continue
+		}
+		value, err := adb.EvmTable().Get(it.Hash.Bytes())
+		if err != nil {
+			return 0, err
+		}
+		if err = rlp.Encode(w, &snapshotRecord{evmRecord, it.Hash.Bytes(), value}); err != nil {
+			return 0, err
+		}
+	}
+	if it.Error != nil {
+		return 0, it.Error
+	}
+
+	return gsnap.Epoch, nil
+}
+
+// ApplySnapshot writes the state from the snapshot file over the genesis state.
+// The engine mustn't be bootstrapped yet, stores aren't flushed.
+func ApplySnapshot(r io.Reader, engine *poset.Poset, adb *app.Store, gdb *gossip.Store) (idx.Epoch, error) {
+	stream := rlp.NewStream(r, 0)
+	epoch, err := readSnapshotFileHeader(stream, engine.GetGenesisHash())
+	if err != nil {
+		return 0, err
+	}
+
+	psnap := &poset.Snapshot{}
+	if err = stream.Decode(psnap); err != nil {
+		return 0, err
+	}
+	gsnap := &gossip.Snapshot{}
+	if err = stream.Decode(gsnap); err != nil {
+		return 0, err
+	}
+	if psnap.Epoch.EpochN != epoch || gsnap.Epoch != epoch || gsnap.Block == nil {
+		return 0, fmt.Errorf("inconsistent snapshot of epoch %d", epoch)
+	}
+
+	for {
+		var rec snapshotRecord
+		err = stream.Decode(&rec)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		switch rec.Kind {
+		case appRecord:
+			err = adb.ApplySnapshotRecord(rec.Key, rec.Value)
+		case evmRecord:
+			err = adb.EvmTable().Put(rec.Key, rec.Value)
+		default:
+			err = fmt.Errorf("unknown snapshot record kind %d", rec.Kind)
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	// check the EVM state root is written
+	if _, err = state.New(gsnap.Block.Root, state.NewDatabase(adb.EvmTable())); err != nil {
+		return 0, err
+	}
+
+	if err = gdb.ApplySnapshot(gsnap); err != nil {
+		return 0, err
+	}
+	if err = engine.ApplySnapshot(psnap); err != nil {
+		return 0, err
+	}
+
+	return epoch, nil
+}
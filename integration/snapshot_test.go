@@ -0,0 +1,95 @@
+package integration
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/gossip"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/logger"
+)
+
+func TestSnapshotFileHeader(t *testing.T) {
+	assertar := assert.New(t)
+
+	genesis := common.HexToHash("0x1234")
+	other := common.HexToHash("0x5678")
+
+	buf := bytes.NewBuffer(nil)
+	assertar.NoError(writeSnapshotFileHeader(buf, genesis, 5))
+	data := buf.Bytes()
+
+	epoch, err := readSnapshotFileHeader(rlp.NewStream(bytes.NewReader(data), 0), genesis)
+	assertar.NoError(err)
+	assertar.Equal(idx.Epoch(5), epoch)
+
+	_, err = readSnapshotFileHeader(rlp.NewStream(bytes.NewReader(data), 0), other)
+	assertar.Equal(ErrSnapshotFileGenesis, err)
+	_, err = readSnapshotFileHeader(rlp.NewStream(bytes.NewReader([]byte{0x01, 0x02}), 0), genesis)
+	assertar.Equal(ErrNotSnapshotFile, err)
+}
+
+func TestSnapshotBootstrap(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	cfg := DefaultSimConfig(3)
+	cfg.Net.Dag.MaxEpochBlocks = 5
+	sim, err := NewSimulator(cfg)
+	if !assertar.NoError(err) {
+		return
+	}
+	defer sim.Stop()
+
+	node := sim.Nodes[0]
+	epoch := idx.Epoch(gossip.NewPrivateSnapshotAPI(node.Service).RequestSnapshot())
+	if !assertar.True(sim.RunUntil(func() bool {
+		return node.Engine.GetEpoch() >= epoch+1
+	}, time.Minute)) {
+		return
+	}
+
+	buf := bytes.NewBuffer(nil)
+	written, err := WriteSnapshot(buf, node.Engine, node.App, node.Store)
+	if !assertar.NoError(err) {
+		return
+	}
+	assertar.Equal(epoch, written)
+	snapBlock := node.Store.GetSnapshot().Block.Index
+
+	// a fresh node starts from the snapshot epoch, without the history before it
+	fresh, err := sim.AddNode(SimNodeConfig{
+		Snapshot: buf.Bytes(),
+	})
+	if !assertar.NoError(err) {
+		return
+	}
+	assertar.Equal(epoch, fresh.Engine.GetEpoch())
+	last, _ := fresh.Engine.LastBlock()
+	assertar.Equal(snapBlock, last)
+	assertar.Nil(fresh.Store.GetBlock(snapBlock - 1))
+
+	// it syncs the snapshot epoch and keeps up with the network in the next ones
+	sim.Sync(len(sim.Nodes)-1, 0)
+	if !assertar.True(sim.RunUntil(func() bool {
+		return fresh.Engine.GetEpoch() >= epoch+3
+	}, time.Minute)) {
+		return
+	}
+	assertar.Zero(fresh.Rejected)
+
+	last = sim.LastDecidedBlock()
+	for n := snapBlock + 1; n <= last; n++ {
+		if !assertar.NotNil(fresh.Store.GetBlock(n), n) {
+			return
+		}
+		epoch := node.Store.GetBlock(n).Atropos.Epoch()
+		assertar.Nil(compareBlocks(node.Store, fresh.Store, epoch, n, n))
+	}
+	assertar.Equal(node.App.GetEpochValidators(epoch+2), fresh.App.GetEpochValidators(epoch+2))
+}
@@ -0,0 +1,241 @@
+package ancestor
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/inter/pos"
+	"github.com/Fantom-foundation/go-lachesis/poset/election"
+	"github.com/Fantom-foundation/go-lachesis/vector"
+)
+
+// CreatorOf returns the creator of the event.
+type CreatorOf func(id hash.Event) idx.StakerID
+
+/*
+ * StakeWeightedStrategy
+ */
+
+// StakeWeightedStrategy chooses a random option, the probability is proportional to the stake of the option's creator
+type StakeWeightedStrategy struct {
+	r          *rand.Rand
+	validators *pos.Validators
+	creatorOf  CreatorOf
+}
+
+// NewStakeWeightedStrategy creates new StakeWeightedStrategy
+func NewStakeWeightedStrategy(r *rand.Rand, validators *pos.Validators, creatorOf CreatorOf) *StakeWeightedStrategy {
+	if r == nil {
+		r = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	return &StakeWeightedStrategy{
+		r:          r,
+		validators: validators,
+		creatorOf:  creatorOf,
+	}
+}
+
+// Init must be called before using the strategy
+func (st *StakeWeightedStrategy) Init(selfParent *hash.Event) {}
+
+// Find chooses the hash from the specified options
+func (st *StakeWeightedStrategy) Find(options hash.Events) hash.Event {
+	stakes := make([]pos.Stake, len(options))
+	var total pos.Stake
+	for i, id := range options {
+		stakes[i] = st.validators.Get(st.creatorOf(id))
+		total += stakes[i]
+	}
+	if total == 0 {
+		return options[st.r.Intn(len(options))]
+	}
+
+	point := pos.Stake(st.r.Int63n(int64(total)))
+	for i, stake := range stakes {
+		if point < stake {
+			return options[i]
+		}
+		point -= stake
+	}
+	return options[len(options)-1]
+}
+
+/*
+ * LatencyStrategy
+ */
+
+// Latencies is the moving average of the observed events latency per creator.
+// Not safe for concurrent use.
+type Latencies struct {
+	avg map[idx.StakerID]time.Duration
+}
+
+// latencyAvgWeight is the weight of the new observation in the moving average
+const latencyAvgWeight = 8
+
+// NewLatencies creates new Latencies.
+func NewLatencies() *Latencies {
+	return &Latencies{
+		avg: make(map[idx.StakerID]time.Duration),
+	}
+}
+
+// Observe adds the observed latency of the creator's event.
+func (l *Latencies) Observe(creator idx.StakerID, latency time.Duration) {
+	avg, ok := l.avg[creator]
+	if !ok {
+		l.avg[creator] = latency
+		return
+	}
+	l.avg[creator] = avg + (latency-avg)/latencyAvgWeight
+}
+
+// Get returns the average latency of the creator's events, or false if nothing is observed.
+func (l *Latencies) Get(creator idx.StakerID) (time.Duration, bool) {
+	avg, ok := l.avg[creator]
+	return avg, ok
+}
+
+// LatencyStrategy chooses the option whose creator has the lowest observed latency.
+// The creators with unknown latency are chosen last.
+type LatencyStrategy struct {
+	latencies *Latencies
+	creatorOf CreatorOf
+}
+
+// NewLatencyStrategy creates new LatencyStrategy
+func NewLatencyStrategy(latencies *Latencies, creatorOf CreatorOf) *LatencyStrategy {
+	return &LatencyStrategy{
+		latencies: latencies,
+		creatorOf: creatorOf,
+	}
+}
+
+// Init must be called before using the strategy
+func (st *LatencyStrategy) Init(selfParent *hash.Event) {}
+
+// Find chooses the hash from the specified options
+func (st *LatencyStrategy) Find(options hash.Events) hash.Event {
+	best := options[0]
+	bestLatency, bestKnown := st.latencies.Get(st.creatorOf(best))
+	for _, id := range options[1:] {
+		latency, known := st.latencies.Get(st.creatorOf(id))
+		if !known {
+			continue
+		}
+		if !bestKnown || latency < bestLatency {
+			best, bestLatency, bestKnown = id, latency, true
+		}
+	}
+	return best
+}
+
+/*
+ * RootsStrategy
+ */
+
+// RootsStrategy uses vector clock to choose the options which make the new event forkless-cause
+// the most stake of the roots, i.e. the parents which bring the new event closer to the next frame.
+// The ties are broken by observing more, as in CasualityStrategy.
+type RootsStrategy struct {
+	vecClock   *vector.Index
+	validators *pos.Validators
+	roots      []election.RootAndSlot
+
+	rootsAfter []vector.LowestAfterSeq
+	merged     vector.HighestBeforeSeq
+}
+
+// NewRootsStrategy creates new RootsStrategy with provided vector clock and the roots of the highest known frame
+func NewRootsStrategy(vecClock *vector.Index, validators *pos.Validators, roots []election.RootAndSlot) *RootsStrategy {
+	return &RootsStrategy{
+		vecClock:   vecClock,
+		validators: validators,
+		roots:      roots,
+	}
+}
+
+// Init must be called before using the strategy
+func (st *RootsStrategy) Init(selfParent *hash.Event) {
+	st.merged = vector.NewHighestBeforeSeq(st.validators.Len()) // nothing observes
+	if selfParent != nil {
+		st.merged = st.mergeWith(st.vecClock.GetHighestBeforeAllBranches(*selfParent))
+	}
+	st.rootsAfter = make([]vector.LowestAfterSeq, len(st.roots))
+	for i, r := range st.roots {
+		st.rootsAfter[i] = st.vecClock.GetLowestAfterSeq(r.ID)
+	}
+}
+
+// mergeWith returns the vector observed by both the chosen parents and the option, the chosen ones aren't changed
+func (st *RootsStrategy) mergeWith(vec vector.HighestBeforeSeq) vector.HighestBeforeSeq {
+	merged := vector.NewHighestBeforeSeq(st.validators.Len())
+	for creatorIdx := idx.Validator(0); creatorIdx < idx.Validator(st.validators.Len()); creatorIdx++ {
+		my := st.merged.Get(creatorIdx)
+		his := vec.Get(creatorIdx)
+		switch {
+		case my.IsForkDetected() || his.IsForkDetected():
+			if my.IsForkDetected() {
+				merged.Set(creatorIdx, my)
+			} else {
+				merged.Set(creatorIdx, his)
+			}
+		case his.Seq > my.Seq:
+			merged.Set(creatorIdx, his)
+		default:
+			merged.Set(creatorIdx, my)
+		}
+	}
+	return merged
+}
+
+// reachedStake returns the stake of the roots which are observed by a quorum in the vector
+func (st *RootsStrategy) reachedStake(vec vector.HighestBeforeSeq) pos.Stake {
+	var reached pos.Stake
+	for i, r := range st.roots {
+		after := st.rootsAfter[i]
+		if after == nil {
+			continue
+		}
+		var observers pos.Stake
+		for creatorIdx := idx.Validator(0); creatorIdx < idx.Validator(st.validators.Len()); creatorIdx++ {
+			lowest := after.Get(creatorIdx)
+			highest := vec.Get(creatorIdx)
+			if lowest != 0 && lowest <= highest.Seq && !highest.IsForkDetected() {
+				observers += st.validators.GetStakeByIdx(creatorIdx)
+			}
+		}
+		if observers >= st.validators.Quorum() {
+			reached += st.validators.Get(r.Slot.Validator)
+		}
+	}
+	return reached
+}
+
+// Find chooses the hash from the specified options
+func (st *RootsStrategy) Find(options hash.Events) hash.Event {
+	var (
+		best         hash.Event
+		bestMerged   vector.HighestBeforeSeq
+		bestReached  pos.Stake
+		bestObserves int
+	)
+	for i, id := range options {
+		merged := st.mergeWith(st.vecClock.GetHighestBeforeAllBranches(id))
+		reached := st.reachedStake(merged)
+		observes := 0
+		for creatorIdx := idx.Validator(0); creatorIdx < idx.Validator(st.validators.Len()); creatorIdx++ {
+			if merged.Get(creatorIdx) != st.merged.Get(creatorIdx) {
+				observes++
+			}
+		}
+		if i == 0 || reached > bestReached || (reached == bestReached && observes > bestObserves) {
+			best, bestMerged, bestReached, bestObserves = id, merged, reached, observes
+		}
+	}
+	// memorize the merged vector for next calls
+	st.merged = bestMerged
+	return best
+}
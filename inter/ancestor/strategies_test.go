@@ -0,0 +1,117 @@
+package ancestor
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/inter/pos"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/memorydb"
+	"github.com/Fantom-foundation/go-lachesis/logger"
+	"github.com/Fantom-foundation/go-lachesis/poset/election"
+	"github.com/Fantom-foundation/go-lachesis/vector"
+)
+
+func TestStakeWeightedStrategy(t *testing.T) {
+	assertar := assert.New(t)
+
+	validators := pos.ArrayToValidators([]idx.StakerID{1, 2}, []pos.Stake{1, 99})
+	a, b := hash.FakeEvent(), hash.FakeEvent()
+	creators := map[hash.Event]idx.StakerID{a: 1, b: 2}
+
+	strategy := NewStakeWeightedStrategy(rand.New(rand.NewSource(0)), validators, func(id hash.Event) idx.StakerID {
+		return creators[id]
+	})
+	strategy.Init(nil)
+	chosen := map[hash.Event]int{}
+	for i := 0; i < 1000; i++ {
+		chosen[strategy.Find(hash.Events{a, b})]++
+	}
+	assertar.True(chosen[b] > 900, chosen)
+	assertar.NotZero(chosen[a])
+}
+
+func TestLatencyStrategy(t *testing.T) {
+	assertar := assert.New(t)
+
+	latencies := NewLatencies()
+	latencies.Observe(1, 100*time.Millisecond)
+	latencies.Observe(2, 10*time.Millisecond)
+	latencies.Observe(2, 90*time.Millisecond)
+	avg, ok := latencies.Get(2)
+	assertar.True(ok)
+	assertar.Equal(20*time.Millisecond, avg)
+	_, ok = latencies.Get(3)
+	assertar.False(ok)
+
+	a, b, c := hash.FakeEvent(), hash.FakeEvent(), hash.FakeEvent()
+	creators := map[hash.Event]idx.StakerID{a: 1, b: 2, c: 3}
+	strategy := NewLatencyStrategy(latencies, func(id hash.Event) idx.StakerID {
+		return creators[id]
+	})
+
+	_, parents := FindBestParents(4, hash.Events{c, a, b}, nil, strategy)
+	assertar.Equal(hash.Events{b, a, c}, parents)
+}
+
+func TestRootsStrategy(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	const (
+		A = idx.StakerID(1)
+		B = idx.StakerID(2)
+		C = idx.StakerID(3)
+		D = idx.StakerID(4)
+	)
+	validators := pos.EqualStakeValidators([]idx.StakerID{A, B, C, D}, 1)
+	events := make(map[hash.Event]*inter.EventHeaderData)
+	vecClock := vector.NewIndex(vector.DefaultIndexConfig(), validators, memorydb.New(), func(id hash.Event) *inter.EventHeaderData {
+		return events[id]
+	})
+	add := func(creator idx.StakerID, seq idx.Event, parents ...hash.Event) hash.Event {
+		e := inter.NewEvent()
+		e.Epoch = 1
+		e.Creator = creator
+		e.Seq = seq
+		e.Parents = parents
+		for _, p := range parents {
+			e.Lamport = idx.MaxLamport(e.Lamport, events[p].Lamport)
+		}
+		e.Lamport++
+		events[e.Hash()] = &e.EventHeaderData
+		vecClock.Add(&e.EventHeaderData)
+		return e.Hash()
+	}
+
+	a1 := add(A, 1)
+	b1 := add(B, 1)
+	c1 := add(C, 1)
+	d1 := add(D, 1)
+	b2 := add(B, 2, b1, a1, c1)
+	c2 := add(C, 2, c1, b2)
+	d2 := add(D, 2, d1, a1, b1)
+	a2 := add(A, 2, a1, d2)
+
+	roots := []election.RootAndSlot{
+		{Slot: election.Slot{Frame: 1, Validator: A}, ID: a1},
+		{Slot: election.Slot{Frame: 1, Validator: B}, ID: b1},
+		{Slot: election.Slot{Frame: 1, Validator: C}, ID: c1},
+		{Slot: election.Slot{Frame: 1, Validator: D}, ID: d1},
+	}
+	strategy := NewRootsStrategy(vecClock, validators, roots)
+
+	// c2 makes the new event reach a1 and b1, a2 reaches only b1
+	_, parents := FindBestParents(3, hash.Events{a2, c2, d2}, &d2, strategy)
+	assertar.Equal(hash.Events{d2, c2, a2}, parents)
+
+	strategy.Init(&d2)
+	assertar.Equal(pos.Stake(0), strategy.reachedStake(strategy.merged))
+	assertar.Equal(pos.Stake(2), strategy.reachedStake(strategy.mergeWith(vecClock.GetHighestBeforeAllBranches(c2))))
+	assertar.Equal(pos.Stake(1), strategy.reachedStake(strategy.mergeWith(vecClock.GetHighestBeforeAllBranches(a2))))
+}
@@ -0,0 +1,169 @@
+package inter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+)
+
+// DagEvent is the event of an exported DAG, along with its consensus marks.
+type DagEvent struct {
+	ID          hash.Event
+	Creator     idx.StakerID
+	Seq         idx.Event
+	Lamport     idx.Lamport
+	Frame       idx.Frame
+	IsRoot      bool
+	Parents     hash.Events
+	ClaimedTime Timestamp
+
+	// AtroposOf is the block which the event is Atropos of, or 0.
+	AtroposOf idx.Block
+	// Block is the block which confirmed the event, or 0 if it isn't confirmed.
+	Block idx.Block
+	// Cheater is true if the creator has forks in the epoch.
+	// Branch numbers the creator's forks, the first one is 0.
+	Cheater bool
+	Branch  int
+	// ArrivalTime is the local time when the event was received, or 0 if it isn't known.
+	ArrivalTime Timestamp
+}
+
+// EpochDag is the exported DAG of an epoch, in topological order.
+type EpochDag struct {
+	Epoch  idx.Epoch
+	Events []*DagEvent
+}
+
+type dagEventJSON struct {
+	ID          string       `json:"id"`
+	Creator     idx.StakerID `json:"creator"`
+	Seq         idx.Event    `json:"seq"`
+	Lamport     idx.Lamport  `json:"lamport"`
+	Frame       idx.Frame    `json:"frame"`
+	IsRoot      bool         `json:"isRoot"`
+	Parents     []string     `json:"parents"`
+	ClaimedTime Timestamp    `json:"claimedTime"`
+	AtroposOf   idx.Block    `json:"atroposOf,omitempty"`
+	Block       idx.Block    `json:"block,omitempty"`
+	Cheater     bool         `json:"cheater,omitempty"`
+	Branch      *int         `json:"branch,omitempty"`
+	ArrivalTime Timestamp    `json:"arrivalTime,omitempty"`
+}
+
+type epochDagJSON struct {
+	Epoch  idx.Epoch       `json:"epoch"`
+	Events []*dagEventJSON `json:"events"`
+}
+
+// MarshalJSON encodes the DAG with full event IDs. Zero marks are omitted.
+func (d *EpochDag) MarshalJSON() ([]byte, error) {
+	res := &epochDagJSON{
+		Epoch:  d.Epoch,
+		Events: make([]*dagEventJSON, len(d.Events)),
+	}
+	for i, e := range d.Events {
+		parents := make([]string, len(e.Parents))
+		for j, p := range e.Parents {
+			parents[j] = p.FullID()
+		}
+		res.Events[i] = &dagEventJSON{
+			ID:          e.ID.FullID(),
+			Creator:     e.Creator,
+			Seq:         e.Seq,
+			Lamport:     e.Lamport,
+			Frame:       e.Frame,
+			IsRoot:      e.IsRoot,
+			Parents:     parents,
+			ClaimedTime: e.ClaimedTime,
+			AtroposOf:   e.AtroposOf,
+			Block:       e.Block,
+			Cheater:     e.Cheater,
+			ArrivalTime: e.ArrivalTime,
+		}
+		if e.Cheater {
+			branch := e.Branch
+			res.Events[i].Branch = &branch
+		}
+	}
+	return json.Marshal(res)
+}
+
+// WriteDagDOT writes the epochs DAGs as a Graphviz digraph, each epoch is a cluster.
+// Roots have double borders, Atroposes are filled, unconfirmed events are dashed
+// and the events of cheaters are red. Edges point from an event to its parents,
+// the self-parent edges are bold.
+func WriteDagDOT(w io.Writer, dags ...*EpochDag) error {
+	out := &strings.Builder{}
+	out.WriteString("digraph DAG {\n")
+	out.WriteString("\trankdir=BT;\n")
+	out.WriteString("\tnode [shape=box, fontname=monospace, fontsize=10];\n")
+	for _, dag := range dags {
+		fmt.Fprintf(out, "\tsubgraph cluster_epoch_%d {\n", dag.Epoch)
+		fmt.Fprintf(out, "\t\tlabel=\"epoch %d\";\n", dag.Epoch)
+		for _, e := range dag.Events {
+			fmt.Fprintf(out, "\t\t\"%s\" [label=\"%s\", group=%d%s];\n", e.ID.FullID(), dotLabel(e), e.Creator, dotStyle(e))
+		}
+		for _, e := range dag.Events {
+			for i, p := range e.Parents {
+				attrs := ""
+				if i == 0 && e.Seq > 1 {
+					// the first parent is the self-parent
+					attrs = " [style=bold]"
+				}
+				fmt.Fprintf(out, "\t\t\"%s\" -> \"%s\"%s;\n", e.ID.FullID(), p.FullID(), attrs)
+			}
+		}
+		out.WriteString("\t}\n")
+	}
+	out.WriteString("}\n")
+
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+func dotLabel(e *DagEvent) string {
+	lines := []string{
+		e.ID.String(),
+		fmt.Sprintf("creator=%d seq=%d", e.Creator, e.Seq),
+		fmt.Sprintf("frame=%s", FmtFrame(e.Frame, e.IsRoot)),
+	}
+	if e.AtroposOf != 0 {
+		lines = append(lines, fmt.Sprintf("Atropos of block %d", e.AtroposOf))
+	} else if e.Block != 0 {
+		lines = append(lines, fmt.Sprintf("block %d", e.Block))
+	}
+	if e.Cheater {
+		lines = append(lines, fmt.Sprintf("cheater branch %d", e.Branch))
+	}
+	if e.ArrivalTime != 0 {
+		lines = append(lines, "arrived "+e.ArrivalTime.Time().UTC().Format("15:04:05.000"))
+	}
+	return strings.Join(lines, "\\n")
+}
+
+func dotStyle(e *DagEvent) string {
+	styles := make([]string, 0, 2)
+	attrs := ""
+	if e.IsRoot {
+		attrs += ", peripheries=2"
+	}
+	if e.AtroposOf != 0 {
+		styles = append(styles, "filled")
+		attrs += ", fillcolor=gold"
+	}
+	if e.Block == 0 {
+		styles = append(styles, "dashed")
+	}
+	if e.Cheater {
+		attrs += ", color=red"
+	}
+	if len(styles) != 0 {
+		attrs += ", style=\"" + strings.Join(styles, ",") + "\""
+	}
+	return attrs
+}
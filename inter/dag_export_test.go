@@ -0,0 +1,69 @@
+package inter
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/hash"
+)
+
+func TestEpochDagExport(t *testing.T) {
+	assertar := assert.New(t)
+
+	root := &DagEvent{
+		ID:        hash.FakeEvent(),
+		Creator:   1,
+		Seq:       1,
+		Lamport:   1,
+		Frame:     1,
+		IsRoot:    true,
+		AtroposOf: 2,
+		Block:     2,
+	}
+	next := &DagEvent{
+		ID:      hash.FakeEvent(),
+		Creator: 1,
+		Seq:     2,
+		Lamport: 2,
+		Frame:   1,
+		Parents: hash.Events{root.ID},
+		Cheater: true,
+	}
+	dag := &EpochDag{
+		Epoch:  1,
+		Events: []*DagEvent{root, next},
+	}
+
+	raw, err := json.Marshal(dag)
+	if !assertar.NoError(err) {
+		return
+	}
+	var decoded struct {
+		Epoch  uint32
+		Events []map[string]interface{}
+	}
+	if !assertar.NoError(json.Unmarshal(raw, &decoded)) {
+		return
+	}
+	assertar.Equal(uint32(1), decoded.Epoch)
+	if assertar.Len(decoded.Events, 2) {
+		assertar.Equal(root.ID.FullID(), decoded.Events[0]["id"])
+		assertar.Equal(2.0, decoded.Events[0]["atroposOf"])
+		assertar.NotContains(decoded.Events[0], "branch")
+		assertar.Equal([]interface{}{root.ID.FullID()}, decoded.Events[1]["parents"])
+		assertar.NotContains(decoded.Events[1], "block")
+		assertar.Equal(0.0, decoded.Events[1]["branch"])
+	}
+
+	dot := &strings.Builder{}
+	if !assertar.NoError(WriteDagDOT(dot, dag)) {
+		return
+	}
+	assertar.Contains(dot.String(), "subgraph cluster_epoch_1 {")
+	assertar.Contains(dot.String(), "Atropos of block 2")
+	assertar.Contains(dot.String(), "\""+next.ID.FullID()+"\" -> \""+root.ID.FullID()+"\" [style=bold];")
+	assertar.Contains(dot.String(), "color=red, style=\"dashed\"")
+}
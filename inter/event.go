@@ -149,7 +149,7 @@ func (e *Event) Sign(signer func([]byte) ([]byte, error)) error {
 }
 
 // VerifySignature checks the signature against e.Creator.
-func (e *Event) VerifySignature(address common.Address) bool {
+func (e *EventHeader) VerifySignature(address common.Address) bool {
 	// NOTE: Keccak256 because of AccountManager
 	signedHash := crypto.Keccak256(e.DataToSign())
 	pk, err := crypto.SigToPub(signedHash, e.Sig)
@@ -0,0 +1,48 @@
+package inter
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+)
+
+var (
+	ErrForkEvidenceNotFork = errors.New("events of the fork evidence aren't a fork")
+	ErrForkEvidenceSig     = errors.New("event of the fork evidence has wrong signature")
+)
+
+// ForkEvidence is a proof of double-signing: two different events of the same creator
+// with the same sequence number, both signed by the creator.
+type ForkEvidence struct {
+	Epoch idx.Epoch
+	// Block is the local block which first observed the creator as a cheater.
+	// It isn't signed, so it's zero in the evidences received from peers until the block is decided locally.
+	Block idx.Block
+
+	A *EventHeader
+	B *EventHeader
+}
+
+// Creator returns the cheater.
+func (f *ForkEvidence) Creator() idx.StakerID {
+	return f.A.Creator
+}
+
+// Verify checks that the events are a fork of the epoch, signed by the creator's address.
+func (f *ForkEvidence) Verify(creator common.Address) error {
+	if f.A == nil || f.B == nil {
+		return ErrForkEvidenceNotFork
+	}
+	if f.A.Epoch != f.Epoch || f.B.Epoch != f.Epoch ||
+		f.A.Creator != f.B.Creator ||
+		f.A.Seq != f.B.Seq ||
+		f.A.CalcHash() == f.B.CalcHash() {
+		return ErrForkEvidenceNotFork
+	}
+	if !f.A.VerifySignature(creator) || !f.B.VerifySignature(creator) {
+		return ErrForkEvidenceSig
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package inter
+
+import (
+	"testing"
+
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/crypto"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+)
+
+func TestForkEvidenceVerify(t *testing.T) {
+	assertar := assert.New(t)
+
+	key := crypto.FakeKey(1)
+	addr := ethcrypto.PubkeyToAddress(key.PublicKey)
+
+	newEvent := func(lamport uint32) *Event {
+		e := NewEvent()
+		e.Epoch = 2
+		e.Seq = 3
+		e.Creator = 1
+		e.Lamport = idx.Lamport(lamport)
+		if !assertar.NoError(e.SignBy(key)) {
+			t.FailNow()
+		}
+		return e
+	}
+	a := newEvent(5)
+	b := newEvent(6)
+
+	f := &ForkEvidence{Epoch: 2, Block: 10, A: &a.EventHeader, B: &b.EventHeader}
+	assertar.NoError(f.Verify(addr))
+	assertar.Equal(ErrForkEvidenceSig, f.Verify(ethcrypto.PubkeyToAddress(crypto.FakeKey(2).PublicKey)))
+
+	f.B = &a.EventHeader
+	assertar.Equal(ErrForkEvidenceNotFork, f.Verify(addr), "same event")
+
+	f.B = &b.EventHeader
+	f.Epoch = 3
+	assertar.Equal(ErrForkEvidenceNotFork, f.Verify(addr), "other epoch")
+	f.Epoch = 2
+
+	c := newEvent(7)
+	c.Seq = 4
+	f.B = &c.EventHeader
+	assertar.Equal(ErrForkEvidenceNotFork, f.Verify(addr), "other seq")
+
+	f.B = nil
+	assertar.Equal(ErrForkEvidenceNotFork, f.Verify(addr))
+}
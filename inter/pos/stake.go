@@ -53,6 +53,21 @@ func (vv Validators) NewCounter() *StakeCounter {
 	return newStakeCounter(vv)
 }
 
+// NewCounterExcluding constructor of a counter which never counts the excluded validators.
+// The quorum is calculated only for the stake of the rest validators.
+func (vv Validators) NewCounterExcluding(excluded []idx.Validator) *StakeCounter {
+	s := newStakeCounter(vv)
+	excludedStake := Stake(0)
+	for _, i := range excluded {
+		if !s.already[i] {
+			s.already[i] = true
+			excludedStake += vv.GetStakeByIdx(i)
+		}
+	}
+	s.quorum = (vv.TotalStake()-excludedStake)*2/3 + 1
+	return s
+}
+
 func newStakeCounter(vv Validators) *StakeCounter {
 	return &StakeCounter{
 		validators: vv,
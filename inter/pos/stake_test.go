@@ -0,0 +1,35 @@
+package pos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+)
+
+func TestStakeCounterExcluding(t *testing.T) {
+	assertar := assert.New(t)
+
+	vv := ArrayToValidators(
+		[]idx.StakerID{1, 2, 3, 4, 5},
+		[]Stake{1, 1, 1, 1, 1},
+	)
+
+	counter := vv.NewCounter()
+	for _, id := range []idx.StakerID{2, 3, 4} {
+		assertar.True(counter.Count(id))
+	}
+	assertar.False(counter.HasQuorum())
+
+	excluded := []idx.Validator{vv.GetIdx(1), vv.GetIdx(1)}
+	counter = vv.NewCounterExcluding(excluded)
+	assertar.False(counter.Count(1), "excluded validator is counted")
+	assertar.Equal(Stake(0), counter.Sum())
+	for _, id := range []idx.StakerID{2, 3} {
+		assertar.True(counter.Count(id))
+	}
+	assertar.False(counter.HasQuorum())
+	assertar.True(counter.Count(4))
+	assertar.True(counter.HasQuorum())
+}
@@ -0,0 +1,551 @@
+// +build !js
+
+// Package badger implements the key-value database layer based on BadgerDB.
+package badger
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dgraph-io/badger"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+
+	"github.com/Fantom-foundation/go-lachesis/kvdb"
+)
+
+const (
+	// minCache is the minimum amount of memory in megabytes to allocate to
+	// the memtables.
+	minCache = 16
+
+	// valueLogFileSize is a size of the value log files. The files are mmapped,
+	// so it's kept small as there are many databases opened at once.
+	valueLogFileSize = 64 << 20
+
+	// gcDiscardRatio is a ratio of the garbage in the value log file to rewrite it.
+	gcDiscardRatio = 0.5
+
+	// gcInterval specifies the interval to run the value log garbage collection
+	// and to report the size of the database.
+	gcInterval = time.Minute
+)
+
+// Database is a persistent key-value store. Apart from basic data storage
+// functionality it also supports batch writes, snapshots and iterating over
+// the keyspace in binary-alphabetical order.
+type Database struct {
+	fn string     // filename for reporting
+	db *badger.DB // BadgerDB instance
+
+	diskSizeGauge metrics.Gauge // Gauge for tracking the size of all the levels and value logs in the database
+	metricNames   []string      // Names of the registered metrics, which are unregistered on close
+
+	quitLock sync.Mutex      // Mutex protecting the quit channel access
+	quitChan chan chan error // Quit channel to stop the garbage collection before closing the database
+
+	log log.Logger // Contextual logger tracking the database path
+
+	onClose func() error
+	onDrop  func()
+}
+
+// New returns a wrapped BadgerDB object. The namespace is the prefix that the
+// metrics reporting should use for surfacing internal stats.
+func New(path string, cache int, namespace string, close func() error, drop func()) (*Database, error) {
+	// Ensure we have some minimal caching
+	if cache < minCache {
+		cache = minCache
+	}
+	logger := log.New("database", path)
+	logger.Info("Allocated memtables", "cache", cache)
+
+	// Writes are flushed by SyncedPool markers, so fsync on each write isn't needed (as for LevelDB)
+	opts := badger.DefaultOptions(path).
+		WithSyncWrites(false).
+		WithTruncate(true).
+		WithLogger(&badgerLogger{logger}).
+		WithMaxTableSize(int64(cache/4) << 20).
+		WithValueLogFileSize(valueLogFileSize)
+	opts.NumMemtables = 2
+	opts.EventLogging = false
+
+	db, err := badger.Open(opts)
+	if err != nil {
+		return nil, err
+	}
+	bdb := &Database{
+		fn:       path,
+		db:       db,
+		log:      logger,
+		quitChan: make(chan chan error),
+		onClose:  close,
+		onDrop:   drop,
+	}
+	bdb.diskSizeGauge = metrics.NewRegisteredGauge(namespace+"disk/size", nil)
+	bdb.metricNames = append(bdb.metricNames, namespace+"disk/size")
+
+	go bdb.gc(gcInterval)
+	return bdb, nil
+}
+
+// Close stops the garbage collection, flushes any pending data to disk and closes
+// all io accesses to the underlying key-value store.
+func (db *Database) Close() error {
+	db.quitLock.Lock()
+	defer db.quitLock.Unlock()
+
+	if db.db == nil {
+		panic("already closed")
+	}
+
+	if db.quitChan != nil {
+		errc := make(chan error)
+		db.quitChan <- errc
+		if err := <-errc; err != nil {
+			db.log.Error("Garbage collection failed", "err", err)
+		}
+		db.quitChan = nil
+	}
+
+	bdb := db.db
+	db.db = nil
+
+	// the DB may be reopened with the same metrics namespace
+	for _, name := range db.metricNames {
+		metrics.DefaultRegistry.Unregister(name)
+	}
+
+	if db.onClose != nil {
+		if err := db.onClose(); err != nil {
+			return err
+		}
+		db.onClose = nil
+	}
+	return bdb.Close()
+}
+
+// Drop whole database.
+func (db *Database) Drop() {
+	if db.db != nil {
+		panic("Close database first!")
+	}
+	if db.onDrop != nil {
+		db.onDrop()
+	}
+}
+
+// Has retrieves if a key is present in the key-value store.
+func (db *Database) Has(key []byte) (bool, error) {
+	var has bool
+	err := db.db.View(func(txn *badger.Txn) (err error) {
+		has, err = hasKey(txn, key)
+		return
+	})
+	return has, err
+}
+
+// Get retrieves the given key if it's present in the key-value store.
+func (db *Database) Get(key []byte) ([]byte, error) {
+	var dat []byte
+	err := db.db.View(func(txn *badger.Txn) (err error) {
+		dat, err = getValue(txn, key)
+		return
+	})
+	return dat, err
+}
+
+// Put inserts the given value into the key-value store.
+func (db *Database) Put(key []byte, value []byte) error {
+	return db.db.Update(func(txn *badger.Txn) error {
+		return txn.Set(key, value)
+	})
+}
+
+// Delete removes the key from the key-value store.
+func (db *Database) Delete(key []byte) error {
+	return db.db.Update(func(txn *badger.Txn) error {
+		return txn.Delete(key)
+	})
+}
+
+// DeleteRange deletes all the keys in the range [start, limit).
+// A nil limit is treated as a key after all keys in the data store.
+// If the range consists of all the keys with a prefix, the keys are dropped natively
+// from the LSM tree and the value log. The writes fail with badger.ErrBlockedWrites meanwhile,
+// which is safe for the flushable DBs as they write on flush only.
+// Otherwise, the keys are read from the LSM tree only and deleted by a write batch.
+func (db *Database) DeleteRange(start []byte, limit []byte) error {
+	if bytes.Equal(kvdb.UpperBound(start), limit) {
+		if len(start) == 0 {
+			return db.db.DropAll()
+		}
+		return db.db.DropPrefix(start)
+	}
+
+	txn := db.db.NewTransaction(false)
+	defer txn.Discard()
+
+	it := txn.NewIterator(badger.IteratorOptions{})
+	defer it.Close()
+
+	wb := db.db.NewWriteBatch()
+	defer wb.Cancel()
+
+	for it.Seek(start); it.Valid(); it.Next() {
+		key := it.Item().KeyCopy(nil)
+		if limit != nil && bytes.Compare(key, limit) >= 0 {
+			break
+		}
+		if err := wb.Delete(key); err != nil {
+			return err
+		}
+	}
+	return wb.Flush()
+}
+
+// NewBatch creates a write-only key-value store that buffers changes to its host
+// database until a final write is called.
+func (db *Database) NewBatch() ethdb.Batch {
+	return &batch{
+		db: db.db,
+	}
+}
+
+// GetSnapshot returns a consistent read-only view of the database.
+func (db *Database) GetSnapshot() (kvdb.Snapshot, error) {
+	return &snapshot{
+		txn: db.db.NewTransaction(false),
+	}, nil
+}
+
+// NewIterator creates a binary-alphabetical iterator over the entire keyspace
+// contained within the badger database.
+func (db *Database) NewIterator() ethdb.Iterator {
+	return newIterator(db.db.NewTransaction(false), true, nil, nil)
+}
+
+// NewIteratorWithStart creates a binary-alphabetical iterator over a subset of
+// database content starting at a particular initial key (or after, if it does
+// not exist).
+func (db *Database) NewIteratorWithStart(start []byte) ethdb.Iterator {
+	return newIterator(db.db.NewTransaction(false), true, start, nil)
+}
+
+// NewIteratorWithPrefix creates a binary-alphabetical iterator over a subset
+// of database content with a particular key prefix.
+func (db *Database) NewIteratorWithPrefix(prefix []byte) ethdb.Iterator {
+	return newIterator(db.db.NewTransaction(false), true, prefix, prefix)
+}
+
+// Stat returns a particular internal stat of the database.
+func (db *Database) Stat(property string) (string, error) {
+	switch property {
+	case "badger.size":
+		lsm, vlog := db.db.Size()
+		return fmt.Sprintf("LSM(B):%d ValueLog(B):%d", lsm, vlog), nil
+	case "badger.tables":
+		buf := new(bytes.Buffer)
+		for _, t := range db.db.Tables(false) {
+			fmt.Fprintf(buf, "Level:%d ID:%d Left:%x Right:%x\n", t.Level, t.ID, t.Left, t.Right)
+		}
+		return buf.String(), nil
+	default:
+		return "", errors.New("unknown property")
+	}
+}
+
+// Compact flattens the underlying data store and rewrites the value log files.
+// BadgerDB doesn't support the compaction of a key range, so the whole
+// data store is compacted regardless of start and limit.
+func (db *Database) Compact(start []byte, limit []byte) error {
+	if err := db.db.Flatten(1); err != nil {
+		return err
+	}
+	db.runValueLogGC()
+	return nil
+}
+
+// Path returns the path to the database directory.
+func (db *Database) Path() string {
+	return db.fn
+}
+
+// gc periodically rewrites the value log files which are mostly garbage,
+// and reports the database size to the metrics subsystem.
+func (db *Database) gc(refresh time.Duration) {
+	var errc chan error
+	for errc == nil {
+		select {
+		case errc = <-db.quitChan:
+			// Quit requesting, stop hammering the database
+		case <-time.After(refresh):
+			// Timeout, rewrite the value logs
+			db.runValueLogGC()
+			if db.diskSizeGauge != nil {
+				lsm, vlog := db.db.Size()
+				db.diskSizeGauge.Update(lsm + vlog)
+			}
+		}
+	}
+	errc <- nil
+}
+
+// runValueLogGC rewrites the value log files until there is nothing to rewrite.
+func (db *Database) runValueLogGC() {
+	for {
+		if err := db.db.RunValueLogGC(gcDiscardRatio); err != nil {
+			return
+		}
+	}
+}
+
+// hasKey checks if the key exists in the transaction view.
+func hasKey(txn *badger.Txn, key []byte) (bool, error) {
+	_, err := txn.Get(key)
+	if err == badger.ErrKeyNotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// getValue retrieves a copy of the value from the transaction view, nil if the key isn't found.
+func getValue(txn *badger.Txn, key []byte) ([]byte, error) {
+	item, err := txn.Get(key)
+	if err == badger.ErrKeyNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return item.ValueCopy(nil)
+}
+
+// snapshot is a consistent read-only view of the database,
+// it's a read-only transaction under the hood.
+// The snapshot iterators must be released before the snapshot.
+type snapshot struct {
+	txn *badger.Txn
+}
+
+// Has retrieves if a key is present in the snapshot.
+func (s *snapshot) Has(key []byte) (bool, error) {
+	return hasKey(s.txn, key)
+}
+
+// Get retrieves the given key if it's present in the snapshot.
+func (s *snapshot) Get(key []byte) ([]byte, error) {
+	return getValue(s.txn, key)
+}
+
+// NewIterator creates a binary-alphabetical iterator over the entire keyspace
+// of the snapshot.
+func (s *snapshot) NewIterator() ethdb.Iterator {
+	return newIterator(s.txn, false, nil, nil)
+}
+
+// NewIteratorWithStart creates a binary-alphabetical iterator over a subset of
+// the snapshot content starting at a particular initial key (or after, if it does
+// not exist).
+func (s *snapshot) NewIteratorWithStart(start []byte) ethdb.Iterator {
+	return newIterator(s.txn, false, start, nil)
+}
+
+// NewIteratorWithPrefix creates a binary-alphabetical iterator over a subset
+// of the snapshot content with a particular key prefix.
+func (s *snapshot) NewIteratorWithPrefix(prefix []byte) ethdb.Iterator {
+	return newIterator(s.txn, false, prefix, prefix)
+}
+
+// Release releases the snapshot. It can be called multiple times.
+func (s *snapshot) Release() {
+	s.txn.Discard()
+}
+
+// keyvalue is a key-value tuple tagged with a deletion field to allow creating
+// badger write batches.
+type keyvalue struct {
+	key    []byte
+	value  []byte
+	delete bool
+}
+
+// batch is a write-only badger batch that commits changes to its host database
+// when Write is called. A batch cannot be used concurrently.
+type batch struct {
+	db     *badger.DB
+	writes []keyvalue
+	size   int
+}
+
+// Put inserts the given value into the batch for later committing.
+func (b *batch) Put(key, value []byte) error {
+	b.writes = append(b.writes, keyvalue{common.CopyBytes(key), common.CopyBytes(value), false})
+	b.size += len(value)
+	return nil
+}
+
+// Delete inserts the a key removal into the batch for later committing.
+func (b *batch) Delete(key []byte) error {
+	b.writes = append(b.writes, keyvalue{common.CopyBytes(key), nil, true})
+	b.size++
+	return nil
+}
+
+// ValueSize retrieves the amount of data queued up for writing.
+func (b *batch) ValueSize() int {
+	return b.size
+}
+
+// Write flushes any accumulated data to disk. Large batches are split into
+// several transactions by BadgerDB.
+func (b *batch) Write() error {
+	wb := b.db.NewWriteBatch()
+	defer wb.Cancel()
+
+	for _, keyvalue := range b.writes {
+		var err error
+		if keyvalue.delete {
+			err = wb.Delete(keyvalue.key)
+		} else {
+			err = wb.Set(keyvalue.key, keyvalue.value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return wb.Flush()
+}
+
+// Reset resets the batch for reuse.
+func (b *batch) Reset() {
+	b.writes = b.writes[:0]
+	b.size = 0
+}
+
+// Replay replays the batch contents.
+func (b *batch) Replay(w ethdb.KeyValueWriter) error {
+	for _, keyvalue := range b.writes {
+		if keyvalue.delete {
+			if err := w.Delete(keyvalue.key); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := w.Put(keyvalue.key, keyvalue.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// iterator can walk over the (potentially partial) keyspace of a badger
+// transaction view.
+type iterator struct {
+	txn     *badger.Txn
+	ownsTxn bool
+	it      *badger.Iterator
+
+	start  []byte
+	prefix []byte
+	inited bool
+
+	key, value []byte
+	err        error
+}
+
+func newIterator(txn *badger.Txn, ownsTxn bool, start, prefix []byte) *iterator {
+	return &iterator{
+		txn:     txn,
+		ownsTxn: ownsTxn,
+		it: txn.NewIterator(badger.IteratorOptions{
+			PrefetchValues: true,
+			PrefetchSize:   100,
+			Prefix:         common.CopyBytes(prefix),
+		}),
+		start:  common.CopyBytes(start),
+		prefix: common.CopyBytes(prefix),
+	}
+}
+
+// Next moves the iterator to the next key/value pair. It returns whether the
+// iterator is exhausted.
+func (it *iterator) Next() bool {
+	if it.it == nil || it.err != nil {
+		return false
+	}
+	if !it.inited {
+		it.inited = true
+		it.it.Seek(it.start)
+	} else {
+		it.it.Next()
+	}
+	if !it.it.ValidForPrefix(it.prefix) {
+		it.key, it.value = nil, nil
+		return false
+	}
+	item := it.it.Item()
+	it.key = item.KeyCopy(it.key[:0])
+	it.value, it.err = item.ValueCopy(it.value[:0])
+	return it.err == nil
+}
+
+// Error returns any accumulated error. Exhausting all the key/value pairs
+// is not considered to be an error.
+func (it *iterator) Error() error {
+	return it.err
+}
+
+// Key returns the key of the current key/value pair, or nil if done. The caller
+// should not modify the contents of the returned slice, and its contents may
+// change on the next call to Next.
+func (it *iterator) Key() []byte {
+	return it.key
+}
+
+// Value returns the value of the current key/value pair, or nil if done. The
+// caller should not modify the contents of the returned slice, and its contents
+// may change on the next call to Next.
+func (it *iterator) Value() []byte {
+	return it.value
+}
+
+// Release releases associated resources. Release should always succeed and can
+// be called multiple times without causing error.
+func (it *iterator) Release() {
+	if it.it == nil {
+		return
+	}
+	it.it.Close()
+	it.it = nil
+	if it.ownsTxn {
+		it.txn.Discard()
+	}
+	it.key, it.value = nil, nil
+}
+
+// badgerLogger redirects BadgerDB logs into the database logger.
+type badgerLogger struct {
+	log log.Logger
+}
+
+func (l *badgerLogger) Errorf(format string, args ...interface{}) {
+	l.log.Error(fmt.Sprintf(format, args...))
+}
+
+func (l *badgerLogger) Warningf(format string, args ...interface{}) {
+	l.log.Warn(fmt.Sprintf(format, args...))
+}
+
+func (l *badgerLogger) Infof(format string, args ...interface{}) {
+	l.log.Debug(fmt.Sprintf(format, args...))
+}
+
+func (l *badgerLogger) Debugf(format string, args ...interface{}) {
+	l.log.Trace(fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,73 @@
+package badger
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Fantom-foundation/go-lachesis/kvdb"
+)
+
+type producer struct {
+	datadir string
+}
+
+// NewProducer of badger db.
+func NewProducer(datadir string) kvdb.DbProducer {
+	return &producer{
+		datadir: datadir,
+	}
+}
+
+// Names of existing databases.
+func (p *producer) Names() []string {
+	var names []string
+
+	files, err := ioutil.ReadDir(p.datadir)
+	if err != nil {
+		panic(err)
+	}
+
+	for _, f := range files {
+		if !f.IsDir() {
+			continue
+		}
+		dirname := f.Name()
+		if strings.HasSuffix(dirname, "-bdb") {
+			name := strings.TrimSuffix(dirname, "-bdb")
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// metricsNamespace is the metrics prefix of the DB, which is unique for the DB name.
+func metricsNamespace(name string) string {
+	return "lachesis/db/" + name + "/"
+}
+
+// OpenDb or create db with name.
+func (p *producer) OpenDb(name string) kvdb.KeyValueStore {
+	dir := name + "-bdb"
+	path := filepath.Join(p.datadir, dir)
+
+	err := os.MkdirAll(path, 0700)
+	if err != nil {
+		panic(err)
+	}
+
+	onDrop := func() {
+		err := os.RemoveAll(path)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	db, err := New(path, 64, metricsNamespace(name), nil, onDrop)
+	if err != nil {
+		panic(err)
+	}
+
+	return db
+}
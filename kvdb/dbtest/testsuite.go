@@ -0,0 +1,395 @@
+// Package dbtest implements the conformance test suite of the kvdb.KeyValueStore backends.
+package dbtest
+
+import (
+	"bytes"
+	"sort"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/kvdb"
+)
+
+// TestDatabaseSuite runs a suite of tests against a KeyValueStore database
+// implementation. Optional kvdb.Snapshoter and kvdb.RangeDeleter are tested
+// if the implementation supports them.
+func TestDatabaseSuite(t *testing.T, New func() kvdb.KeyValueStore) {
+	t.Run("KeyValueOperations", func(t *testing.T) {
+		db := New()
+		defer db.Close()
+		testKeyValueOperations(t, db)
+	})
+	t.Run("Batch", func(t *testing.T) {
+		db := New()
+		defer db.Close()
+		testBatch(t, db)
+	})
+	t.Run("Iterator", func(t *testing.T) {
+		testIterator(t, New)
+	})
+	t.Run("Snapshot", func(t *testing.T) {
+		db := New()
+		defer db.Close()
+		if _, ok := db.(kvdb.Snapshoter); !ok {
+			t.Skip("snapshots aren't supported")
+		}
+		testSnapshot(t, db)
+	})
+	t.Run("DeleteRange", func(t *testing.T) {
+		db := New()
+		defer db.Close()
+		if _, ok := db.(kvdb.RangeDeleter); !ok {
+			t.Skip("range deletions aren't supported")
+		}
+		testDeleteRange(t, db)
+	})
+}
+
+func testKeyValueOperations(t *testing.T, db kvdb.KeyValueStore) {
+	assertar := assert.New(t)
+
+	key := []byte("key")
+
+	has, err := db.Has(key)
+	assertar.NoError(err)
+	assertar.False(has)
+	got, err := db.Get(key)
+	assertar.NoError(err)
+	assertar.Nil(got, "not existing key")
+
+	value := []byte("value")
+	assertar.NoError(db.Put(key, value))
+	// the written slices may be reused by the caller
+	value[0] = 'V'
+
+	has, err = db.Has(key)
+	assertar.NoError(err)
+	assertar.True(has)
+	got, err = db.Get(key)
+	assertar.NoError(err)
+	assertar.Equal([]byte("value"), got)
+	// the read slice may be modified by the caller
+	got[0] = 'V'
+	got, err = db.Get(key)
+	assertar.NoError(err)
+	assertar.Equal([]byte("value"), got)
+
+	assertar.NoError(db.Put(key, []byte("overwritten")))
+	got, err = db.Get(key)
+	assertar.NoError(err)
+	assertar.Equal([]byte("overwritten"), got)
+
+	assertar.NoError(db.Delete(key))
+	has, err = db.Has(key)
+	assertar.NoError(err)
+	assertar.False(has)
+	got, err = db.Get(key)
+	assertar.NoError(err)
+	assertar.Nil(got, "deleted key")
+
+	// deletion of not existing key isn't an error
+	assertar.NoError(db.Delete([]byte("not existing")))
+}
+
+func testBatch(t *testing.T, db kvdb.KeyValueStore) {
+	assertar := assert.New(t)
+
+	assertar.NoError(db.Put([]byte("1"), []byte("deleted")))
+
+	b := db.NewBatch()
+	key, value := []byte("2"), []byte("two")
+	assertar.NoError(b.Put(key, value))
+	// the written slices may be reused by the caller
+	key[0], value[0] = '3', 'T'
+	assertar.NoError(b.Put([]byte("3"), []byte("three")))
+	assertar.NoError(b.Delete([]byte("1")))
+	assertar.True(b.ValueSize() > 0)
+
+	// nothing is written until Write
+	got, err := db.Get([]byte("2"))
+	assertar.NoError(err)
+	assertar.Nil(got)
+
+	assertar.NoError(b.Write())
+	assertContent(t, db, map[string]string{
+		"2": "two",
+		"3": "three",
+	})
+
+	// replay into another DB
+	replayed := newMapWriter()
+	assertar.NoError(b.Replay(replayed))
+	assertar.Equal(map[string]string{"2": "two", "3": "three"}, replayed.data)
+	assertar.Equal([]string{"1"}, replayed.deleted)
+
+	b.Reset()
+	assertar.Equal(0, b.ValueSize())
+	assertar.NoError(b.Put([]byte("4"), []byte("four")))
+	assertar.NoError(b.Write())
+	assertContent(t, db, map[string]string{
+		"2": "two",
+		"3": "three",
+		"4": "four",
+	})
+}
+
+func testIterator(t *testing.T, New func() kvdb.KeyValueStore) {
+	tests := []struct {
+		content map[string]string
+		start   string
+		prefix  string
+		order   []string
+	}{
+		// Empty databases should be iterable
+		{map[string]string{}, "", "", nil},
+		{map[string]string{}, "", "non-existent-prefix", nil},
+		{map[string]string{}, "non-existent-start", "", nil},
+
+		// Single-item databases should be iterable
+		{map[string]string{"key": "val"}, "", "", []string{"key"}},
+		{map[string]string{"key": "val"}, "", "k", []string{"key"}},
+		{map[string]string{"key": "val"}, "", "l", nil},
+		{map[string]string{"key": "val"}, "k", "", []string{"key"}},
+		{map[string]string{"key": "val"}, "l", "", nil},
+
+		// Multi-item databases should be fully iterable
+		{
+			map[string]string{"k1": "v1", "k5": "v5", "k2": "v2", "k4": "v4", "k3": "v3"},
+			"", "",
+			[]string{"k1", "k2", "k3", "k4", "k5"},
+		},
+		{
+			map[string]string{"k1": "v1", "k5": "v5", "k2": "v2", "k4": "v4", "k3": "v3"},
+			"", "k",
+			[]string{"k1", "k2", "k3", "k4", "k5"},
+		},
+		{
+			map[string]string{"k1": "v1", "k5": "v5", "k2": "v2", "k4": "v4", "k3": "v3"},
+			"", "l",
+			nil,
+		},
+		// Multi-item databases should be iterable from the start
+		{
+			map[string]string{"k1": "v1", "k5": "v5", "k2": "v2", "k4": "v4", "k3": "v3"},
+			"k3", "",
+			[]string{"k3", "k4", "k5"},
+		},
+		{
+			map[string]string{"k1": "v1", "k5": "v5", "k2": "v2", "k4": "v4", "k3": "v3"},
+			"k21", "",
+			[]string{"k3", "k4", "k5"},
+		},
+		// Multi-item databases should be prefix-iterable
+		{
+			map[string]string{
+				"ka1": "va1", "ka5": "va5", "ka2": "va2", "ka4": "va4", "ka3": "va3",
+				"kb1": "vb1", "kb5": "vb5", "kb2": "vb2", "kb4": "vb4", "kb3": "vb3",
+			},
+			"", "ka",
+			[]string{"ka1", "ka2", "ka3", "ka4", "ka5"},
+		},
+		{
+			map[string]string{
+				"ka1": "va1", "ka5": "va5", "ka2": "va2", "ka4": "va4", "ka3": "va3",
+				"kb1": "vb1", "kb5": "vb5", "kb2": "vb2", "kb4": "vb4", "kb3": "vb3",
+			},
+			"", "kc",
+			nil,
+		},
+	}
+	for i, tt := range tests {
+		db := New()
+		for key, val := range tt.content {
+			if err := db.Put([]byte(key), []byte(val)); err != nil {
+				t.Fatalf("test %d: failed to insert item %s:%s into database: %v", i, key, val, err)
+			}
+		}
+
+		it := db.NewIteratorWithStart([]byte(tt.start))
+		if tt.prefix != "" {
+			it.Release()
+			it = db.NewIteratorWithPrefix([]byte(tt.prefix))
+		}
+		idx := 0
+		for it.Next() {
+			if idx >= len(tt.order) {
+				t.Errorf("test %d: iteration doesn't stop: have %s", i, string(it.Key()))
+				break
+			}
+			if !bytes.Equal(it.Key(), []byte(tt.order[idx])) {
+				t.Errorf("test %d: item %d: key mismatch: have %s, want %s", i, idx, string(it.Key()), tt.order[idx])
+			}
+			if !bytes.Equal(it.Value(), []byte(tt.content[tt.order[idx]])) {
+				t.Errorf("test %d: item %d: value mismatch: have %s, want %s", i, idx, string(it.Value()), tt.content[tt.order[idx]])
+			}
+			idx++
+		}
+		if err := it.Error(); err != nil {
+			t.Errorf("test %d: iteration failed: %v", i, err)
+		}
+		if idx != len(tt.order) {
+			t.Errorf("test %d: iteration terminated prematurely: have %d, want %d", i, idx, len(tt.order))
+		}
+		it.Release()
+		// Release is idempotent
+		it.Release()
+
+		if err := db.Close(); err != nil {
+			t.Fatalf("test %d: failed to close database: %v", i, err)
+		}
+	}
+}
+
+func testSnapshot(t *testing.T, db kvdb.KeyValueStore) {
+	assertar := assert.New(t)
+
+	assertar.NoError(db.Put([]byte("1"), []byte("one")))
+	assertar.NoError(db.Put([]byte("2"), []byte("two")))
+
+	snap, err := db.(kvdb.Snapshoter).GetSnapshot()
+	if !assertar.NoError(err) {
+		return
+	}
+
+	// the changes after the snapshot creation aren't visible in the snapshot
+	assertar.NoError(db.Put([]byte("1"), []byte("ONE")))
+	assertar.NoError(db.Delete([]byte("2")))
+	assertar.NoError(db.Put([]byte("3"), []byte("three")))
+
+	assertContent(t, snap, map[string]string{
+		"1": "one",
+		"2": "two",
+	})
+	has, err := snap.Has([]byte("3"))
+	assertar.NoError(err)
+	assertar.False(has)
+	got, err := snap.Get([]byte("3"))
+	assertar.NoError(err)
+	assertar.Nil(got)
+
+	it := snap.NewIteratorWithPrefix([]byte("2"))
+	assertar.True(it.Next())
+	assertar.Equal([]byte("2"), it.Key())
+	assertar.False(it.Next())
+	it.Release()
+
+	it = snap.NewIteratorWithStart([]byte("2"))
+	assertar.True(it.Next())
+	assertar.Equal([]byte("2"), it.Key())
+	assertar.False(it.Next())
+	it.Release()
+
+	assertContent(t, db, map[string]string{
+		"1": "ONE",
+		"3": "three",
+	})
+
+	snap.Release()
+	// Release is idempotent
+	snap.Release()
+}
+
+func testDeleteRange(t *testing.T, db kvdb.KeyValueStore) {
+	assertar := assert.New(t)
+
+	for _, key := range []string{"a", "b1", "b2", "b3", "c", "d"} {
+		assertar.NoError(db.Put([]byte(key), []byte(key)))
+	}
+
+	assertar.NoError(db.(kvdb.RangeDeleter).DeleteRange([]byte("b"), []byte("b3")))
+	assertContent(t, db, map[string]string{
+		"a":  "a",
+		"b3": "b3",
+		"c":  "c",
+		"d":  "d",
+	})
+
+	// empty range
+	assertar.NoError(db.(kvdb.RangeDeleter).DeleteRange([]byte("b4"), []byte("c")))
+	assertar.NoError(db.(kvdb.RangeDeleter).DeleteRange([]byte("c"), []byte("c")))
+
+	// up to the end
+	assertar.NoError(db.(kvdb.RangeDeleter).DeleteRange([]byte("c"), nil))
+	assertContent(t, db, map[string]string{
+		"a":  "a",
+		"b3": "b3",
+	})
+
+	// all the keys with a prefix
+	for _, key := range []string{"e1", "e2", "f"} {
+		assertar.NoError(db.Put([]byte(key), []byte(key)))
+	}
+	assertar.NoError(db.(kvdb.RangeDeleter).DeleteRange([]byte("e"), []byte("f")))
+	assertContent(t, db, map[string]string{
+		"a":  "a",
+		"b3": "b3",
+		"f":  "f",
+	})
+	// the DB is writable after the deletion
+	assertar.NoError(db.Put([]byte("e3"), []byte("e3")))
+
+	// all the keys
+	assertar.NoError(db.(kvdb.RangeDeleter).DeleteRange(nil, nil))
+	assertContent(t, db, map[string]string{})
+	assertar.NoError(db.Put([]byte("g"), []byte("g")))
+	assertContent(t, db, map[string]string{
+		"g": "g",
+	})
+}
+
+// reader is either a DB or a snapshot.
+type reader interface {
+	ethdb.KeyValueReader
+	ethdb.Iteratee
+}
+
+// assertContent checks that the DB contains exactly the expected key-value pairs.
+func assertContent(t *testing.T, db reader, exp map[string]string) {
+	assertar := assert.New(t)
+
+	for key, val := range exp {
+		got, err := db.Get([]byte(key))
+		assertar.NoError(err)
+		assertar.Equal([]byte(val), got, key)
+	}
+
+	keys := make([]string, 0, len(exp))
+	for key := range exp {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	got := make([]string, 0, len(exp))
+	it := db.NewIterator()
+	defer it.Release()
+	for it.Next() {
+		got = append(got, string(it.Key()))
+		assertar.Equal(exp[string(it.Key())], string(it.Value()), string(it.Key()))
+	}
+	assertar.NoError(it.Error())
+	assertar.Equal(keys, got)
+}
+
+// mapWriter collects the replayed batch.
+type mapWriter struct {
+	data    map[string]string
+	deleted []string
+}
+
+func newMapWriter() *mapWriter {
+	return &mapWriter{
+		data: make(map[string]string),
+	}
+}
+
+func (w *mapWriter) Put(key []byte, value []byte) error {
+	w.data[string(key)] = string(value)
+	return nil
+}
+
+func (w *mapWriter) Delete(key []byte) error {
+	w.deleted = append(w.deleted, string(key))
+	return nil
+}
@@ -0,0 +1,53 @@
+package kvdb
+
+import (
+	"bytes"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+// UpperBound returns the least key which is greater than all the keys with the prefix,
+// or nil if there is no such key.
+func UpperBound(prefix []byte) []byte {
+	limit := append([]byte{}, prefix...)
+	for i := len(limit) - 1; i >= 0; i-- {
+		if limit[i] < 0xff {
+			limit[i]++
+			return limit[:i+1]
+		}
+	}
+	return nil
+}
+
+// DeleteRange deletes all the keys in the range [start, limit) of the db.
+// A nil limit is treated as a key after all keys in the data store.
+// If the db isn't a RangeDeleter, the keys are deleted one by one.
+func DeleteRange(db ethdb.KeyValueStore, start []byte, limit []byte) error {
+	if deleter, ok := db.(RangeDeleter); ok {
+		return deleter.DeleteRange(start, limit)
+	}
+
+	it := db.NewIteratorWithStart(start)
+	defer it.Release()
+
+	batch := db.NewBatch()
+	for it.Next() {
+		if limit != nil && bytes.Compare(it.Key(), limit) >= 0 {
+			break
+		}
+		if err := batch.Delete(common.CopyBytes(it.Key())); err != nil {
+			return err
+		}
+		if batch.ValueSize() > ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	return batch.Write()
+}
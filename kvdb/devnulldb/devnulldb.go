@@ -2,6 +2,8 @@ package devnulldb
 
 import (
 	"github.com/ethereum/go-ethereum/ethdb"
+
+	"github.com/Fantom-foundation/go-lachesis/kvdb"
 )
 
 // Database is an always empty database.
@@ -42,6 +44,16 @@ func (db *Database) Delete(key []byte) error {
 	return nil
 }
 
+// DeleteRange deletes all the keys in the range [start, limit).
+func (db *Database) DeleteRange(start []byte, limit []byte) error {
+	return nil
+}
+
+// GetSnapshot returns an always empty snapshot.
+func (db *Database) GetSnapshot() (kvdb.Snapshot, error) {
+	return &snapshot{db}, nil
+}
+
 // NewBatch creates a write-only key-value store that buffers changes to its host
 // database until a final write is called.
 func (db *Database) NewBatch() ethdb.Batch {
@@ -85,6 +97,14 @@ func (db *Database) Len() int {
 	return 0
 }
 
+type snapshot struct {
+	*Database
+}
+
+// Release releases the snapshot.
+func (s *snapshot) Release() {
+}
+
 // batch is a write-only memory batch that commits changes to its host
 // database when Write is called. A batch cannot be used concurrently.
 type batch struct{}
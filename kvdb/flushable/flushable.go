@@ -13,7 +13,8 @@ import (
 )
 
 var (
-	errClosed = errors.New("database closed")
+	errClosed      = errors.New("database closed")
+	errNoSnapshots = errors.New("underlying DB doesn't support snapshots")
 )
 
 // Flushable is a ethdb.Database wrapper around any Database.
@@ -23,7 +24,8 @@ type Flushable struct {
 	onDrop     func()
 	underlying kvdb.KeyValueStore
 
-	modified       *rbt.Tree // modified, comparing to parent, pairs. deleted values are nil
+	modified       *rbt.Tree   // modified, comparing to parent, pairs. deleted values are nil
+	deleted        *[]keyRange // deleted, comparing to parent, key ranges. the pairs modified after the deletion are in modified
 	sizeEstimation *int
 
 	lock *sync.Mutex // we have no guarantees that rbt.Tree works with concurrent reads, so we can't use MutexRW
@@ -49,6 +51,7 @@ func WrapWithDrop(parent kvdb.KeyValueStore, drop func()) *Flushable {
 		underlying:     parent,
 		onDrop:         drop,
 		modified:       rbt.NewWithStringComparator(),
+		deleted:        new([]keyRange),
 		lock:           new(sync.Mutex),
 		sizeEstimation: new(int),
 	}
@@ -88,12 +91,19 @@ func (w *Flushable) Has(key []byte) (bool, error) {
 		return false, errClosed
 	}
 
-	val, ok := w.modified.Get(string(key))
+	return has(w.modified, *w.deleted, w.underlying, key)
+}
+
+func has(modified *rbt.Tree, deleted []keyRange, parent ethdb.KeyValueReader, key []byte) (bool, error) {
+	val, ok := modified.Get(string(key))
 	if ok {
 		return val != nil, nil
 	}
+	if inRanges(deleted, key) {
+		return false, nil
+	}
 
-	return w.underlying.Has(key)
+	return parent.Has(key)
 }
 
 // Get returns key-value pair by key. Looks in cache first, then - in DB.
@@ -105,14 +115,21 @@ func (w *Flushable) Get(key []byte) ([]byte, error) {
 		return nil, errClosed
 	}
 
-	if entry, ok := w.modified.Get(string(key)); ok {
+	return get(w.modified, *w.deleted, w.underlying, key)
+}
+
+func get(modified *rbt.Tree, deleted []keyRange, parent ethdb.KeyValueReader, key []byte) ([]byte, error) {
+	if entry, ok := modified.Get(string(key)); ok {
 		if entry == nil {
 			return nil, nil
 		}
 		return common.CopyBytes(entry.([]byte)), nil
 	}
+	if inRanges(deleted, key) {
+		return nil, nil
+	}
 
-	return w.underlying.Get(key)
+	return parent.Get(key)
 }
 
 // Delete removes key-value pair by key. In parent DB, key won't be deleted until .Flush() is called.
@@ -129,6 +146,66 @@ func (w *Flushable) delete(key []byte) error {
 	return nil
 }
 
+// DeleteRange removes all the keys in the range [start, limit).
+// A nil limit is treated as a key after all keys in the data store.
+// In parent DB, keys won't be deleted until .Flush() is called.
+func (w *Flushable) DeleteRange(start []byte, limit []byte) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.modified == nil {
+		return errClosed
+	}
+
+	r := keyRange{common.CopyBytes(start), common.CopyBytes(limit)}
+	// the modified pairs of the range are marked as deleted, the parent's ones are deleted by the range
+	var keys []string
+	for node, ok := w.modified.Ceiling(string(r.start)); ok && r.has([]byte(node.Key.(string))); node, ok = nextNode(w.modified, node) {
+		keys = append(keys, node.Key.(string))
+	}
+	for _, key := range keys {
+		w.modified.Put(key, nil)
+	}
+	*w.deleted = append(*w.deleted, r)
+	*w.sizeEstimation += len(start) + len(limit)
+
+	return nil
+}
+
+// GetSnapshot returns a snapshot of the DB, including the not flushed keys.
+// The underlying DB must be a kvdb.Snapshoter.
+func (w *Flushable) GetSnapshot() (kvdb.Snapshot, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.modified == nil {
+		return nil, errClosed
+	}
+
+	snapshoter, ok := w.underlying.(kvdb.Snapshoter)
+	if !ok {
+		return nil, errNoSnapshots
+	}
+	parent, err := snapshoter.GetSnapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	// values are never modified in place, so they aren't copied
+	modified := rbt.NewWithStringComparator()
+	for it := w.modified.Iterator(); it.Next(); {
+		modified.Put(it.Key(), it.Value())
+	}
+	deleted := append([]keyRange{}, *w.deleted...)
+
+	return &snapshot{
+		lock:     new(sync.Mutex),
+		modified: modified,
+		deleted:  &deleted,
+		parent:   parent,
+	}, nil
+}
+
 // DropNotFlushed drops all the not flushed keys.
 // After this call, the state of parent DB is identical to the state of this DB.
 func (w *Flushable) DropNotFlushed() {
@@ -140,6 +217,7 @@ func (w *Flushable) DropNotFlushed() {
 
 func (w *Flushable) dropNotFlushed() {
 	w.modified.Clear()
+	*w.deleted = nil
 	*w.sizeEstimation = 0
 }
 
@@ -168,9 +246,9 @@ func (w *Flushable) Drop() {
 	}
 }
 
-// NotFlushedPairs returns num of not flushed keys, including deleted keys.
+// NotFlushedPairs returns num of not flushed keys, including deleted keys and key ranges.
 func (w *Flushable) NotFlushedPairs() int {
-	return w.modified.Size()
+	return w.modified.Size() + len(*w.deleted)
 }
 
 // NotFlushedSizeEst returns estimation of not flushed data, including deleted keys.
@@ -191,6 +269,14 @@ func (w *Flushable) flush() error {
 		return errClosed
 	}
 
+	// the ranges are deleted first, as the modified pairs are newer
+	for _, r := range *w.deleted {
+		if err := kvdb.DeleteRange(w.underlying, r.start, r.limit); err != nil {
+			return err
+		}
+	}
+	*w.deleted = nil
+
 	batch := w.underlying.NewBatch()
 	for it := w.modified.Iterator(); it.Next(); {
 		var err error
@@ -236,7 +322,8 @@ func (w *Flushable) Compact(start []byte, limit []byte) error {
 type iterator struct {
 	lock *sync.Mutex
 
-	tree *rbt.Tree
+	tree    *rbt.Tree
+	deleted *[]keyRange
 
 	key, val []byte
 	prevKey  []byte
@@ -346,6 +433,9 @@ func (it *iterator) Next() bool {
 		if it.parentOk {
 			var ok bool
 			ok, it.parentOk = isSuitable(it.parentIt.Key(), it.prevKey)
+			if ok && inRanges(*it.deleted, it.parentIt.Key()) {
+				ok = false // deleted by a range
+			}
 
 			if ok {
 				it.key = common.CopyBytes(it.parentIt.Key()) // leveldb's iterator may use the same memory
@@ -387,6 +477,9 @@ func (it *iterator) Value() []byte {
 // Release releases associated resources. Release should always succeed and can
 // be called multiple times without causing error.
 func (it *iterator) Release() {
+	if it.parentIt == nil {
+		return // already released
+	}
 	it.parentIt.Release()
 	*it = iterator{}
 }
@@ -397,6 +490,7 @@ func (w *Flushable) NewIterator() ethdb.Iterator {
 	return &iterator{
 		lock:     w.lock,
 		tree:     w.modified,
+		deleted:  w.deleted,
 		parentIt: w.underlying.NewIterator(),
 	}
 }
@@ -408,6 +502,7 @@ func (w *Flushable) NewIteratorWithStart(start []byte) ethdb.Iterator {
 	return &iterator{
 		lock:     w.lock,
 		tree:     w.modified,
+		deleted:  w.deleted,
 		start:    start,
 		parentIt: w.underlying.NewIteratorWithStart(start),
 	}
@@ -419,6 +514,7 @@ func (w *Flushable) NewIteratorWithPrefix(prefix []byte) ethdb.Iterator {
 	return &iterator{
 		lock:     w.lock,
 		tree:     w.modified,
+		deleted:  w.deleted,
 		start:    prefix,
 		prefix:   prefix,
 		parentIt: w.underlying.NewIteratorWithPrefix(prefix),
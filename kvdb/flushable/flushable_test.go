@@ -14,6 +14,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/Fantom-foundation/go-lachesis/kvdb"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/dbtest"
 	"github.com/Fantom-foundation/go-lachesis/kvdb/leveldb"
 	"github.com/Fantom-foundation/go-lachesis/kvdb/memorydb"
 	"github.com/Fantom-foundation/go-lachesis/kvdb/table"
@@ -319,6 +320,83 @@ func TestFlushableIterator(t *testing.T) {
 	}
 }
 
+func TestFlushableSuite(t *testing.T) {
+	dbtest.TestDatabaseSuite(t, func() kvdb.KeyValueStore {
+		return Wrap(memorydb.New())
+	})
+}
+
+func TestFlushableDeleteRange(t *testing.T) {
+	assertar := assert.New(t)
+
+	parent := memorydb.New()
+	for _, key := range []string{"a", "b1", "b2", "c"} {
+		assertar.NoError(parent.Put([]byte(key), []byte("parent")))
+	}
+
+	db := Wrap(parent)
+	assertar.NoError(db.Put([]byte("b3"), []byte("cache")))
+	assertar.NoError(db.DeleteRange([]byte("b"), []byte("c")))
+	// the pairs written after the deletion aren't deleted
+	assertar.NoError(db.Put([]byte("b2"), []byte("new")))
+
+	snap, err := db.GetSnapshot()
+	if !assertar.NoError(err) {
+		return
+	}
+	defer snap.Release()
+
+	expect := func(db reader, exp map[string]string) {
+		got := map[string]string{}
+		it := db.NewIterator()
+		defer it.Release()
+		for it.Next() {
+			got[string(it.Key())] = string(it.Value())
+		}
+		assertar.NoError(it.Error())
+		assertar.Equal(exp, got)
+
+		for _, key := range []string{"a", "b1", "b2", "b3", "c"} {
+			val, err := db.Get([]byte(key))
+			assertar.NoError(err)
+			has, err := db.Has([]byte(key))
+			assertar.NoError(err)
+			if v, ok := exp[key]; ok {
+				assertar.Equal([]byte(v), val, key)
+				assertar.True(has, key)
+			} else {
+				assertar.Nil(val, key)
+				assertar.False(has, key)
+			}
+		}
+	}
+	exp := map[string]string{
+		"a":  "parent",
+		"b2": "new",
+		"c":  "parent",
+	}
+
+	expect(db, exp)
+	// the parent isn't changed until flush
+	assertar.Equal(4, parent.Len())
+
+	assertar.NoError(db.Flush())
+	assertar.Equal(0, db.NotFlushedPairs())
+	expect(db, exp)
+	expect(parent, exp)
+
+	// the snapshot isn't affected by the flush and the later changes
+	assertar.NoError(db.DeleteRange(nil, nil))
+	expect(db, map[string]string{})
+	expect(snap, exp)
+}
+
+// reader is either a DB or a snapshot.
+type reader interface {
+	ethdb.KeyValueReader
+	ethdb.Iteratee
+}
+
 func BenchmarkFlushable(b *testing.B) {
 	disk := dbProducer("BenchmarkFlushable")
 
@@ -0,0 +1,25 @@
+package flushable
+
+import (
+	"bytes"
+)
+
+// keyRange is a range [start, limit) of keys. A nil limit is treated as a key after all keys.
+type keyRange struct {
+	start, limit []byte
+}
+
+// has returns true if the key is in the range.
+func (r keyRange) has(key []byte) bool {
+	return bytes.Compare(key, r.start) >= 0 && (r.limit == nil || bytes.Compare(key, r.limit) < 0)
+}
+
+// inRanges returns true if the key is in any of the ranges.
+func inRanges(ranges []keyRange, key []byte) bool {
+	for _, r := range ranges {
+		if r.has(key) {
+			return true
+		}
+	}
+	return false
+}
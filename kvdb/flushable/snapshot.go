@@ -0,0 +1,78 @@
+package flushable
+
+import (
+	"sync"
+
+	rbt "github.com/emirpasic/gods/trees/redblacktree"
+	"github.com/ethereum/go-ethereum/ethdb"
+
+	"github.com/Fantom-foundation/go-lachesis/kvdb"
+)
+
+// snapshot is a read-only copy of the not flushed keys over a snapshot of the parent DB.
+type snapshot struct {
+	lock *sync.Mutex
+
+	modified *rbt.Tree
+	deleted  *[]keyRange
+
+	parent kvdb.Snapshot
+}
+
+// Has checks if key is in the exists. Looks in cache first, then - in DB.
+func (s *snapshot) Has(key []byte) (bool, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return has(s.modified, *s.deleted, s.parent, key)
+}
+
+// Get returns key-value pair by key. Looks in cache first, then - in DB.
+func (s *snapshot) Get(key []byte) ([]byte, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return get(s.modified, *s.deleted, s.parent, key)
+}
+
+// NewIterator creates a binary-alphabetical iterator over the entire keyspace
+// contained within the snapshot.
+func (s *snapshot) NewIterator() ethdb.Iterator {
+	return &iterator{
+		lock:     s.lock,
+		tree:     s.modified,
+		deleted:  s.deleted,
+		parentIt: s.parent.NewIterator(),
+	}
+}
+
+// NewIteratorWithStart creates a binary-alphabetical iterator over a subset of
+// snapshot content starting at a particular initial key (or after, if it does
+// not exist).
+func (s *snapshot) NewIteratorWithStart(start []byte) ethdb.Iterator {
+	return &iterator{
+		lock:     s.lock,
+		tree:     s.modified,
+		deleted:  s.deleted,
+		start:    start,
+		parentIt: s.parent.NewIteratorWithStart(start),
+	}
+}
+
+// NewIteratorWithPrefix creates a binary-alphabetical iterator over a subset
+// of snapshot content with a particular key prefix.
+func (s *snapshot) NewIteratorWithPrefix(prefix []byte) ethdb.Iterator {
+	return &iterator{
+		lock:     s.lock,
+		tree:     s.modified,
+		deleted:  s.deleted,
+		start:    prefix,
+		prefix:   prefix,
+		parentIt: s.parent.NewIteratorWithPrefix(prefix),
+	}
+}
+
+// Release releases the snapshot of the parent DB. It can be called multiple times.
+func (s *snapshot) Release() {
+	s.parent.Release()
+}
@@ -25,6 +25,18 @@ type SyncedPool struct {
 }
 
 func NewSyncedPool(producer kvdb.DbProducer) *SyncedPool {
+	p := NewUncheckedSyncedPool(producer)
+
+	if err := p.CheckDbsSynced(); err != nil {
+		log.Crit("Databases are corrupted, which is possible after a crash or disk failure.", "err", err)
+	}
+
+	return p
+}
+
+// NewUncheckedSyncedPool doesn't check that the DBs are synced,
+// it's intended for the offline tools which inspect and repair the DBs.
+func NewUncheckedSyncedPool(producer kvdb.DbProducer) *SyncedPool {
 	if producer == nil {
 		panic("nil producer")
 	}
@@ -37,11 +49,10 @@ func NewSyncedPool(producer kvdb.DbProducer) *SyncedPool {
 
 	for _, name := range producer.Names() {
 		open, drop := p.callbacks(name)
-		p.wrappers[name] = NewLazy(open, drop)
-	}
-
-	if err := p.checkDbsSynced(); err != nil {
-		log.Crit("Databases are corrupted, which is possible after a crash or disk failure.", "err", err)
+		wrapper := NewLazy(open, drop)
+		// existing DBs are read right away
+		wrapper.InitUnderlyingDb()
+		p.wrappers[name] = wrapper
 	}
 
 	return p
@@ -178,8 +189,8 @@ func (p *SyncedPool) IsFlushNeeded() bool {
 	return false
 }
 
-// checkDbsSynced on startup, after all dbs are registered.
-func (p *SyncedPool) checkDbsSynced() error {
+// CheckDbsSynced on startup, after all dbs are registered.
+func (p *SyncedPool) CheckDbsSynced() error {
 	p.Lock()
 	defer p.Unlock()
 
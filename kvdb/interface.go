@@ -34,3 +34,25 @@ type DbProducer interface {
 	// OpenDb or create db with name.
 	OpenDb(name string) KeyValueStore
 }
+
+// Snapshot is a read-only view of the database at the moment of the snapshot creation.
+type Snapshot interface {
+	ethdb.KeyValueReader
+	ethdb.Iteratee
+
+	// Release releases associated resources. Release should always succeed and can
+	// be called multiple times without causing error.
+	Release()
+}
+
+// Snapshoter is able to make consistent snapshots of the DB.
+type Snapshoter interface {
+	GetSnapshot() (Snapshot, error)
+}
+
+// RangeDeleter is able to delete a range of keys.
+type RangeDeleter interface {
+	// DeleteRange deletes all the keys in the range [start, limit).
+	// A nil limit is treated as a key after all keys in the data store.
+	DeleteRange(start []byte, limit []byte) error
+}
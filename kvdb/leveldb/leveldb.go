@@ -19,6 +19,8 @@ import (
 	"github.com/syndtr/goleveldb/leveldb/filter"
 	"github.com/syndtr/goleveldb/leveldb/opt"
 	"github.com/syndtr/goleveldb/leveldb/util"
+
+	"github.com/Fantom-foundation/go-lachesis/kvdb"
 )
 
 const (
@@ -37,6 +39,10 @@ const (
 	// metricsGatheringInterval specifies the interval to retrieve leveldb database
 	// compaction, io and pause stats to report to the user.
 	metricsGatheringInterval = 3 * time.Second
+
+	// rangeDeletionsBatch is the maximum number of keys which DeleteRange
+	// deletes in a single write.
+	rangeDeletionsBatch = 1000
 )
 
 // Database is a persistent key-value store. Apart from basic data storage
@@ -54,6 +60,7 @@ type Database struct {
 	diskSizeGauge    metrics.Gauge // Gauge for tracking the size of all the levels in the database
 	diskReadMeter    metrics.Meter // Meter for measuring the effective amount of data read
 	diskWriteMeter   metrics.Meter // Meter for measuring the effective amount of data written
+	metricNames      []string      // Names of the registered metrics, which are unregistered on close
 
 	quitLock sync.Mutex      // Mutex protecting the quit channel access
 	quitChan chan chan error // Quit channel to stop the metrics collection before closing the database
@@ -97,14 +104,15 @@ func New(path string, cache int, handles int, namespace string, close func() err
 		log:      logger,
 		quitChan: make(chan chan error),
 	}
-	ldb.compTimeMeter = metrics.NewRegisteredMeter(namespace+"compact/time", nil)
-	ldb.compReadMeter = metrics.NewRegisteredMeter(namespace+"compact/input", nil)
-	ldb.compWriteMeter = metrics.NewRegisteredMeter(namespace+"compact/output", nil)
+	ldb.compTimeMeter = ldb.registerMeter(namespace + "compact/time")
+	ldb.compReadMeter = ldb.registerMeter(namespace + "compact/input")
+	ldb.compWriteMeter = ldb.registerMeter(namespace + "compact/output")
 	ldb.diskSizeGauge = metrics.NewRegisteredGauge(namespace+"disk/size", nil)
-	ldb.diskReadMeter = metrics.NewRegisteredMeter(namespace+"disk/read", nil)
-	ldb.diskWriteMeter = metrics.NewRegisteredMeter(namespace+"disk/write", nil)
-	ldb.writeDelayMeter = metrics.NewRegisteredMeter(namespace+"compact/writedelay/duration", nil)
-	ldb.writeDelayNMeter = metrics.NewRegisteredMeter(namespace+"compact/writedelay/counter", nil)
+	ldb.metricNames = append(ldb.metricNames, namespace+"disk/size")
+	ldb.diskReadMeter = ldb.registerMeter(namespace + "disk/read")
+	ldb.diskWriteMeter = ldb.registerMeter(namespace + "disk/write")
+	ldb.writeDelayMeter = ldb.registerMeter(namespace + "compact/writedelay/duration")
+	ldb.writeDelayNMeter = ldb.registerMeter(namespace + "compact/writedelay/counter")
 
 	ldb.onClose = close
 	ldb.onDrop = drop
@@ -114,6 +122,11 @@ func New(path string, cache int, handles int, namespace string, close func() err
 	return ldb, nil
 }
 
+func (db *Database) registerMeter(name string) metrics.Meter {
+	db.metricNames = append(db.metricNames, name)
+	return metrics.NewRegisteredMeter(name, nil)
+}
+
 // Close stops the metrics collection, flushes any pending data to disk and closes
 // all io accesses to the underlying key-value store.
 func (db *Database) Close() error {
@@ -136,6 +149,11 @@ func (db *Database) Close() error {
 	ldb := db.db
 	db.db = nil
 
+	// the DB may be reopened with the same metrics namespace
+	for _, name := range db.metricNames {
+		metrics.DefaultRegistry.Unregister(name)
+	}
+
 	if db.onClose != nil {
 		if err := db.onClose(); err != nil {
 			return err
@@ -182,6 +200,47 @@ func (db *Database) Delete(key []byte) error {
 	return db.db.Delete(key, nil)
 }
 
+// DeleteRange deletes all the keys in the range [start, limit).
+// A nil limit is treated as a key after all keys in the data store.
+// LevelDB has no range tombstones, so the keys are deleted by batches,
+// and then the range is compacted to drop the deleted values from disk.
+func (db *Database) DeleteRange(start []byte, limit []byte) error {
+	it := db.db.NewIterator(&util.Range{Start: start, Limit: limit}, nil)
+	defer it.Release()
+
+	b := new(leveldb.Batch)
+	deleted := 0
+	for it.Next() {
+		b.Delete(it.Key())
+		deleted++
+		if b.Len() >= rangeDeletionsBatch {
+			if err := db.db.Write(b, nil); err != nil {
+				return err
+			}
+			b.Reset()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	if err := db.db.Write(b, nil); err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return nil
+	}
+	return db.db.CompactRange(util.Range{Start: start, Limit: limit})
+}
+
+// GetSnapshot returns a consistent read-only view of the database.
+func (db *Database) GetSnapshot() (kvdb.Snapshot, error) {
+	snap, err := db.db.GetSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot{snap}, nil
+}
+
 // NewBatch creates a write-only key-value store that buffers changes to its host
 // database until a final write is called.
 func (db *Database) NewBatch() ethdb.Batch {
@@ -473,3 +532,46 @@ func (r *replayer) Delete(key []byte) {
 	}
 	r.failure = r.writer.Delete(key)
 }
+
+// snapshot is a consistent read-only view of the database.
+type snapshot struct {
+	snap *leveldb.Snapshot
+}
+
+// Has retrieves if a key is present in the snapshot.
+func (s *snapshot) Has(key []byte) (bool, error) {
+	return s.snap.Has(key, nil)
+}
+
+// Get retrieves the given key if it's present in the snapshot.
+func (s *snapshot) Get(key []byte) ([]byte, error) {
+	dat, err := s.snap.Get(key, nil)
+	if err != nil && err == leveldb.ErrNotFound {
+		return nil, nil
+	}
+	return dat, err
+}
+
+// NewIterator creates a binary-alphabetical iterator over the entire keyspace
+// of the snapshot.
+func (s *snapshot) NewIterator() ethdb.Iterator {
+	return s.snap.NewIterator(new(util.Range), nil)
+}
+
+// NewIteratorWithStart creates a binary-alphabetical iterator over a subset of
+// the snapshot content starting at a particular initial key (or after, if it does
+// not exist).
+func (s *snapshot) NewIteratorWithStart(start []byte) ethdb.Iterator {
+	return s.snap.NewIterator(&util.Range{Start: start}, nil)
+}
+
+// NewIteratorWithPrefix creates a binary-alphabetical iterator over a subset
+// of the snapshot content with a particular key prefix.
+func (s *snapshot) NewIteratorWithPrefix(prefix []byte) ethdb.Iterator {
+	return s.snap.NewIterator(util.BytesPrefix(prefix), nil)
+}
+
+// Release releases the snapshot. It can be called multiple times.
+func (s *snapshot) Release() {
+	s.snap.Release()
+}
@@ -0,0 +1,57 @@
+package leveldb
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/kvdb"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/dbtest"
+)
+
+func TestLevelDBSuite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "test-leveldb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	i := 0
+	dbtest.TestDatabaseSuite(t, func() kvdb.KeyValueStore {
+		i++
+		db, err := New(filepath.Join(dir, strconv.Itoa(i)), 0, 0, "", nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return db
+	})
+}
+
+func TestProducerMetricsNamespace(t *testing.T) {
+	assertar := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "test-leveldb-metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	producer := NewProducer(dir)
+	a := producer.OpenDb("a")
+	b := producer.OpenDb("b")
+
+	// each DB has its own metrics
+	assertar.NotNil(metrics.DefaultRegistry.Get("lachesis/db/a/disk/size"))
+	assertar.NotNil(metrics.DefaultRegistry.Get("lachesis/db/b/disk/size"))
+
+	// the metrics are unregistered on close, so the DB may be reopened
+	assertar.NoError(a.Close())
+	assertar.Nil(metrics.DefaultRegistry.Get("lachesis/db/a/disk/size"))
+	assertar.NotNil(metrics.DefaultRegistry.Get("lachesis/db/b/disk/size"))
+	assertar.NoError(b.Close())
+}
@@ -42,6 +42,11 @@ func (p *producer) Names() []string {
 	return names
 }
 
+// metricsNamespace is the metrics prefix of the DB, which is unique for the DB name.
+func metricsNamespace(name string) string {
+	return "lachesis/db/" + name + "/"
+}
+
 // OpenDb or create db with name.
 func (p *producer) OpenDb(name string) kvdb.KeyValueStore {
 	dir := name + "-ldb"
@@ -67,7 +72,7 @@ func (p *producer) OpenDb(name string) kvdb.KeyValueStore {
 
 	}
 
-	db, err := New(path, 64, 0, "", onClose, onDrop)
+	db, err := New(path, 64, 0, metricsNamespace(name), onClose, onDrop)
 	if err != nil {
 		panic(err)
 	}
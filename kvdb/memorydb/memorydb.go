@@ -10,6 +10,8 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethdb"
+
+	"github.com/Fantom-foundation/go-lachesis/kvdb"
 )
 
 var (
@@ -148,6 +150,43 @@ func (db *Database) Delete(key []byte) error {
 	return nil
 }
 
+// DeleteRange deletes all the keys in the range [start, limit).
+// A nil limit is treated as a key after all keys in the data store.
+func (db *Database) DeleteRange(start []byte, limit []byte) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.db == nil {
+		return errMemorydbClosed
+	}
+
+	st, lt := string(start), string(limit)
+	for key := range db.db {
+		if key >= st && (limit == nil || key < lt) {
+			delete(db.db, key)
+		}
+	}
+	return nil
+}
+
+// GetSnapshot returns a copy of the database.
+func (db *Database) GetSnapshot() (kvdb.Snapshot, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return nil, errMemorydbClosed
+	}
+
+	cp := NewWithCap(len(db.db))
+	for key, value := range db.db {
+		// values are never modified in place, so they aren't copied
+		cp.db[key] = value
+	}
+	cp.lag = db.lag
+	return &snapshot{cp}, nil
+}
+
 // NewBatch creates a write-only key-value store that buffers changes to its host
 // database until a final write is called.
 func (db *Database) NewBatch() ethdb.Batch {
@@ -242,6 +281,16 @@ func (db *Database) Len() int {
 	return len(db.db)
 }
 
+// snapshot is a read-only copy of the database.
+type snapshot struct {
+	*Database
+}
+
+// Release releases the snapshot. It can be called multiple times.
+func (s *snapshot) Release() {
+	_ = s.Database.Close()
+}
+
 // keyvalue is a key-value tuple tagged with a deletion field to allow creating
 // memory-database write batches.
 type keyvalue struct {
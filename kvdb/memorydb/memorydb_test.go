@@ -3,8 +3,17 @@ package memorydb
 import (
 	"bytes"
 	"testing"
+
+	"github.com/Fantom-foundation/go-lachesis/kvdb"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/dbtest"
 )
 
+func TestMemoryDBSuite(t *testing.T) {
+	dbtest.TestDatabaseSuite(t, func() kvdb.KeyValueStore {
+		return New()
+	})
+}
+
 // Tests that key-value iteration on top of a memory database works.
 func TestMemoryDBIterator(t *testing.T) {
 	tests := []struct {
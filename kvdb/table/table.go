@@ -2,8 +2,11 @@ package table
 
 import (
 	"bytes"
+	"errors"
 
 	"github.com/ethereum/go-ethereum/ethdb"
+
+	"github.com/Fantom-foundation/go-lachesis/kvdb"
 )
 
 // Table wraps the underling DB, so all the table's data is stored with a prefix in underling DB
@@ -15,6 +18,8 @@ type Table struct {
 var (
 	// NOTE: key collisions are possible
 	separator = []byte{}
+
+	errNoSnapshots = errors.New("underlying DB doesn't support snapshots")
 )
 
 // prefixed key (prefix + separator + key)
@@ -68,6 +73,29 @@ func (t *Table) Delete(key []byte) error {
 	return t.db.Delete(prefixed(key, t.prefix))
 }
 
+// DeleteRange deletes all the table's keys in the range [start, limit).
+// A nil limit is treated as a key after all the table's keys.
+func (t *Table) DeleteRange(start []byte, limit []byte) error {
+	end := kvdb.UpperBound(t.prefix)
+	if limit != nil {
+		end = prefixed(limit, t.prefix)
+	}
+	return kvdb.DeleteRange(t.db, prefixed(start, t.prefix), end)
+}
+
+// GetSnapshot returns a snapshot of the table if the underlying DB is a kvdb.Snapshoter.
+func (t *Table) GetSnapshot() (kvdb.Snapshot, error) {
+	snapshoter, ok := t.db.(kvdb.Snapshoter)
+	if !ok {
+		return nil, errNoSnapshots
+	}
+	snap, err := snapshoter.GetSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot{snap, t.prefix}, nil
+}
+
 func (t *Table) NewBatch() ethdb.Batch {
 	return &batch{t.db.NewBatch(), t.prefix}
 }
@@ -110,6 +138,9 @@ func (it *iterator) Value() []byte {
 }
 
 func (it *iterator) Release() {
+	if it.it == nil {
+		return // already released
+	}
 	it.it.Release()
 	*it = iterator{}
 }
@@ -126,6 +157,39 @@ func (t *Table) NewIteratorWithPrefix(itPrefix []byte) ethdb.Iterator {
 	return &iterator{t.db.NewIteratorWithPrefix(prefixed(itPrefix, t.prefix)), t.prefix}
 }
 
+/*
+ * Snapshot
+ */
+
+type snapshot struct {
+	snap   kvdb.Snapshot
+	prefix []byte
+}
+
+func (s *snapshot) Has(key []byte) (bool, error) {
+	return s.snap.Has(prefixed(key, s.prefix))
+}
+
+func (s *snapshot) Get(key []byte) ([]byte, error) {
+	return s.snap.Get(prefixed(key, s.prefix))
+}
+
+func (s *snapshot) NewIterator() ethdb.Iterator {
+	return &iterator{s.snap.NewIteratorWithPrefix(s.prefix), s.prefix}
+}
+
+func (s *snapshot) NewIteratorWithStart(start []byte) ethdb.Iterator {
+	return &iterator{s.snap.NewIteratorWithStart(prefixed(start, s.prefix)), s.prefix}
+}
+
+func (s *snapshot) NewIteratorWithPrefix(itPrefix []byte) ethdb.Iterator {
+	return &iterator{s.snap.NewIteratorWithPrefix(prefixed(itPrefix, s.prefix)), s.prefix}
+}
+
+func (s *snapshot) Release() {
+	s.snap.Release()
+}
+
 /*
  * Batch
  */
@@ -11,9 +11,11 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/Fantom-foundation/go-lachesis/kvdb"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/dbtest"
 	"github.com/Fantom-foundation/go-lachesis/kvdb/flushable"
 	"github.com/Fantom-foundation/go-lachesis/kvdb/leveldb"
 	"github.com/Fantom-foundation/go-lachesis/kvdb/memorydb"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/skiperrors"
 )
 
 func tempLevelDB(name string) *leveldb.Database {
@@ -36,6 +38,58 @@ func tempLevelDB(name string) *leveldb.Database {
 	return diskdb
 }
 
+func TestTableSuite(t *testing.T) {
+	for name, newDb := range map[string]func() kvdb.KeyValueStore{
+		"memory": func() kvdb.KeyValueStore {
+			return memorydb.New()
+		},
+		"cache-over-memory": func() kvdb.KeyValueStore {
+			return flushable.Wrap(memorydb.New())
+		},
+	} {
+		newDb := newDb
+		t.Run(name, func(t *testing.T) {
+			dbtest.TestDatabaseSuite(t, func() kvdb.KeyValueStore {
+				db := newDb()
+				// the neighbour tables mustn't be affected
+				for _, prefix := range []string{"s", "u"} {
+					err := New(db, []byte(prefix)).Put([]byte("neighbour"), []byte("neighbour"))
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+				return New(db, []byte("t"))
+			})
+		})
+	}
+}
+
+func TestTableDeleteRangeOneByOne(t *testing.T) {
+	assertar := assert.New(t)
+
+	// the wrapper isn't a kvdb.RangeDeleter, so the keys are deleted one by one
+	db := skiperrors.Wrap(memorydb.New())
+	t1 := New(db, []byte{0x01, 0xff})
+	t2 := New(db, []byte{0x02})
+	for _, key := range []string{"a", "b", "c"} {
+		assertar.NoError(t1.Put([]byte(key), []byte(key)))
+		assertar.NoError(t2.Put([]byte(key), []byte(key)))
+	}
+
+	assertar.NoError(t1.DeleteRange([]byte("b"), nil))
+	assertar.NoError(t2.DeleteRange(nil, []byte("b")))
+
+	for _, key := range []string{"a", "b", "c"} {
+		has, err := t1.Has([]byte(key))
+		assertar.NoError(err)
+		assertar.Equal(key < "b", has, key)
+
+		has, err = t2.Has([]byte(key))
+		assertar.NoError(err)
+		assertar.Equal(key >= "b", has, key)
+	}
+}
+
 func TestTable(t *testing.T) {
 	prefix0 := map[string][]byte{
 		"00": []byte{0},
@@ -6,6 +6,7 @@ import (
 
 	ethparams "github.com/ethereum/go-ethereum/params"
 
+	"github.com/Fantom-foundation/go-lachesis/hash"
 	"github.com/Fantom-foundation/go-lachesis/inter"
 	"github.com/Fantom-foundation/go-lachesis/inter/idx"
 	"github.com/Fantom-foundation/go-lachesis/lachesis/genesis"
@@ -43,6 +44,8 @@ type DagConfig struct {
 	MaxEpochBlocks   idx.Frame     `json:"maxEpochBlocks"`
 	MaxEpochDuration time.Duration `json:"maxEpochDuration"`
 
+	EpochSealing EpochSealingConfig `json:"epochSealing"`
+
 	VectorClockConfig vector.IndexConfig `json:"vectorClockConfig"`
 
 	MaxValidatorEventsInBlock idx.Event `json:"maxValidatorEventsInBlock"`
@@ -87,13 +90,30 @@ type Config struct {
 
 	// Economy options
 	Economy EconomyConfig
+
+	// Upgrades of the options, ordered by epoch
+	Upgrades []Upgrade
 }
 
-// EvmChainConfig returns ChainConfig for transaction signing and execution
+// EvmChainConfig returns ChainConfig of the genesis rules.
+// Use Rules(epoch).EvmChainConfig() for transaction execution in the epoch.
 func (c *Config) EvmChainConfig() *ethparams.ChainConfig {
-	cfg := *ethparams.AllEthashProtocolChanges
-	cfg.ChainID = new(big.Int).SetUint64(c.NetworkID)
-	return &cfg
+	rules := c.Rules(0)
+	return rules.EvmChainConfig()
+}
+
+// GenesisAtropos returns the Atropos ID of the genesis block, which is the hash of the genesis block.
+func (c *Config) GenesisAtropos() hash.Event {
+	e := inter.NewEvent()
+	// for nice-looking ID
+	e.Epoch = 0
+	e.Lamport = idx.Lamport(c.Dag.MaxEpochBlocks)
+	// actual data hashed
+	e.Extra = c.Genesis.ExtraData
+	e.ClaimedTime = c.Genesis.Time
+	e.TxHash = c.Genesis.Alloc.Accounts.Hash()
+
+	return e.CalcHash()
 }
 
 func MainNetConfig() Config {
@@ -132,6 +152,9 @@ func FakeNetConfig(accs genesis.VAccounts) Config {
 		Blocks: BlocksConfig{
 			BlockGasHardLimit: 20000000,
 		},
+		Upgrades: []Upgrade{
+			LightClientUpgrade(1),
+		},
 	}
 }
 
@@ -172,10 +195,13 @@ func FakeEconomyConfig() EconomyConfig {
 
 func DefaultDagConfig() DagConfig {
 	return DagConfig{
-		MaxParents:                10,
-		MaxFreeParents:            3,
-		MaxEpochBlocks:            1000,
-		MaxEpochDuration:          4 * time.Hour,
+		MaxParents:       10,
+		MaxFreeParents:   3,
+		MaxEpochBlocks:   1000,
+		MaxEpochDuration: 4 * time.Hour,
+		EpochSealing: EpochSealingConfig{
+			Policy: DefaultSealingPolicy,
+		},
 		MaxValidatorEventsInBlock: 50,
 		VectorClockConfig:         vector.DefaultIndexConfig(),
 	}
@@ -185,6 +211,7 @@ func FakeNetDagConfig() DagConfig {
 	cfg := DefaultDagConfig()
 	cfg.MaxEpochBlocks = 200
 	cfg.MaxEpochDuration = 10 * time.Minute
+	cfg.VectorClockConfig.EvictCheaters = true
 	return cfg
 }
 
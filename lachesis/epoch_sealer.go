@@ -0,0 +1,130 @@
+package lachesis
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+)
+
+// Built-in epoch sealing policies
+const (
+	// DefaultSealingPolicy seals the epoch after MaxEpochBlocks or MaxEpochDuration.
+	DefaultSealingPolicy = "default"
+	// BlocksSealingPolicy seals the epoch after MaxEpochBlocks only.
+	BlocksSealingPolicy = "blocks"
+	// GasSealingPolicy seals the epoch after MaxEpochGas is used by the epoch blocks.
+	GasSealingPolicy = "gas"
+	// StakersSealingPolicy seals the epoch as soon as the active SFC stakers differ from the epoch validators.
+	StakersSealingPolicy = "stakers"
+)
+
+// EpochSealingConfig selects the epoch sealing policy. Every policy seals the epoch after MaxEpochBlocks anyway.
+type EpochSealingConfig struct {
+	Policy string `json:"policy"`
+
+	// MaxEpochGas is the gas used by the epoch blocks, after which the epoch is sealed by the gas policy.
+	MaxEpochGas uint64 `json:"maxEpochGas"`
+}
+
+// EpochSealingContext is the state which the epoch sealing is decided by.
+// It's the state before the block is applied.
+type EpochSealingContext struct {
+	Block        *inter.Block
+	DecidedFrame idx.Frame
+	EpochStart   inter.Timestamp
+	// EpochGasUsed is the gas used by the previous blocks of the epoch.
+	EpochGasUsed uint64
+	// StakersChanged returns true if the active SFC stakers differ from the epoch validators.
+	StakersChanged func() bool
+}
+
+// EpochSealer decides whether the block seals the epoch.
+// The decision must be deterministic, because every node of the network makes it.
+type EpochSealer interface {
+	SealEpoch(ctx *EpochSealingContext) bool
+}
+
+// EpochSealerFunc is an adapter to use a function as EpochSealer.
+type EpochSealerFunc func(ctx *EpochSealingContext) bool
+
+// SealEpoch calls f(ctx).
+func (f EpochSealerFunc) SealEpoch(ctx *EpochSealingContext) bool {
+	return f(ctx)
+}
+
+// EpochSealerConstructor makes the sealer of the policy with the DAG config.
+type EpochSealerConstructor func(c *DagConfig) EpochSealer
+
+var (
+	epochSealers = map[string]EpochSealerConstructor{
+		DefaultSealingPolicy: func(c *DagConfig) EpochSealer {
+			return EpochSealerFunc(func(ctx *EpochSealingContext) bool {
+				return ctx.Block.Time-ctx.EpochStart >= inter.Timestamp(c.MaxEpochDuration)
+			})
+		},
+		BlocksSealingPolicy: func(c *DagConfig) EpochSealer {
+			return EpochSealerFunc(func(ctx *EpochSealingContext) bool {
+				return false
+			})
+		},
+		GasSealingPolicy: func(c *DagConfig) EpochSealer {
+			return EpochSealerFunc(func(ctx *EpochSealingContext) bool {
+				return ctx.EpochGasUsed >= c.EpochSealing.MaxEpochGas
+			})
+		},
+		StakersSealingPolicy: func(c *DagConfig) EpochSealer {
+			return EpochSealerFunc(func(ctx *EpochSealingContext) bool {
+				return ctx.StakersChanged()
+			})
+		},
+	}
+	epochSealersMu sync.RWMutex
+)
+
+// RegisterEpochSealer adds the custom epoch sealing policy, which may be selected by EpochSealingConfig.Policy.
+// Note that all the nodes of the network must have the policy registered.
+func RegisterEpochSealer(policy string, constructor EpochSealerConstructor) {
+	epochSealersMu.Lock()
+	defer epochSealersMu.Unlock()
+
+	epochSealers[policy] = constructor
+}
+
+// EpochSealingPolicies returns the names of the registered epoch sealing policies.
+func EpochSealingPolicies() []string {
+	epochSealersMu.RLock()
+	defer epochSealersMu.RUnlock()
+
+	policies := make([]string, 0, len(epochSealers))
+	for policy := range epochSealers {
+		policies = append(policies, policy)
+	}
+	sort.Strings(policies)
+	return policies
+}
+
+// EpochSealer returns the sealer of the configured policy. An empty policy is the default one.
+func (c *DagConfig) EpochSealer() (EpochSealer, error) {
+	policy := c.EpochSealing.Policy
+	if policy == "" {
+		policy = DefaultSealingPolicy
+	}
+
+	epochSealersMu.RLock()
+	constructor, ok := epochSealers[policy]
+	epochSealersMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown epoch sealing policy %q, the registered ones are %v", policy, EpochSealingPolicies())
+	}
+	if policy == GasSealingPolicy && c.EpochSealing.MaxEpochGas == 0 {
+		return nil, fmt.Errorf("epoch sealing policy %q requires MaxEpochGas", policy)
+	}
+
+	sealer := constructor(c)
+	return EpochSealerFunc(func(ctx *EpochSealingContext) bool {
+		return ctx.DecidedFrame >= c.MaxEpochBlocks || sealer.SealEpoch(ctx)
+	}), nil
+}
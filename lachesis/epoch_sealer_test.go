@@ -0,0 +1,79 @@
+package lachesis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+)
+
+func TestEpochSealer(t *testing.T) {
+	assertar := assert.New(t)
+
+	stakersChanged := false
+	ctx := func(frame, duration int, gas uint64) *EpochSealingContext {
+		return &EpochSealingContext{
+			Block:        &inter.Block{Time: inter.Timestamp(100 + duration)},
+			DecidedFrame: idx.Frame(frame),
+			EpochStart:   100,
+			EpochGasUsed: gas,
+			StakersChanged: func() bool {
+				return stakersChanged
+			},
+		}
+	}
+
+	c := DefaultDagConfig()
+	c.MaxEpochBlocks = 10
+	c.MaxEpochDuration = 50 * time.Nanosecond
+	c.EpochSealing.MaxEpochGas = 1000
+
+	for policy, expect := range map[string][]bool{
+		"":                   {false, true, true, false, false},
+		DefaultSealingPolicy: {false, true, true, false, false},
+		BlocksSealingPolicy:  {false, true, false, false, false},
+		GasSealingPolicy:     {false, true, false, true, false},
+		StakersSealingPolicy: {false, true, false, false, true},
+	} {
+		c.EpochSealing.Policy = policy
+		sealer, err := c.EpochSealer()
+		if !assertar.NoError(err, policy) {
+			return
+		}
+		stakersChanged = false
+		assertar.Equal(expect[0], sealer.SealEpoch(ctx(9, 49, 999)), policy)
+		assertar.Equal(expect[1], sealer.SealEpoch(ctx(10, 0, 0)), policy)
+		assertar.Equal(expect[2], sealer.SealEpoch(ctx(1, 50, 0)), policy)
+		assertar.Equal(expect[3], sealer.SealEpoch(ctx(1, 0, 1000)), policy)
+		stakersChanged = true
+		assertar.Equal(expect[4], sealer.SealEpoch(ctx(1, 0, 0)), policy)
+	}
+
+	c.EpochSealing.Policy = "unknown"
+	_, err := c.EpochSealer()
+	assertar.Error(err)
+
+	c.EpochSealing.Policy = GasSealingPolicy
+	c.EpochSealing.MaxEpochGas = 0
+	_, err = c.EpochSealer()
+	assertar.Error(err)
+
+	// custom policy
+	RegisterEpochSealer("odd", func(c *DagConfig) EpochSealer {
+		return EpochSealerFunc(func(ctx *EpochSealingContext) bool {
+			return ctx.DecidedFrame%2 == 1
+		})
+	})
+	assertar.Contains(EpochSealingPolicies(), "odd")
+	c.EpochSealing.Policy = "odd"
+	sealer, err := c.EpochSealer()
+	if !assertar.NoError(err) {
+		return
+	}
+	assertar.True(sealer.SealEpoch(ctx(3, 0, 0)))
+	assertar.False(sealer.SealEpoch(ctx(4, 0, 0)))
+	assertar.True(sealer.SealEpoch(ctx(10, 0, 0)))
+}
@@ -0,0 +1,88 @@
+package genesis
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/inter/pos"
+	"github.com/Fantom-foundation/go-lachesis/lachesis/genesis/sfc"
+)
+
+// SFC contract binaries, which may be pre-deployed by the genesis spec
+const (
+	MainSfcContract = "main"
+	TestSfcContract = "test"
+)
+
+// Spec defines the genesis of a network in a form which may be stored in a file.
+// The SFC contract isn't a part of the spec, it's pre-deployed from the validators.
+type Spec struct {
+	Time             inter.Timestamp `json:"time"`
+	ExtraData        hexutil.Bytes   `json:"extraData,omitempty"`
+	Accounts         Accounts        `json:"accounts"`
+	Validators       pos.GValidators `json:"validators"`
+	SfcContractAdmin common.Address  `json:"sfcContractAdmin"`
+	SfcContract      string          `json:"sfcContract"`
+}
+
+// Genesis checks the spec and builds the genesis with the pre-deployed SFC contract.
+func (s *Spec) Genesis() (Genesis, error) {
+	if s.Time == 0 {
+		return Genesis{}, errors.New("genesis time isn't set")
+	}
+	if len(s.Validators) == 0 {
+		return Genesis{}, errors.New("genesis has no validators")
+	}
+	if s.SfcContractAdmin == (common.Address{}) {
+		return Genesis{}, errors.New("SFC contract admin isn't set")
+	}
+	ids := make(map[idx.StakerID]bool, len(s.Validators))
+	for _, v := range s.Validators {
+		if v.ID == 0 || ids[v.ID] {
+			return Genesis{}, fmt.Errorf("validator %d: ID must be unique and non-zero", v.ID)
+		}
+		ids[v.ID] = true
+		if v.Stake == nil || v.Stake.Sign() <= 0 {
+			return Genesis{}, fmt.Errorf("validator %d: stake must be positive", v.ID)
+		}
+	}
+
+	var contract []byte
+	switch s.SfcContract {
+	case MainSfcContract:
+		contract = sfc.GetMainContractBinV1()
+	case TestSfcContract, "":
+		contract = sfc.GetTestContractBinV1()
+	default:
+		return Genesis{}, fmt.Errorf("unknown SFC contract %q", s.SfcContract)
+	}
+
+	// the SFC accounts are added, so copy the allocations
+	accounts := make(Accounts, len(s.Accounts)+2)
+	for addr, acc := range s.Accounts {
+		if addr == sfc.ContractAddress || addr == sfc.ContractAddressV1 {
+			return Genesis{}, fmt.Errorf("account %s is reserved for the SFC contract", addr.Hex())
+		}
+		if acc.Balance == nil {
+			acc.Balance = new(big.Int)
+		}
+		accounts[addr] = acc
+	}
+
+	g := Genesis{
+		Alloc: VAccounts{
+			Accounts:         accounts,
+			Validators:       s.Validators,
+			SfcContractAdmin: s.SfcContractAdmin,
+		},
+		Time:      s.Time,
+		ExtraData: s.ExtraData,
+	}
+	return preDeploySfc(g, contract), nil
+}
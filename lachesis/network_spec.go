@@ -0,0 +1,158 @@
+package lachesis
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/Fantom-foundation/go-lachesis/lachesis/genesis"
+)
+
+// NetworkSpec is the definition of a network, which may be stored in a file
+// to run a private network without the builtin config.
+type NetworkSpec struct {
+	Name      string       `json:"name"`
+	NetworkID uint64       `json:"networkId"`
+	Genesis   genesis.Spec `json:"genesis"`
+
+	Dag      DagConfig     `json:"dag"`
+	Blocks   BlocksConfig  `json:"blocks"`
+	Economy  EconomyConfig `json:"economy"`
+	Upgrades []Upgrade     `json:"upgrades,omitempty"`
+}
+
+// DefaultNetworkSpec returns the spec with the default rules and without genesis.
+func DefaultNetworkSpec(name string, networkID uint64) NetworkSpec {
+	return NetworkSpec{
+		Name:      name,
+		NetworkID: networkID,
+		Genesis: genesis.Spec{
+			Accounts:    genesis.Accounts{},
+			SfcContract: genesis.MainSfcContract,
+		},
+		Dag:     DefaultDagConfig(),
+		Economy: DefaultEconomyConfig(),
+		Blocks: BlocksConfig{
+			BlockGasHardLimit: 20000000,
+		},
+		Upgrades: []Upgrade{
+			LightClientUpgrade(1),
+		},
+	}
+}
+
+// specHashVersion is the version of the canonical encoding, which the spec hash is calculated of.
+const specHashVersion = "lachesis/network-spec/1"
+
+// Hash returns the canonical hash of the spec, which doesn't depend on the file format.
+// The fields with zero values aren't hashed, so the fields which are added to the config
+// later don't change the hash of the specs which don't set them.
+func (s *NetworkSpec) Hash() common.Hash {
+	h, err := canonicalHash(s)
+	if err != nil {
+		panic(err)
+	}
+	return h
+}
+
+// canonicalHash hashes the JSON of v with the sorted keys and without the zero values,
+// so the hash doesn't depend on the order of the struct fields and on the omitted fields.
+func canonicalHash(v interface{}) (common.Hash, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	var tree interface{}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&tree); err != nil {
+		return common.Hash{}, err
+	}
+	// the map keys are sorted by the encoder
+	canonical, err := json.Marshal(withoutZeros(tree))
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash([]byte(specHashVersion), canonical), nil
+}
+
+// withoutZeros removes the zero values from the decoded JSON, it returns nil if the value is zero.
+// The array elements aren't removed, as their positions matter.
+func withoutZeros(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, val := range v {
+			if val = withoutZeros(val); val == nil {
+				delete(v, key)
+			} else {
+				v[key] = val
+			}
+		}
+		if len(v) == 0 {
+			return nil
+		}
+		return v
+	case []interface{}:
+		if len(v) == 0 {
+			return nil
+		}
+		for i := range v {
+			v[i] = withoutZeros(v[i])
+		}
+		return v
+	case json.Number:
+		if n, ok := new(big.Float).SetString(string(v)); ok && n.Sign() == 0 {
+			return nil
+		}
+		return v
+	case string:
+		if v == "" || strings.HasPrefix(v, "0x") && strings.Trim(v[2:], "0") == "" {
+			// empty string, zero hash or address
+			return nil
+		}
+		return v
+	case bool:
+		if !v {
+			return nil
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// Config checks the spec and builds the network config.
+func (s *NetworkSpec) Config() (Config, error) {
+	if s.Name == "" {
+		return Config{}, errors.New("network name isn't set")
+	}
+	if s.NetworkID == 0 {
+		return Config{}, errors.New("network ID isn't set")
+	}
+	g, err := s.Genesis.Genesis()
+	if err != nil {
+		return Config{}, err
+	}
+
+	cfg := Config{
+		Name:      s.Name,
+		NetworkID: s.NetworkID,
+		Genesis:   g,
+		Dag:       s.Dag,
+		Blocks:    s.Blocks,
+		Economy:   s.Economy,
+		Upgrades:  s.Upgrades,
+	}
+	if _, err := cfg.Dag.EpochSealer(); err != nil {
+		return Config{}, err
+	}
+	if err := cfg.ValidateUpgrades(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
@@ -0,0 +1,107 @@
+package lachesis
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/pos"
+	"github.com/Fantom-foundation/go-lachesis/lachesis/genesis"
+	"github.com/Fantom-foundation/go-lachesis/lachesis/genesis/sfc"
+)
+
+func TestNetworkSpecConfig(t *testing.T) {
+	assertar := assert.New(t)
+
+	validator := common.HexToAddress("0x541E408443A592C38e01Bed0cB31f9De8c1322d0")
+	spec := DefaultNetworkSpec("private", 4000)
+	spec.Genesis.Time = inter.Timestamp(1600000000)
+	spec.Genesis.Accounts[validator] = genesis.Account{Balance: big.NewInt(1)}
+	spec.Genesis.Validators = pos.GValidators{{ID: 1, Address: validator, Stake: big.NewInt(100)}}
+	spec.Genesis.SfcContractAdmin = validator
+
+	cfg, err := spec.Config()
+	if !assertar.NoError(err) {
+		return
+	}
+	assertar.Equal("private", cfg.Name)
+	assertar.Equal(uint64(4000), cfg.EvmChainConfig().ChainID.Uint64())
+	assertar.Equal(spec.Dag, cfg.Dag)
+	// the SFC is pre-deployed, the spec isn't changed
+	assertar.Equal(3, len(cfg.Genesis.Alloc.Accounts))
+	assertar.Equal(big.NewInt(100), cfg.Genesis.Alloc.Accounts[sfc.ContractAddress].Balance)
+	assertar.Equal(1, len(spec.Genesis.Accounts))
+	// the same spec is the same network
+	cfg2, err := spec.Config()
+	assertar.NoError(err)
+	assertar.Equal(cfg.GenesisAtropos(), cfg2.GenesisAtropos())
+
+	h := spec.Hash()
+	spec.Dag.MaxParents++
+	assertar.NotEqual(h, spec.Hash())
+	spec.Dag.MaxParents--
+	assertar.Equal(h, spec.Hash())
+
+	for name, broken := range map[string]func(s *NetworkSpec){
+		"no network ID": func(s *NetworkSpec) { s.NetworkID = 0 },
+		"no time":       func(s *NetworkSpec) { s.Genesis.Time = 0 },
+		"no validators": func(s *NetworkSpec) { s.Genesis.Validators = nil },
+		"zero stake": func(s *NetworkSpec) {
+			s.Genesis.Validators = pos.GValidators{{ID: 1, Address: validator, Stake: big.NewInt(0)}}
+		},
+		"no admin":        func(s *NetworkSpec) { s.Genesis.SfcContractAdmin = common.Address{} },
+		"unknown SFC":     func(s *NetworkSpec) { s.Genesis.SfcContract = "unknown" },
+		"reserved SFC":    func(s *NetworkSpec) { s.Genesis.Accounts = genesis.Accounts{sfc.ContractAddress: {}} },
+		"unknown sealing": func(s *NetworkSpec) { s.Dag.EpochSealing.Policy = "unknown" },
+	} {
+		s := spec
+		broken(&s)
+		_, err := s.Config()
+		assertar.Error(err, name)
+	}
+}
+
+func TestNetworkSpecCanonicalHash(t *testing.T) {
+	assertar := assert.New(t)
+
+	type specV1 struct {
+		A uint64         `json:"a"`
+		B string         `json:"b"`
+		C common.Address `json:"c"`
+	}
+	// the next release adds the fields, and reorders the old ones
+	type specV2 struct {
+		C    common.Address `json:"c"`
+		New1 uint64         `json:"new1"`
+		A    uint64         `json:"a"`
+		New2 *big.Int       `json:"new2"`
+		New3 []uint64       `json:"new3"`
+		New4 struct {
+			Flag bool `json:"flag"`
+		} `json:"new4"`
+		New5 common.Hash `json:"new5"`
+		B    string      `json:"b"`
+	}
+
+	addr := common.HexToAddress("0x541E408443A592C38e01Bed0cB31f9De8c1322d0")
+	h1, err := canonicalHash(&specV1{A: 1, B: "b", C: addr})
+	assertar.NoError(err)
+	h2, err := canonicalHash(&specV2{A: 1, B: "b", C: addr})
+	assertar.NoError(err)
+	assertar.Equal(h1, h2, "new fields aren't set")
+
+	h2, err = canonicalHash(&specV2{A: 1, B: "b", C: addr, New1: 1})
+	assertar.NoError(err)
+	assertar.NotEqual(h1, h2, "new field is set")
+
+	h2, err = canonicalHash(&specV2{A: 1, B: "b", C: addr, New3: []uint64{0}})
+	assertar.NoError(err)
+	assertar.NotEqual(h1, h2, "new array isn't empty")
+
+	h2, err = canonicalHash(&specV1{A: 1, B: "b"})
+	assertar.NoError(err)
+	assertar.NotEqual(h1, h2, "old field is changed")
+}
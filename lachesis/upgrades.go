@@ -0,0 +1,109 @@
+package lachesis
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+
+	ethparams "github.com/ethereum/go-ethereum/params"
+
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+)
+
+// Upgrade changes the network rules from the epoch. Nil sections are left unchanged.
+// All the nodes of the network must have the same upgrades scheduled.
+type Upgrade struct {
+	Name  string    `json:"name"`
+	Epoch idx.Epoch `json:"epoch"`
+
+	Dag     *DagConfig     `json:"dag,omitempty" toml:",omitempty"`
+	Economy *EconomyConfig `json:"economy,omitempty" toml:",omitempty"`
+	Blocks  *BlocksConfig  `json:"blocks,omitempty" toml:",omitempty"`
+	// Evm replaces the EVM chain config, the ChainID is always the NetworkID.
+	// Forks are still activated by the block numbers, so a fork enabled from the epoch should have 0 block.
+	Evm *ethparams.ChainConfig `json:"evm,omitempty" toml:",omitempty"`
+	// LightClient switches the block app hash to the hash of the block result, which the light client proofs are built on.
+	// It alters PrevEpochHash, so it's applied only from the upgrade epoch, and it can't be switched off.
+	LightClient bool `json:"lightClient,omitempty"`
+}
+
+// LightClientUpgrade returns the upgrade which enables the light client proofs from the epoch.
+func LightClientUpgrade(epoch idx.Epoch) Upgrade {
+	return Upgrade{
+		Name:        "light-client",
+		Epoch:       epoch,
+		LightClient: true,
+	}
+}
+
+// Rules are the network rules which are active in an epoch.
+type Rules struct {
+	Dag     DagConfig
+	Economy EconomyConfig
+	Blocks  BlocksConfig
+	Evm     ethparams.ChainConfig
+	// LightClient is true if the block app hash is the hash of the block result
+	LightClient bool
+}
+
+// EvmChainConfig returns ChainConfig for transaction signing and execution
+func (r *Rules) EvmChainConfig() *ethparams.ChainConfig {
+	cfg := r.Evm
+	return &cfg
+}
+
+// Rules returns the network rules which are active in the epoch, i.e. the genesis rules
+// with the upgrades scheduled up to the epoch.
+func (c *Config) Rules(epoch idx.Epoch) Rules {
+	rules := Rules{
+		Dag:     c.Dag,
+		Economy: c.Economy,
+		Blocks:  c.Blocks,
+		Evm:     *ethparams.AllEthashProtocolChanges,
+	}
+	for _, u := range c.Upgrades {
+		if u.Epoch > epoch {
+			break
+		}
+		if u.Dag != nil {
+			rules.Dag = *u.Dag
+		}
+		if u.Economy != nil {
+			rules.Economy = *u.Economy
+		}
+		if u.Blocks != nil {
+			rules.Blocks = *u.Blocks
+		}
+		if u.Evm != nil {
+			rules.Evm = *u.Evm
+		}
+		if u.LightClient {
+			rules.LightClient = true
+		}
+	}
+	rules.Evm.ChainID = new(big.Int).SetUint64(c.NetworkID)
+	return rules
+}
+
+// ValidateUpgrades checks that the upgrades are ordered by epoch and may be applied.
+func (c *Config) ValidateUpgrades() error {
+	prev := idx.Epoch(0)
+	for i, u := range c.Upgrades {
+		if u.Epoch <= prev {
+			return fmt.Errorf("upgrade %d %q: epoch %d must be greater than %d", i, u.Name, u.Epoch, prev)
+		}
+		prev = u.Epoch
+
+		if u.Dag == nil {
+			continue
+		}
+		// the vector clock of the epoch is built with the genesis config
+		if !reflect.DeepEqual(u.Dag.VectorClockConfig, c.Dag.VectorClockConfig) {
+			return fmt.Errorf("upgrade %d %q: vector clock config can't be upgraded", i, u.Name)
+		}
+		if _, err := u.Dag.EpochSealer(); err != nil {
+			return fmt.Errorf("upgrade %d %q: %v", i, u.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package lachesis
+
+import (
+	"math/big"
+	"testing"
+
+	ethparams "github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+)
+
+func TestConfigRules(t *testing.T) {
+	assertar := assert.New(t)
+
+	net := TestNetConfig()
+
+	dag := net.Dag
+	dag.MaxParents = 5
+	economy := net.Economy
+	economy.BlockMissedLatency = 10
+	evm := *ethparams.AllEthashProtocolChanges
+	evm.IstanbulBlock = nil
+	net.Upgrades = []Upgrade{
+		{Name: "parents", Epoch: 5, Dag: &dag},
+		{Name: "economy", Epoch: 10, Economy: &economy, Evm: &evm},
+		LightClientUpgrade(11),
+	}
+	if !assertar.NoError(net.ValidateUpgrades()) {
+		return
+	}
+
+	for _, epoch := range []idx.Epoch{0, 1, 4} {
+		rules := net.Rules(epoch)
+		assertar.Equal(net.Dag, rules.Dag)
+		assertar.Equal(net.Economy, rules.Economy)
+		assertar.True(rules.EvmChainConfig().IsIstanbul(big.NewInt(0)))
+	}
+	for _, epoch := range []idx.Epoch{5, 9} {
+		rules := net.Rules(epoch)
+		assertar.Equal(5, rules.Dag.MaxParents)
+		assertar.Equal(net.Economy, rules.Economy)
+	}
+	rules := net.Rules(10)
+	assertar.Equal(5, rules.Dag.MaxParents)
+	assertar.Equal(idx.Block(10), rules.Economy.BlockMissedLatency)
+	assertar.False(rules.EvmChainConfig().IsIstanbul(big.NewInt(0)))
+	assertar.Equal(net.NetworkID, rules.EvmChainConfig().ChainID.Uint64())
+	assertar.False(rules.LightClient)
+	assertar.True(net.Rules(11).LightClient)
+	assertar.True(net.Rules(12).LightClient)
+	// the genesis chain config
+	assertar.True(net.EvmChainConfig().IsIstanbul(big.NewInt(0)))
+	assertar.Equal(net.NetworkID, net.EvmChainConfig().ChainID.Uint64())
+
+	// unordered
+	net.Upgrades[1].Epoch = 5
+	assertar.Error(net.ValidateUpgrades())
+	// from genesis
+	net.Upgrades[1].Epoch = 10
+	net.Upgrades[0].Epoch = 0
+	assertar.Error(net.ValidateUpgrades())
+	net.Upgrades[0].Epoch = 5
+	// vector clock can't be changed
+	dag.VectorClockConfig.EvictCheaters = !dag.VectorClockConfig.EvictCheaters
+	assertar.Error(net.ValidateUpgrades())
+	dag.VectorClockConfig.EvictCheaters = !dag.VectorClockConfig.EvictCheaters
+	// epoch sealing policy
+	dag.EpochSealing.Policy = "unknown"
+	assertar.Error(net.ValidateUpgrades())
+}
@@ -0,0 +1,60 @@
+// Package light is a light client library. It follows the validators of the epochs from the genesis,
+// and verifies the EVM state and the receipts of the blocks without the full DAG.
+package light
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+	"golang.org/x/crypto/sha3"
+
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/inter/pos"
+)
+
+// BlockResult is the outcome of the block execution. Its hash is the app hash of the block.
+// The app hashes are chained into the epoch state, which is signed by the validators
+// with the first events of the next epoch.
+type BlockResult struct {
+	Atropos      hash.Event
+	TxHash       common.Hash
+	Root         common.Hash
+	ReceiptsRoot common.Hash
+	// NextValidators is the ValidatorsHash of the next epoch if the block seals the epoch, zero otherwise.
+	NextValidators common.Hash
+}
+
+// Hash returns the app hash of the block.
+func (r *BlockResult) Hash() common.Hash {
+	return rlpHash(r)
+}
+
+type validator struct {
+	ID      idx.StakerID
+	Stake   pos.Stake
+	Address common.Address
+}
+
+// ValidatorsHash returns the hash of the validators and their addresses in the order of SortedIDs.
+func ValidatorsHash(validators *pos.Validators, addresses []common.Address) common.Hash {
+	list := make([]validator, 0, validators.Len())
+	for i, id := range validators.SortedIDs() {
+		v := validator{
+			ID:    id,
+			Stake: validators.Get(id),
+		}
+		if i < len(addresses) {
+			v.Address = addresses[i]
+		}
+		list = append(list, v)
+	}
+	return rlpHash(list)
+}
+
+func rlpHash(x interface{}) common.Hash {
+	hasher := sha3.NewLegacyKeccak256()
+	if err := rlp.Encode(hasher, x); err != nil {
+		panic(err)
+	}
+	return hash.FromBytes(hasher.Sum(nil))
+}
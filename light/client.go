@@ -0,0 +1,170 @@
+package light
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+)
+
+var (
+	ErrUnknownBlock = errors.New("block isn't verified, the proof of its epoch isn't applied")
+	ErrNotFound     = errors.New("value isn't found by the proof")
+)
+
+// Client follows the validators of the epochs from the trusted checkpoint,
+// and verifies the state and the receipts of the blocks of the applied epochs.
+type Client struct {
+	checkpoint Checkpoint
+	blocks     map[idx.Block]BlockResult
+
+	mu sync.RWMutex
+}
+
+// NewClient creates the light client, which trusts the checkpoint, e.g. the GenesisCheckpoint.
+func NewClient(trusted *Checkpoint) *Client {
+	return &Client{
+		checkpoint: *trusted,
+		blocks:     make(map[idx.Block]BlockResult),
+	}
+}
+
+// Checkpoint returns the current checkpoint, i.e. the epoch whose proof is expected next.
+func (c *Client) Checkpoint() Checkpoint {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.checkpoint
+}
+
+// ApplyEpoch verifies the proof of the current epoch and moves the client to the next epoch.
+// The blocks of the epoch become verified.
+func (c *Client) ApplyEpoch(p *EpochProof) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	next, err := p.Verify(&c.checkpoint)
+	if err != nil {
+		return err
+	}
+	for i, r := range p.Blocks {
+		c.blocks[c.checkpoint.LastBlock+idx.Block(i)+1] = r
+	}
+	c.checkpoint = *next
+	return nil
+}
+
+// Block returns the verified result of the block, or nil if the block isn't verified.
+func (c *Client) Block(n idx.Block) *BlockResult {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	r, ok := c.blocks[n]
+	if !ok {
+		return nil
+	}
+	return &r
+}
+
+// VerifyAccount verifies the account proof, as returned by eth_getProof, against the state root of the block.
+// Returns nil if the proof proves that the account doesn't exist.
+func (c *Client) VerifyAccount(n idx.Block, address common.Address, proof [][]byte) (*state.Account, error) {
+	r := c.Block(n)
+	if r == nil {
+		return nil, ErrUnknownBlock
+	}
+	raw, _, err := trie.VerifyProof(r.Root, crypto.Keccak256(address.Bytes()), proofDb(proof))
+	if err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		return nil, nil
+	}
+	account := &state.Account{}
+	if err := rlp.DecodeBytes(raw, account); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// VerifyStorage verifies the storage proof, as returned by eth_getProof, against the storage root of the account.
+func VerifyStorage(root common.Hash, key common.Hash, proof [][]byte) (common.Hash, error) {
+	raw, _, err := trie.VerifyProof(root, crypto.Keccak256(key.Bytes()), proofDb(proof))
+	if err != nil || raw == nil {
+		return common.Hash{}, err
+	}
+	_, content, _, err := rlp.Split(raw)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.BytesToHash(content), nil
+}
+
+// VerifyReceipt verifies the proof of the receipt by its index in the block, see ReceiptProof.
+func (c *Client) VerifyReceipt(n idx.Block, index uint, proof [][]byte) (*types.Receipt, error) {
+	r := c.Block(n)
+	if r == nil {
+		return nil, ErrUnknownBlock
+	}
+	key, _ := rlp.EncodeToBytes(index)
+	raw, _, err := trie.VerifyProof(r.ReceiptsRoot, key, proofDb(proof))
+	if err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		return nil, ErrNotFound
+	}
+	receipt := &types.Receipt{}
+	if err := rlp.DecodeBytes(raw, receipt); err != nil {
+		return nil, err
+	}
+	return receipt, nil
+}
+
+// ReceiptProof returns the proof of the receipt by its index against the block's receipts root.
+func ReceiptProof(receipts types.Receipts, index uint) ([][]byte, error) {
+	if index >= uint(len(receipts)) {
+		return nil, fmt.Errorf("receipt %d isn't found, the block has %d receipts", index, len(receipts))
+	}
+	tr := new(trie.Trie)
+	for i := range receipts {
+		key, _ := rlp.EncodeToBytes(uint(i))
+		tr.Update(key, receipts.GetRlp(i))
+	}
+	key, _ := rlp.EncodeToBytes(index)
+	var proof proofList
+	if err := tr.Prove(key, 0, &proof); err != nil {
+		return nil, err
+	}
+	return proof, nil
+}
+
+// proofList collects the proof nodes in the order from the root.
+type proofList [][]byte
+
+func (n *proofList) Put(key []byte, value []byte) error {
+	*n = append(*n, value)
+	return nil
+}
+
+func (n *proofList) Delete(key []byte) error {
+	panic("not supported")
+}
+
+func proofDb(proof [][]byte) ethdb.KeyValueReader {
+	db := memorydb.New()
+	for _, node := range proof {
+		_ = db.Put(crypto.Keccak256(node), node)
+	}
+	return db
+}
@@ -0,0 +1,62 @@
+package light
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+)
+
+func TestClientVerifyReceipt(t *testing.T) {
+	assertar := assert.New(t)
+
+	receipts := make(types.Receipts, 200)
+	for i := range receipts {
+		receipts[i] = &types.Receipt{
+			Status:            types.ReceiptStatusSuccessful,
+			CumulativeGasUsed: uint64(i+1) * 21000,
+			Logs:              []*types.Log{},
+		}
+	}
+	receipts[150].Logs = []*types.Log{{
+		Address: common.BytesToAddress(hash.FakeHash(1).Bytes()),
+		Topics:  []common.Hash{hash.FakeHash(2)},
+		Data:    []byte{1, 2, 3},
+	}}
+
+	client := NewClient(&Checkpoint{})
+	client.blocks[5] = BlockResult{
+		ReceiptsRoot: types.DeriveSha(receipts),
+	}
+
+	for _, i := range []uint{0, 1, 127, 128, 150, 199} {
+		proof, err := ReceiptProof(receipts, i)
+		if !assertar.NoError(err) {
+			return
+		}
+		receipt, err := client.VerifyReceipt(5, i, proof)
+		if !assertar.NoError(err) {
+			return
+		}
+		assertar.Equal(receipts[i].CumulativeGasUsed, receipt.CumulativeGasUsed)
+		assertar.Equal(len(receipts[i].Logs), len(receipt.Logs))
+	}
+
+	proof, err := ReceiptProof(receipts, 1)
+	if !assertar.NoError(err) {
+		return
+	}
+	// proof of other receipt
+	_, err = client.VerifyReceipt(5, 2, proof)
+	assertar.Error(err)
+	// not verified block
+	_, err = client.VerifyReceipt(idx.Block(6), 1, proof)
+	assertar.Equal(ErrUnknownBlock, err)
+
+	_, err = ReceiptProof(receipts, 200)
+	assertar.Error(err)
+}
@@ -0,0 +1,139 @@
+package light
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/inter/pos"
+	"github.com/Fantom-foundation/go-lachesis/lachesis"
+	"github.com/Fantom-foundation/go-lachesis/poset"
+)
+
+var (
+	ErrMalformed  = errors.New("epoch proof is malformed")
+	ErrWrongEpoch = errors.New("epoch proof isn't of the checkpoint epoch")
+	ErrNoQuorum   = errors.New("epoch state isn't signed by more than 2/3 of the epoch stake")
+)
+
+// Checkpoint is the trusted state the light client follows: the validators of the epoch,
+// and the app hash after the last block of the previous epoch.
+type Checkpoint struct {
+	Epoch      idx.Epoch
+	Validators *pos.Validators
+	Addresses  []common.Address // in the order of Validators.SortedIDs()
+
+	LastBlock idx.Block
+	AppHash   common.Hash
+}
+
+// GenesisCheckpoint returns the checkpoint of the first epoch of the network.
+// The network must have the light client proofs enabled since the first epoch, see lachesis.LightClientUpgrade.
+func GenesisCheckpoint(net *lachesis.Config) *Checkpoint {
+	genesisAtropos := net.GenesisAtropos()
+	return &Checkpoint{
+		Epoch:      1,
+		Validators: net.Genesis.Alloc.Validators.Validators(),
+		Addresses:  sortedGenesisAddresses(net.Genesis.Alloc.Validators),
+		LastBlock:  0,
+		AppHash:    common.Hash(genesisAtropos),
+	}
+}
+
+func sortedGenesisAddresses(gv pos.GValidators) []common.Address {
+	byID := gv.Map()
+	validators := gv.Validators()
+	addresses := make([]common.Address, 0, validators.Len())
+	for _, id := range validators.SortedIDs() {
+		addresses = append(addresses, byID[id].Address)
+	}
+	return addresses
+}
+
+// EpochProof proves the sealing of the epoch and the validators of the next epoch.
+//
+// The results of the epoch blocks are chained into the app hash of the epoch state, and the
+// sealing block result commits to the next validators. The epoch state hash is the PrevEpochHash
+// of the first events of the next epoch, so the events prove it if their creators have more than 2/3
+// of the sealed epoch stake. It means that the proof is available when the most of the validators
+// keep validating in the next epoch.
+type EpochProof struct {
+	Epoch idx.Epoch
+	// Time is the consensus time of the sealing block.
+	Time inter.Timestamp
+	// Blocks are the results of all the epoch blocks, the last one seals the epoch.
+	Blocks []BlockResult
+
+	NextValidators *pos.Validators
+	NextAddresses  []common.Address // in the order of NextValidators.SortedIDs()
+
+	// Events are the headers of the first events of the next epoch, signed by the sealed epoch validators.
+	Events []*inter.EventHeader
+}
+
+// Verify checks the proof with the checkpoint of the proof epoch, and returns the checkpoint of the next epoch.
+func (p *EpochProof) Verify(cp *Checkpoint) (*Checkpoint, error) {
+	if p.Epoch != cp.Epoch {
+		return nil, ErrWrongEpoch
+	}
+	if len(p.Blocks) == 0 || p.NextValidators == nil || len(p.NextAddresses) != p.NextValidators.Len() {
+		return nil, ErrMalformed
+	}
+	if len(cp.Addresses) != cp.Validators.Len() {
+		return nil, fmt.Errorf("checkpoint is malformed")
+	}
+
+	appHash := cp.AppHash
+	for i, r := range p.Blocks {
+		if i < len(p.Blocks)-1 && r.NextValidators != (common.Hash{}) {
+			return nil, fmt.Errorf("%v: block %d seals the epoch before the last one", ErrMalformed, cp.LastBlock+idx.Block(i)+1)
+		}
+		appHash = hash.Of(appHash.Bytes(), r.Hash().Bytes())
+	}
+	sealing := p.Blocks[len(p.Blocks)-1]
+	if sealing.NextValidators != ValidatorsHash(p.NextValidators, p.NextAddresses) {
+		return nil, fmt.Errorf("%v: next validators don't match the sealing block", ErrMalformed)
+	}
+
+	state := poset.GenesisState{
+		Epoch:       p.Epoch,
+		Time:        p.Time,
+		LastAtropos: sealing.Atropos,
+		AppHash:     appHash,
+	}
+	stateHash := state.Hash()
+
+	addresses := make(map[idx.StakerID]common.Address, len(cp.Addresses))
+	for i, id := range cp.Validators.SortedIDs() {
+		addresses[id] = cp.Addresses[i]
+	}
+	signed := cp.Validators.NewCounter()
+	for _, e := range p.Events {
+		if e.Epoch != p.Epoch+1 || e.Seq != 1 || e.PrevEpochHash != stateHash {
+			return nil, fmt.Errorf("%v: event %s doesn't sign the epoch state", ErrMalformed, e.Hash().String())
+		}
+		address, ok := addresses[e.Creator]
+		if !ok {
+			return nil, fmt.Errorf("%v: creator of event %s isn't a validator", ErrMalformed, e.Hash().String())
+		}
+		if !e.VerifySignature(address) {
+			return nil, fmt.Errorf("%v: event %s has wrong signature", ErrMalformed, e.Hash().String())
+		}
+		signed.Count(e.Creator)
+	}
+	if !signed.HasQuorum() {
+		return nil, ErrNoQuorum
+	}
+
+	return &Checkpoint{
+		Epoch:      p.Epoch + 1,
+		Validators: p.NextValidators,
+		Addresses:  p.NextAddresses,
+		LastBlock:  cp.LastBlock + idx.Block(len(p.Blocks)),
+		AppHash:    appHash,
+	}, nil
+}
@@ -0,0 +1,144 @@
+package light
+
+import (
+	"crypto/ecdsa"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/crypto"
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/inter/pos"
+	"github.com/Fantom-foundation/go-lachesis/poset"
+)
+
+func TestEpochProofVerify(t *testing.T) {
+	assertar := assert.New(t)
+
+	keys := make(map[idx.StakerID]*ecdsa.PrivateKey)
+	validatorsOf := func(ids ...idx.StakerID) (*pos.Validators, []common.Address) {
+		validators := pos.EqualStakeValidators(ids, 1)
+		addresses := make([]common.Address, 0, len(ids))
+		for _, id := range validators.SortedIDs() {
+			if keys[id] == nil {
+				keys[id] = crypto.FakeKey(int(id))
+			}
+			addresses = append(addresses, ethcrypto.PubkeyToAddress(keys[id].PublicKey))
+		}
+		return validators, addresses
+	}
+	validators, addresses := validatorsOf(1, 2, 3, 4)
+	nextValidators, nextAddresses := validatorsOf(2, 3, 4, 5)
+
+	cp := &Checkpoint{
+		Epoch:      2,
+		Validators: validators,
+		Addresses:  addresses,
+		LastBlock:  10,
+		AppHash:    hash.FakeHash(1),
+	}
+
+	blocks := []BlockResult{
+		{Atropos: hash.FakeEvent(), Root: hash.FakeHash(2)},
+		{Atropos: hash.FakeEvent(), Root: hash.FakeHash(3)},
+		{Atropos: hash.FakeEvent(), Root: hash.FakeHash(4), NextValidators: ValidatorsHash(nextValidators, nextAddresses)},
+	}
+	appHash := cp.AppHash
+	for _, r := range blocks {
+		appHash = hash.Of(appHash.Bytes(), r.Hash().Bytes())
+	}
+	state := poset.GenesisState{
+		Epoch:       2,
+		Time:        100,
+		LastAtropos: blocks[2].Atropos,
+		AppHash:     appHash,
+	}
+
+	firstEvent := func(creator idx.StakerID, prevEpochHash common.Hash) *inter.EventHeader {
+		e := inter.NewEvent()
+		e.Epoch = 3
+		e.Seq = 1
+		e.Creator = creator
+		e.PrevEpochHash = prevEpochHash
+		if !assertar.NoError(e.SignBy(keys[creator])) {
+			t.FailNow()
+		}
+		return &e.EventHeader
+	}
+	newProof := func(signers ...idx.StakerID) *EpochProof {
+		p := &EpochProof{
+			Epoch:          2,
+			Time:           100,
+			Blocks:         append([]BlockResult{}, blocks...),
+			NextValidators: nextValidators,
+			NextAddresses:  nextAddresses,
+		}
+		for _, creator := range signers {
+			p.Events = append(p.Events, firstEvent(creator, state.Hash()))
+		}
+		return p
+	}
+
+	next, err := newProof(1, 2, 3).Verify(cp)
+	if !assertar.NoError(err) {
+		return
+	}
+	assertar.Equal(idx.Epoch(3), next.Epoch)
+	assertar.Equal(idx.Block(13), next.LastBlock)
+	assertar.Equal(appHash, next.AppHash)
+	assertar.Equal(nextValidators, next.Validators)
+	assertar.Equal(nextAddresses, next.Addresses)
+
+	// the new validator 5 isn't counted, it doesn't validate the epoch
+	_, err = newProof(2, 3, 5).Verify(cp)
+	assertar.Error(err)
+	_, err = newProof(1, 2, 2).Verify(cp)
+	assertar.Equal(ErrNoQuorum, err)
+
+	// other epoch
+	p := newProof(1, 2, 3)
+	p.Epoch = 3
+	_, err = p.Verify(cp)
+	assertar.Equal(ErrWrongEpoch, err)
+
+	// other state
+	p = newProof(1, 2, 3)
+	p.Blocks[1].Root = hash.FakeHash(5)
+	_, err = p.Verify(cp)
+	assertar.Error(err)
+	p = newProof(1, 2, 3)
+	p.Time++
+	_, err = p.Verify(cp)
+	assertar.Error(err)
+
+	// other next validators
+	p = newProof(1, 2, 3)
+	p.NextAddresses = addresses
+	_, err = p.Verify(cp)
+	assertar.Error(err)
+	p = newProof(1, 2, 3)
+	p.NextAddresses = nextAddresses[:3]
+	_, err = p.Verify(cp)
+	assertar.Equal(ErrMalformed, err)
+
+	// wrong signature
+	p = newProof(1, 2, 3)
+	p.Events[0] = &inter.EventHeader{EventHeaderData: p.Events[0].EventHeaderData, Sig: p.Events[1].Sig}
+	_, err = p.Verify(cp)
+	assertar.Error(err)
+
+	// the client moves to the next epoch, and the epoch blocks become verified
+	client := NewClient(cp)
+	assertar.Error(client.ApplyEpoch(newProof(1, 2)))
+	assertar.Equal(*cp, client.Checkpoint())
+	assertar.NoError(client.ApplyEpoch(newProof(1, 2, 3)))
+	assertar.Equal(*next, client.Checkpoint())
+	assertar.Nil(client.Block(10))
+	assertar.Equal(blocks[0], *client.Block(11))
+	assertar.Equal(blocks[2], *client.Block(13))
+	assertar.Nil(client.Block(14))
+}
@@ -18,10 +18,12 @@ type (
 		// election state
 		decidedRoots map[idx.StakerID]voteValue // decided roots at "frameToDecide"
 		votes        map[voteID]voteValue
+		voters       []RootAndSlot // roots which voted, in order of processing
 
 		// external world
 		observe       ForklessCauseFn
 		getFrameRoots GetFrameRootsFn
+		newCounter    NewCounterFn
 
 		logger.Instance
 	}
@@ -30,6 +32,8 @@ type (
 	ForklessCauseFn func(a hash.Event, b hash.Event) bool
 	// GetFrameRootsFn returns all the roots in the specified frame
 	GetFrameRootsFn func(f idx.Frame) []RootAndSlot
+	// NewCounterFn returns the stake counter of votes from the point of view of the root
+	NewCounterFn func(root hash.Event) *pos.StakeCounter
 
 	// Slot specifies a root slot {addr, frame}. Normal validators can have only one root with this pair.
 	// Due to a fork, different roots may occupy the same slot
@@ -53,6 +57,7 @@ type voteValue struct {
 	decided      bool
 	yes          bool
 	observedRoot hash.Event
+	round        idx.Frame
 }
 
 // Res defines the final election result, i.e. decided frame
@@ -67,10 +72,12 @@ func New(
 	frameToDecide idx.Frame,
 	forklessCauseFn ForklessCauseFn,
 	getFrameRoots GetFrameRootsFn,
+	newCounter NewCounterFn,
 ) *Election {
 	el := &Election{
 		observe:       forklessCauseFn,
 		getFrameRoots: getFrameRoots,
+		newCounter:    newCounter,
 
 		Instance: logger.MakeInstance(),
 	}
@@ -86,6 +93,7 @@ func (el *Election) Reset(validators *pos.Validators, frameToDecide idx.Frame) {
 	el.frameToDecide = frameToDecide
 	el.votes = make(map[voteID]voteValue)
 	el.decidedRoots = make(map[idx.StakerID]voteValue)
+	el.voters = nil
 }
 
 // return root slots which are not within el.decidedRoots
@@ -34,8 +34,11 @@ func (el *Election) ProcessRoot(newRoot RootAndSlot) (*Res, error) {
 		observedRoots = el.observedRoots(newRoot.ID, newRoot.Slot.Frame-1)
 	}
 
+	el.voters = append(el.voters, newRoot)
 	for _, validatorSubject := range notDecidedRoots {
-		vote := voteValue{}
+		vote := voteValue{
+			round: round,
+		}
 
 		if round == 1 {
 			// in initial round, vote "yes" if observe the subject
@@ -47,9 +50,9 @@ func (el *Election) ProcessRoot(newRoot RootAndSlot) (*Res, error) {
 			}
 		} else {
 			var (
-				yesVotes = el.validators.NewCounter()
-				noVotes  = el.validators.NewCounter()
-				allVotes = el.validators.NewCounter()
+				yesVotes = el.newCounter(newRoot.ID)
+				noVotes  = el.newCounter(newRoot.ID)
+				allVotes = el.newCounter(newRoot.ID)
 			)
 
 			// calc number of "yes" and "no", weighted by validator's stake
@@ -263,7 +263,11 @@ func testProcessRoot(
 	}
 	ordered = unordered.ByParents()
 
-	election := New(validators, 0, forklessCauseFn, getFrameRootsFn)
+	newCounterFn := func(hash.Event) *pos.StakeCounter {
+		return validators.NewCounter()
+	}
+
+	election := New(validators, 0, forklessCauseFn, getFrameRootsFn, newCounterFn)
 
 	// processing:
 	var alreadyDecided bool
@@ -0,0 +1,87 @@
+package election
+
+import (
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+)
+
+type (
+	// Record is the trace of a decided election, which explains how the Atropos was chosen.
+	Record struct {
+		Frame   idx.Frame
+		Atropos hash.Event
+
+		// Candidates are the roots of the decided frame.
+		Candidates []RootAndSlot
+		// Voters are the roots of the next frames which voted, in order of processing.
+		Voters []RootAndSlot
+
+		Votes     []VoteRecord
+		Decisions []DecisionRecord
+	}
+
+	// VoteRecord is a vote of the root for the validator's slot of the decided frame.
+	VoteRecord struct {
+		Voter        hash.Event
+		Subject      idx.StakerID
+		Yes          bool
+		Decided      bool
+		ObservedRoot hash.Event
+	}
+
+	// DecisionRecord is the final vote for the validator's slot.
+	// Round is the distance from the decided frame to the frame of the deciding root.
+	DecisionRecord struct {
+		Subject idx.StakerID
+		Round   idx.Frame
+		Yes     bool
+		Root    hash.Event
+	}
+)
+
+// Record returns the trace of the decided election.
+// Must be called before Reset.
+func (el *Election) Record(res *Res) *Record {
+	r := &Record{
+		Frame:      res.Frame,
+		Atropos:    res.Atropos,
+		Candidates: el.getFrameRoots(el.frameToDecide),
+		Voters:     el.voters,
+		Votes:      make([]VoteRecord, 0, len(el.votes)),
+		Decisions:  make([]DecisionRecord, 0, len(el.decidedRoots)),
+	}
+
+	for _, voter := range el.voters {
+		for _, subject := range el.validators.SortedIDs() {
+			vote, ok := el.votes[voteID{
+				fromRoot:     voter.ID,
+				forValidator: subject,
+			}]
+			if !ok {
+				continue // the subject was already decided
+			}
+			r.Votes = append(r.Votes, VoteRecord{
+				Voter:        voter.ID,
+				Subject:      subject,
+				Yes:          vote.yes,
+				Decided:      vote.decided,
+				ObservedRoot: vote.observedRoot,
+			})
+		}
+	}
+
+	for _, subject := range el.validators.SortedIDs() {
+		vote, ok := el.decidedRoots[subject]
+		if !ok {
+			continue // the Atropos is chosen before all the slots are decided
+		}
+		r.Decisions = append(r.Decisions, DecisionRecord{
+			Subject: subject,
+			Round:   vote.round,
+			Yes:     vote.yes,
+			Root:    vote.observedRoot,
+		})
+	}
+
+	return r
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/Fantom-foundation/go-lachesis/hash"
 	"github.com/Fantom-foundation/go-lachesis/inter"
 	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/poset/election"
 )
 
 func (p *Poset) confirmEvents(frame idx.Frame, atropos hash.Event, onEventConfirmed func(*inter.EventHeaderData)) {
@@ -95,6 +96,12 @@ func (p *Poset) confirmBlock(frame idx.Frame, atropos hash.Event) (block *inter.
 func (p *Poset) onFrameDecided(frame idx.Frame, atropos hash.Event) bool {
 	p.Log.Debug("consensus: event is atropos", "event", atropos.String())
 
+	if p.electionsIndex {
+		p.store.SetElection(p.EpochN, p.election.Record(&election.Res{
+			Frame:   frame,
+			Atropos: atropos,
+		}))
+	}
 	p.election.Reset(p.Validators, frame+1)
 	p.Checkpoint.LastDecidedFrame = frame
 
@@ -135,6 +142,10 @@ func (p *Poset) sealEpoch() {
 	// commit
 	p.store.SetEpoch(&p.EpochState)
 	p.saveCheckpoint()
+	p.store.SetSnapshot(&Snapshot{
+		Checkpoint: *p.Checkpoint,
+		Epoch:      p.EpochState,
+	})
 
 	// reset internal epoch DB
 	p.store.RecreateEpochDb(p.EpochN)
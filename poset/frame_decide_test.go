@@ -10,6 +10,7 @@ import (
 	"github.com/Fantom-foundation/go-lachesis/inter"
 	"github.com/Fantom-foundation/go-lachesis/inter/idx"
 	"github.com/Fantom-foundation/go-lachesis/logger"
+	"github.com/Fantom-foundation/go-lachesis/poset/election"
 )
 
 func TestConfirmBlockEvents(t *testing.T) {
@@ -75,3 +76,69 @@ func TestConfirmBlockEvents(t *testing.T) {
 		}
 	}
 }
+
+func TestElectionsIndex(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	nodes := inter.GenNodes(5)
+	poset, _, input := FakePoset("", nodes)
+	poset.SetElectionsIndex(true)
+
+	var frames []idx.Frame
+	applyBlock := poset.callback.ApplyBlock
+	poset.callback.ApplyBlock = func(block *inter.Block, decidedFrame idx.Frame, cheaters inter.Cheaters) (common.Hash, bool) {
+		frames = append(frames, decidedFrame)
+		return applyBlock(block, decidedFrame, cheaters)
+	}
+
+	_ = inter.ForEachRandEvent(nodes, int(poset.dag.MaxEpochBlocks)-1, 5, nil, inter.ForEachEvent{
+		Process: func(e *inter.Event, name string) {
+			input.SetEvent(e)
+			assertar.NoError(
+				poset.ProcessEvent(e))
+			assertar.NoError(
+				flushDb(poset, e.Hash()))
+		},
+		Build: func(e *inter.Event, name string) *inter.Event {
+			e.Epoch = idx.Epoch(1)
+			return poset.Prepare(e)
+		},
+	})
+	if !assertar.NotEmpty(frames) {
+		return
+	}
+
+	for i, frame := range frames {
+		block := poset.blocks[idx.Block(i+1)]
+		record := poset.GetElection(1, frame)
+		if !assertar.NotNil(record, frame) {
+			return
+		}
+		assertar.Equal(frame, record.Frame)
+		assertar.Equal(block.Atropos, record.Atropos)
+
+		candidates := make(map[idx.StakerID]bool)
+		for _, root := range record.Candidates {
+			assertar.Equal(frame, root.Slot.Frame)
+			candidates[root.Slot.Validator] = true
+		}
+		for _, voter := range record.Voters {
+			assertar.True(voter.Slot.Frame > frame)
+		}
+		assertar.NotEmpty(record.Votes)
+
+		// the Atropos is the first decided "yes" slot, in order of validators
+		var chosen *election.DecisionRecord
+		for j, decision := range record.Decisions {
+			assertar.True(decision.Round > 0)
+			if decision.Yes && chosen == nil {
+				chosen = &record.Decisions[j]
+			}
+		}
+		if assertar.NotNil(chosen) {
+			assertar.Equal(record.Atropos, chosen.Root)
+			assertar.True(candidates[chosen.Subject])
+		}
+	}
+}
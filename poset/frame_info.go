@@ -54,6 +54,11 @@ func (f *FrameInfo) CalcConsensusTime(lamport idx.Lamport) inter.Timestamp {
 	return inter.Timestamp(int64(lamport)*int64(f.TimeRatio) + f.TimeOffset)
 }
 
+// GetEventConfirmedOn returns the frame which confirmed the event, or 0 if it isn't confirmed.
+func (p *Poset) GetEventConfirmedOn(id hash.Event) idx.Frame {
+	return p.store.GetEventConfirmedOn(id)
+}
+
 // GetConsensusTime calc consensus timestamp for given event, if event is confirmed.
 func (p *Poset) GetConsensusTime(id hash.Event) (inter.Timestamp, error) {
 	f := p.store.GetEventConfirmedOn(id)
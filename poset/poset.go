@@ -36,6 +36,8 @@ type Poset struct {
 
 	callback inter.ConsensusCallbacks
 
+	electionsIndex bool
+
 	epochMu utils.SpinLock // protects p.Validators and p.EpochN
 
 	logger.Instance
@@ -55,6 +57,16 @@ func New(dag lachesis.DagConfig, store *Store, input EventSource) *Poset {
 	return p
 }
 
+// SetElectionsIndex enables or disables storing the records of decided elections.
+func (p *Poset) SetElectionsIndex(enabled bool) {
+	p.electionsIndex = enabled
+}
+
+// GetElection returns the record of the decided election, if the elections index was enabled.
+func (p *Poset) GetElection(epoch idx.Epoch, frame idx.Frame) *election.Record {
+	return p.store.GetElection(epoch, frame)
+}
+
 // GetVectorIndex returns vector clock.
 func (p *Poset) GetVectorIndex() *vector.Index {
 	return p.vecClock
@@ -216,7 +228,7 @@ func (p *Poset) ProcessEvent(e *inter.Event) (err error) {
 
 // forklessCausedByQuorumOn returns true if event is forkless caused by 2/3W roots on specified frame
 func (p *Poset) forklessCausedByQuorumOn(e *inter.Event, f idx.Frame) bool {
-	observedCounter := p.Validators.NewCounter()
+	observedCounter := p.vecClock.NewQuorumCounter(e.Hash())
 	// check "observing" prev roots only if called by creator, or if creator has marked that event as root
 	for _, it := range p.store.GetFrameRoots(f) {
 		if p.vecClock.ForklessCause(e.Hash(), it.ID) {
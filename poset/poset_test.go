@@ -116,3 +116,97 @@ func compareResults(t *testing.T, posets []*ExtendedPoset) {
 		}
 	}
 }
+
+// TestPosetEvictCheaters checks that the consensus goes on after a fork is observed,
+// even if the honest online validators have less than 2/3W without the cheater.
+func TestPosetEvictCheaters(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	const posetCount = 3
+	nodes := inter.GenNodes(5)
+	cheater, offline := nodes[0], nodes[4]
+
+	posets := make([]*ExtendedPoset, 0, posetCount)
+	inputs := make([]*EventStore, 0, posetCount)
+	for i := 0; i < posetCount; i++ {
+		poset, store, input := FakePoset("", nodes)
+		n := i % len(nodes)
+		poset.SetName(hash.GetNodeName(nodes[n]))
+		store.SetName(hash.GetNodeName(nodes[n]))
+		posets = append(posets, poset)
+		inputs = append(inputs, input)
+	}
+	assertar.True(posets[0].dag.VectorClockConfig.EvictCheaters)
+
+	// create events on poset0
+	var (
+		ordered     inter.Events
+		processed   = map[hash.Event]bool{}
+		forkBlock   idx.Block
+		cheaterSeen bool
+	)
+	inter.ForEachRandFork(nodes, []idx.StakerID{cheater}, 100, 3, 1, nil, inter.ForEachEvent{
+		Process: func(e *inter.Event, name string) {
+			if processed[e.Hash()] {
+				return
+			}
+			processed[e.Hash()] = true
+			ordered = append(ordered, e)
+
+			inputs[0].SetEvent(e)
+			assertar.NoError(
+				posets[0].ProcessEvent(e))
+			assertar.NoError(
+				flushDb(posets[0], e.Hash()))
+
+			if !cheaterSeen && len(posets[0].vecClock.ObservedCheaters(e.Hash())) != 0 {
+				cheaterSeen = true
+				forkBlock = posets[0].LastBlockN
+			}
+		},
+		Build: func(e *inter.Event, name string) *inter.Event {
+			if e.Creator == offline {
+				return nil
+			}
+			// don't link to known cheaters, like the emitter does
+			parents := posets[0].vecClock.NoCheaters(e.SelfParent(), e.Parents)
+			if e.SelfParent() != nil && (len(parents) == 0 || parents[0] != *e.SelfParent()) {
+				return nil // cheater observes its own fork
+			}
+			e.Parents = hash.Events{}
+			e.Lamport = 1
+			for _, p := range parents {
+				if !inputs[0].HasEvent(p) {
+					continue // parent of offline validator
+				}
+				e.Parents.Add(p)
+				if parent := inputs[0].GetEvent(p); e.Lamport <= parent.Lamport {
+					e.Lamport = parent.Lamport + 1
+				}
+			}
+			e.Epoch = 1
+			return posets[0].Prepare(e)
+		},
+	})
+	if !assertar.True(cheaterSeen) {
+		return
+	}
+	// without the eviction, 3 of 5 honest validators cannot have a quorum after the fork is observed
+	assertar.Greater(int(posets[0].LastBlockN), int(forkBlock)+5)
+
+	for i := 1; i < len(posets); i++ {
+		ee := reorder(ordered)
+		for _, e := range ee {
+			inputs[i].SetEvent(e)
+			assertar.NoError(
+				posets[i].ProcessEvent(e))
+			assertar.NoError(
+				flushDb(posets[i], e.Hash()))
+		}
+	}
+
+	t.Run("Check consensus", func(t *testing.T) {
+		compareResults(t, posets)
+	})
+}
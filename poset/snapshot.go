@@ -0,0 +1,60 @@
+package poset
+
+import (
+	"fmt"
+
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+)
+
+// Snapshot is a poset state at the beginning of an epoch.
+type Snapshot struct {
+	Checkpoint Checkpoint
+	Epoch      EpochState
+}
+
+// GetSnapshot returns the poset state at the beginning of the epoch, if it was sealed by this node.
+func (p *Poset) GetSnapshot(epoch idx.Epoch) *Snapshot {
+	return p.store.GetSnapshot(epoch)
+}
+
+// PruneSnapshots deletes the poset states at the beginning of the epochs before the given one.
+func (p *Poset) PruneSnapshots(before idx.Epoch) {
+	p.store.PruneSnapshots(before)
+}
+
+// GetLastState returns the last stored poset state.
+// Unlike the embedded Checkpoint and EpochState, it's available before Bootstrap.
+func (p *Poset) GetLastState() *Snapshot {
+	return &Snapshot{
+		Checkpoint: *p.store.GetCheckpoint(),
+		Epoch:      *p.store.GetEpoch(),
+	}
+}
+
+// ApplySnapshot writes the poset state at the beginning of an epoch.
+// Genesis must be already applied and the poset mustn't be bootstrapped yet.
+func (p *Poset) ApplySnapshot(snap *Snapshot) error {
+	if p.Checkpoint != nil {
+		return fmt.Errorf("poset is already bootstrapped")
+	}
+	return p.store.ApplySnapshot(snap)
+}
+
+// ApplySnapshot writes the poset state at the beginning of an epoch.
+func (s *Store) ApplySnapshot(snap *Snapshot) error {
+	if s.GetGenesis() == nil {
+		return fmt.Errorf("apply genesis for store first")
+	}
+	if snap.Checkpoint.LastDecidedFrame != 0 {
+		return fmt.Errorf("snapshot isn't at the beginning of an epoch")
+	}
+	if snap.Epoch.EpochN <= s.GetEpoch().EpochN {
+		return fmt.Errorf("snapshot epoch %d isn't ahead of the current one", snap.Epoch.EpochN)
+	}
+
+	s.SetEpoch(&snap.Epoch)
+	s.SetCheckpoint(&snap.Checkpoint)
+	s.SetSnapshot(snap)
+
+	return nil
+}
@@ -26,6 +26,8 @@ type Store struct {
 		Epochs         kvdb.KeyValueStore `table:"e"`
 		ConfirmedEvent kvdb.KeyValueStore `table:"C"`
 		FrameInfos     kvdb.KeyValueStore `table:"f"`
+		Snapshots      kvdb.KeyValueStore `table:"s"`
+		Elections      kvdb.KeyValueStore `table:"E"`
 	}
 
 	cache struct {
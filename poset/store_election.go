@@ -0,0 +1,25 @@
+package poset
+
+import (
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/poset/election"
+)
+
+// SetElection stores the record of decided election (off-chain data).
+func (s *Store) SetElection(e idx.Epoch, record *election.Record) {
+	key := append(e.Bytes(), record.Frame.Bytes()...)
+
+	s.set(s.table.Elections, key, record)
+}
+
+// GetElection returns the stored record of decided election.
+func (s *Store) GetElection(e idx.Epoch, f idx.Frame) *election.Record {
+	key := append(e.Bytes(), f.Bytes()...)
+
+	w, exists := s.get(s.table.Elections, key, &election.Record{}).(*election.Record)
+	if !exists {
+		return nil
+	}
+
+	return w
+}
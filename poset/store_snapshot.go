@@ -0,0 +1,26 @@
+package poset
+
+import (
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/kvdb"
+)
+
+// SetSnapshot stores the poset state at the beginning of an epoch.
+// Snapshot is seldom read; so no cache.
+func (s *Store) SetSnapshot(snap *Snapshot) {
+	s.set(s.table.Snapshots, snap.Epoch.EpochN.Bytes(), snap)
+}
+
+// GetSnapshot returns stored poset state at the beginning of an epoch.
+func (s *Store) GetSnapshot(epoch idx.Epoch) *Snapshot {
+	w, _ := s.get(s.table.Snapshots, epoch.Bytes(), &Snapshot{}).(*Snapshot)
+	return w
+}
+
+// PruneSnapshots deletes the stored poset states of the epochs before the given one.
+func (s *Store) PruneSnapshots(before idx.Epoch) {
+	// the keys are ordered by epoch, so the states are deleted by a range
+	if err := kvdb.DeleteRange(s.table.Snapshots, nil, before.Bytes()); err != nil {
+		s.Log.Crit("Failed to erase key range", "err", err)
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
 
 	"github.com/Fantom-foundation/go-lachesis/hash"
 	"github.com/Fantom-foundation/go-lachesis/inter"
@@ -20,6 +21,34 @@ import (
 	"github.com/Fantom-foundation/go-lachesis/logger"
 )
 
+func TestStorePruneSnapshots(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	store := NewMemStore()
+	defer store.Close()
+
+	for epoch := idx.Epoch(1); epoch <= 300; epoch++ {
+		store.SetSnapshot(&Snapshot{
+			Epoch: EpochState{EpochN: epoch},
+		})
+	}
+
+	store.PruneSnapshots(260)
+	for epoch := idx.Epoch(1); epoch < 260; epoch++ {
+		assertar.Nil(store.GetSnapshot(epoch), epoch)
+	}
+	for epoch := idx.Epoch(260); epoch <= 300; epoch++ {
+		if assertar.NotNil(store.GetSnapshot(epoch), epoch) {
+			assertar.Equal(epoch, store.GetSnapshot(epoch).Epoch.EpochN)
+		}
+	}
+
+	// nothing to prune
+	store.PruneSnapshots(260)
+	assertar.NotNil(store.GetSnapshot(260))
+}
+
 /*
  * bench:
  */
@@ -1,30 +1,34 @@
 package topicsdb
 
 import (
+	"bytes"
+
 	"github.com/ethereum/go-ethereum/common"
 
 	"github.com/Fantom-foundation/go-lachesis/common/bigendian"
 )
 
 const (
-	uint8Size  = 1
-	uint64Size = 8
-	hashSize   = common.HashLength
-
-	logrecKeySize = uint64Size + hashSize + uint64Size
-	topicKeySize  = hashSize + uint8Size + logrecKeySize
-	otherKeySize  = logrecKeySize + uint8Size
+	uint8Size   = 1
+	uint64Size  = 8
+	hashSize    = common.HashLength
+	addressSize = common.AddressLength
+
+	logrecKeySize  = uint64Size + uint64Size + uint64Size
+	topicKeySize   = hashSize + uint8Size + logrecKeySize
+	addressKeySize = addressSize + logrecKeySize
+	otherKeySize   = logrecKeySize + uint8Size
 )
 
 type (
-	// ID of log record
+	// ID of log record, IDs are ordered by block number, tx index and log index.
 	ID [logrecKeySize]byte
 )
 
-func NewID(block uint64, tx common.Hash, logIndex uint) (id ID) {
+func NewID(block uint64, txIndex uint, logIndex uint) (id ID) {
 	copy(id[:], uintToBytes(block))
-	copy(id[uint64Size:], tx.Bytes())
-	copy(id[uint64Size+hashSize:], uintToBytes(uint64(logIndex)))
+	copy(id[uint64Size:], uintToBytes(uint64(txIndex)))
+	copy(id[uint64Size+uint64Size:], uintToBytes(uint64(logIndex)))
 	return
 }
 
@@ -36,14 +40,19 @@ func (id *ID) BlockNumber() uint64 {
 	return bytesToUint((*id)[:uint64Size])
 }
 
-func (id *ID) TxHash() (tx common.Hash) {
-	copy(tx[:], (*id)[uint64Size:uint64Size+hashSize])
-	return
+func (id *ID) TxIndex() uint {
+	return uint(bytesToUint(
+		(*id)[uint64Size : uint64Size+uint64Size]))
 }
 
 func (id *ID) Index() uint {
 	return uint(bytesToUint(
-		(*id)[uint64Size+hashSize : uint64Size+hashSize+uint64Size]))
+		(*id)[uint64Size+uint64Size : uint64Size+uint64Size+uint64Size]))
+}
+
+// Less returns true if the id is ordered before the other one.
+func (id *ID) Less(other ID) bool {
+	return bytes.Compare((*id)[:], other[:]) < 0
 }
 
 func topicKey(topic common.Hash, pos uint8, logrec ID) []byte {
@@ -56,6 +65,15 @@ func topicKey(topic common.Hash, pos uint8, logrec ID) []byte {
 	return key
 }
 
+func addressKey(address common.Address, logrec ID) []byte {
+	key := make([]byte, 0, addressKeySize)
+
+	key = append(key, address.Bytes()...)
+	key = append(key, logrec.Bytes()...)
+
+	return key
+}
+
 func otherKey(logrec ID, pos uint8) []byte {
 	key := make([]byte, 0, otherKeySize)
 
@@ -86,6 +104,9 @@ func extractLogrecID(key []byte) (id ID) {
 	case topicKeySize:
 		copy(id[:], key[hashSize+uint8Size:])
 		return
+	case addressKeySize:
+		copy(id[:], key[addressSize:])
+		return
 	default:
 		panic("wrong key type")
 	}
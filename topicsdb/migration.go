@@ -0,0 +1,110 @@
+package topicsdb
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+
+	"github.com/Fantom-foundation/go-lachesis/kvdb"
+)
+
+// formerIDSize is the size of the log record ID of the former (blockN+TxHash+logIndex) layout.
+const formerIDSize = uint64Size + hashSize + uint64Size
+
+// MigrateFormerLayout moves up to limit log records of the former (blockN+TxHash+logIndex) layout into the current tables.
+// The former records don't keep the tx index, so it's resolved by txIndex.
+// The moved records are deleted, so the migration is continued by the next call.
+// Returns the number of the moved records, and true if there are no former records left.
+func (tt *Index) MigrateFormerLayout(txIndex func(block uint64, tx common.Hash) uint, limit int) (moved int, done bool, err error) {
+	recs, ids, err := tt.readFormer(limit)
+	if err != nil {
+		return
+	}
+
+	for _, rec := range recs {
+		rec.TxIndex = txIndex(rec.BlockNumber, rec.TxHash)
+	}
+	err = tt.Push(recs...)
+	if err != nil {
+		return
+	}
+	for _, id := range ids {
+		err = kvdb.DeleteRange(tt.former.Other, id, kvdb.UpperBound(id))
+		if err != nil {
+			return
+		}
+		err = tt.former.Logrec.Delete(id)
+		if err != nil {
+			return
+		}
+	}
+	moved = len(recs)
+
+	if len(ids) < limit {
+		// the former topics index is useless without the records
+		if !isEmpty(tt.former.Topic) {
+			err = kvdb.DeleteRange(tt.former.Topic, nil, nil)
+		}
+		done = err == nil
+	}
+	return
+}
+
+// readFormer returns up to limit log records of the former layout, along with their former IDs.
+func (tt *Index) readFormer(limit int) (recs []*types.Log, ids [][]byte, err error) {
+	it := tt.former.Logrec.NewIterator()
+	defer it.Release()
+
+	for len(ids) < limit && it.Next() {
+		id := common.CopyBytes(it.Key())
+		buf := it.Value()
+		if len(id) != formerIDSize || len(buf) < addressSize+hashSize {
+			// a broken record is dropped along with its topics
+			ids = append(ids, id)
+			continue
+		}
+
+		rec := &types.Log{
+			BlockNumber: bytesToUint(id[:uint64Size]),
+			TxHash:      common.BytesToHash(id[uint64Size : uint64Size+hashSize]),
+			Index:       uint(bytesToUint(id[uint64Size+hashSize:])),
+			Address:     common.BytesToAddress(buf[:addressSize]),
+			BlockHash:   common.BytesToHash(buf[addressSize : addressSize+hashSize]),
+			Data:        common.CopyBytes(buf[addressSize+hashSize:]),
+		}
+		rec.Topics, err = tt.readFormerTopics(id)
+		if err != nil {
+			return
+		}
+		recs = append(recs, rec)
+		ids = append(ids, id)
+	}
+	err = it.Error()
+	return
+}
+
+// readFormerTopics returns the topics of the former log record.
+func (tt *Index) readFormerTopics(id []byte) ([]common.Hash, error) {
+	it := tt.former.Other.NewIteratorWithPrefix(id)
+	defer it.Release()
+
+	var topics []common.Hash
+	for it.Next() {
+		if len(it.Key()) != formerIDSize+uint8Size {
+			continue
+		}
+		pos := int(bytesToPos(it.Key()[formerIDSize:]))
+		for len(topics) <= pos {
+			topics = append(topics, common.Hash{})
+		}
+		topics[pos] = common.BytesToHash(it.Value())
+	}
+	return topics, it.Error()
+}
+
+func isEmpty(db ethdb.Iteratee) bool {
+	it := db.NewIterator()
+	defer it.Release()
+
+	return !it.Next()
+}
@@ -0,0 +1,183 @@
+package topicsdb
+
+import (
+	"bytes"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+// query is a set of the log records search conditions.
+type query struct {
+	from, to  uint64
+	addresses []common.Address
+	topics    [][]common.Hash
+	limit     int
+}
+
+type (
+	// cursor iterates over the records with the key prefix (one condition alternative),
+	// starting from the from block and stopping after the to block.
+	cursor struct {
+		it     ethdb.Iterator
+		prefix []byte
+		to     uint64
+
+		id         ID
+		topicCount uint8
+		ok         bool
+	}
+
+	// condition is matched by a record if any of its alternatives is.
+	condition []*cursor
+)
+
+func newCursor(table ethdb.Iteratee, prefix []byte, from, to uint64) *cursor {
+	start := make([]byte, 0, len(prefix)+uint64Size)
+	start = append(start, prefix...)
+	start = append(start, uintToBytes(from)...)
+
+	c := &cursor{
+		it:     table.NewIteratorWithStart(start),
+		prefix: prefix,
+		to:     to,
+	}
+	c.next()
+	return c
+}
+
+// next moves the cursor to the next record. c.ok is false if the records are over.
+func (c *cursor) next() {
+	c.ok = c.it.Next() && bytes.HasPrefix(c.it.Key(), c.prefix)
+	if !c.ok {
+		return
+	}
+	c.id = extractLogrecID(c.it.Key())
+	if c.id.BlockNumber() > c.to {
+		c.ok = false
+		return
+	}
+	c.topicCount = bytesToPos(c.it.Value())
+}
+
+// seek moves the cursor to the first record which isn't ordered before the id.
+func (c *cursor) seek(id ID) {
+	for c.ok && c.id.Less(id) {
+		c.next()
+	}
+}
+
+// head returns the alternative with the least record, or nil if the records are over.
+func (cond condition) head() (head *cursor) {
+	for _, c := range cond {
+		if c.ok && (head == nil || c.id.Less(head.id)) {
+			head = c
+		}
+	}
+	return
+}
+
+// seek moves the alternatives to the first records which aren't ordered before the id.
+func (cond condition) seek(id ID) {
+	for _, c := range cond {
+		c.seek(id)
+	}
+}
+
+// skip moves the alternatives, which are at the id, to the next records.
+func (cond condition) skip(id ID) {
+	for _, c := range cond {
+		if c.ok && c.id == id {
+			c.next()
+		}
+	}
+}
+
+// conditions returns a cursor for each alternative of the addresses and the topics conditions.
+// Topic conditions without alternatives are wildcards, they aren't returned.
+func (q *query) conditions(tt *Index) (conds []condition) {
+	if len(q.addresses) > 0 {
+		cond := make(condition, 0, len(q.addresses))
+		for _, address := range q.addresses {
+			cond = append(cond, newCursor(tt.table.Address, address.Bytes(), q.from, q.to))
+		}
+		conds = append(conds, cond)
+	}
+	for pos, alternatives := range q.topics {
+		if len(alternatives) < 1 {
+			continue
+		}
+		cond := make(condition, 0, len(alternatives))
+		for _, topic := range alternatives {
+			prefix := make([]byte, 0, hashSize+uint8Size)
+			prefix = append(prefix, topic.Bytes()...)
+			prefix = append(prefix, posToBytes(uint8(pos))...)
+			cond = append(cond, newCursor(tt.table.Topic, prefix, q.from, q.to))
+		}
+		conds = append(conds, cond)
+	}
+	return
+}
+
+// scan calls onMatch for the records which match all the conditions, in the ID order,
+// until onMatch returns false. It doesn't iterate over the rest of records then.
+func (q *query) scan(tt *Index, onMatch func(id ID, topicCount uint8) bool) (err error) {
+	conds := q.conditions(tt)
+	defer func() {
+		for _, cond := range conds {
+			for _, c := range cond {
+				if err == nil {
+					err = c.it.Error()
+				}
+				c.it.Release()
+			}
+		}
+	}()
+	if len(conds) == 0 {
+		return
+	}
+
+	for {
+		// no record before the greatest head can match all the conditions
+		var target ID
+		for i, cond := range conds {
+			head := cond.head()
+			if head == nil {
+				return
+			}
+			if i == 0 || target.Less(head.id) {
+				target = head.id
+			}
+		}
+
+		matched := true
+		var topicCount uint8
+		for _, cond := range conds {
+			cond.seek(target)
+			head := cond.head()
+			if head == nil {
+				return
+			}
+			if head.id != target {
+				matched = false
+				break
+			}
+			topicCount = head.topicCount
+		}
+		if !matched {
+			continue
+		}
+
+		if !onMatch(target, topicCount) {
+			return
+		}
+		for _, cond := range conds {
+			cond.skip(target)
+		}
+	}
+}
+
+// isLimitReached returns true if no more records are needed.
+func (q *query) isLimitReached(count int) bool {
+	return q.limit > 0 && count >= q.limit
+}
@@ -12,24 +12,21 @@ type (
 		types.Log
 
 		ID          ID
-		conditions  uint8
 		topicsCount uint8
 
-		ok    chan struct{}
 		ready chan error
 	}
 )
 
-func newLogrecBuilder(logrec ID, conditions uint8, topicCount uint8) *logrecBuilder {
+func newLogrecBuilder(logrec ID, topicCount uint8) *logrecBuilder {
 	rec := &logrecBuilder{
 		Log: types.Log{
 			BlockNumber: logrec.BlockNumber(),
-			TxHash:      logrec.TxHash(),
+			TxIndex:     logrec.TxIndex(),
 			Index:       logrec.Index(),
 			Topics:      make([]common.Hash, topicCount),
 		},
 		ID:          logrec,
-		conditions:  conditions,
 		topicsCount: topicCount,
 	}
 
@@ -38,9 +35,9 @@ func newLogrecBuilder(logrec ID, conditions uint8, topicCount uint8) *logrecBuil
 
 func (rec *logrecBuilder) Build() (r *types.Log, err error) {
 	if rec.ready != nil {
-		var complete bool
-		err, complete = <-rec.ready
-		if !complete {
+		err = <-rec.ready
+		rec.ready = nil
+		if err != nil {
 			return
 		}
 	}
@@ -49,23 +46,6 @@ func (rec *logrecBuilder) Build() (r *types.Log, err error) {
 	return
 }
 
-// MatchedWith count of conditions.
-func (rec *logrecBuilder) MatchedWith(count uint8) {
-	if rec.conditions > count {
-		rec.conditions -= count
-		return
-	}
-	rec.conditions = 0
-	if rec.ok != nil {
-		rec.ok <- struct{}{}
-	}
-}
-
-// IsMatched if it is matched with the all conditions.
-func (rec *logrecBuilder) IsMatched() bool {
-	return rec.conditions == 0
-}
-
 // SetOtherTopic appends topic.
 func (rec *logrecBuilder) SetOtherTopic(pos uint8, topic common.Hash) {
 	if pos >= rec.topicsCount {
@@ -87,6 +67,7 @@ func (rec *logrecBuilder) Fetch(
 ) (err error) {
 	// others
 	it := othersTable.NewIteratorWithPrefix(rec.ID.Bytes())
+	defer it.Release()
 	for it.Next() {
 		pos := extractTopicPos(it.Key())
 		topic := common.BytesToHash(it.Value())
@@ -98,14 +79,14 @@ func (rec *logrecBuilder) Fetch(
 		return
 	}
 
-	it.Release()
-
 	// fields
 	buf, err := logrecTable.Get(rec.ID.Bytes())
 	if err != nil {
 		return
 	}
 	offset := 0
+	rec.TxHash = common.BytesToHash(buf[offset : offset+common.HashLength])
+	offset += common.HashLength
 	rec.Address = common.BytesToAddress(buf[offset : offset+common.AddressLength])
 	offset += common.AddressLength
 	rec.BlockHash = common.BytesToHash(buf[offset : offset+common.HashLength])
@@ -2,6 +2,7 @@ package topicsdb
 
 import (
 	"fmt"
+	"math"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -16,17 +17,27 @@ var ErrTooManyTopics = fmt.Errorf("Too many topics")
 
 // Index is a specialized indexes for log records storing and fetching.
 type Index struct {
-	db    kvdb.KeyValueStore
+	db kvdb.KeyValueStore
+
+	// The records are keyed by (blockN+txIndex+logIndex), so the search results are ordered by it.
 	table struct {
-		// topic+topicN+(blockN+TxHash+logIndex) -> topic_count
-		Topic kvdb.KeyValueStore `table:"t"`
-		// (blockN+TxHash+logIndex) + topicN -> topic
-		Other kvdb.KeyValueStore `table:"o"`
-		// (blockN+TxHash+logIndex) -> address, blockHash, data
+		// topic+topicN+(blockN+txIndex+logIndex) -> topic_count
+		Topic kvdb.KeyValueStore `table:"T"`
+		// address+(blockN+txIndex+logIndex) -> topic_count
+		Address kvdb.KeyValueStore `table:"A"`
+		// (blockN+txIndex+logIndex) + topicN -> topic
+		Other kvdb.KeyValueStore `table:"O"`
+		// (blockN+txIndex+logIndex) -> TxHash, address, blockHash, data
+		Logrec kvdb.KeyValueStore `table:"R"`
+	}
+	// The tables of the former (blockN+TxHash+logIndex) layout, the records are moved by MigrateFormerLayout.
+	former struct {
+		Topic  kvdb.KeyValueStore `table:"t"`
+		Other  kvdb.KeyValueStore `table:"o"`
 		Logrec kvdb.KeyValueStore `table:"r"`
 	}
 
-	fetchMethod func(topics [][]common.Hash) ([]*types.Log, error)
+	fetchMethod func(q *query) ([]*types.Log, error)
 }
 
 // New TopicsDb instance.
@@ -38,13 +49,31 @@ func New(db kvdb.KeyValueStore) *Index {
 	tt.fetchMethod = tt.fetchAsync
 
 	table.MigrateTables(&tt.table, tt.db)
+	table.MigrateTables(&tt.former, tt.db)
 
 	return tt
 }
 
 // Find log records by conditions.
 func (tt *Index) Find(topics [][]common.Hash) ([]*types.Log, error) {
-	return tt.fetchMethod(topics)
+	return tt.FindInRange(0, math.MaxUint64, nil, topics, 0)
+}
+
+// FindInRange log records by conditions within [from, to] blocks range.
+// Empty addresses list means any address. At least one address or topic condition is required.
+// The result is ordered by (block, tx index, log index) and contains no more than limit records, 0 means no limit.
+// The search stops as soon as the limit is reached.
+func (tt *Index) FindInRange(from, to uint64, addresses []common.Address, topics [][]common.Hash, limit int) ([]*types.Log, error) {
+	if from > to {
+		return nil, nil
+	}
+	return tt.fetchMethod(&query{
+		from:      from,
+		to:        to,
+		addresses: addresses,
+		topics:    topics,
+		limit:     limit,
+	})
 }
 
 // MustPush calls Push() and panics if error.
@@ -63,7 +92,7 @@ func (tt *Index) Push(recs ...*types.Log) error {
 		}
 		count := posToBytes(uint8(len(rec.Topics)))
 
-		id := NewID(rec.BlockNumber, rec.TxHash, rec.Index)
+		id := NewID(rec.BlockNumber, rec.TxIndex, rec.Index)
 
 		for pos, topic := range rec.Topics {
 			key := topicKey(topic, uint8(pos), id)
@@ -79,12 +108,18 @@ func (tt *Index) Push(recs ...*types.Log) error {
 			}
 		}
 
-		buf := make([]byte, 0, common.AddressLength+common.HashLength+len(rec.Data))
+		err := tt.table.Address.Put(addressKey(rec.Address, id), count)
+		if err != nil {
+			return err
+		}
+
+		buf := make([]byte, 0, common.HashLength+common.AddressLength+common.HashLength+len(rec.Data))
+		buf = append(buf, rec.TxHash.Bytes()...)
 		buf = append(buf, rec.Address.Bytes()...)
 		buf = append(buf, rec.BlockHash.Bytes()...)
 		buf = append(buf, rec.Data...)
 
-		err := tt.table.Logrec.Put(id.Bytes(), buf)
+		err = tt.table.Logrec.Put(id.Bytes(), buf)
 		if err != nil {
 			return err
 		}
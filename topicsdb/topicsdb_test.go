@@ -6,9 +6,11 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/kvdb"
 	"github.com/Fantom-foundation/go-lachesis/kvdb/memorydb"
 	"github.com/Fantom-foundation/go-lachesis/logger"
 )
@@ -74,6 +76,197 @@ func TestTopicsDb(t *testing.T) {
 
 }
 
+func TestTopicsDbRange(t *testing.T) {
+	logger.SetTestMode(t)
+
+	topics, recs, topics4rec := genTestData()
+
+	db := New(memorydb.New())
+	for _, rec := range recs {
+		db.MustPush(rec)
+	}
+
+	find := func(t *testing.T) {
+		assertar := assert.New(t)
+
+		for i := 0; i < len(topics); i++ {
+			from, _ := topics4rec(i)
+			qq := [][]common.Hash{{topics[from]}}
+
+			for fromBlock := uint64(0); fromBlock < 3; fromBlock++ {
+				for toBlock := fromBlock; toBlock < 3; toBlock++ {
+					var expect, expectByAddress []*types.Log
+					for j, rec := range recs {
+						if f, _ := topics4rec(j); f != from {
+							continue
+						}
+						if rec.BlockNumber < fromBlock || rec.BlockNumber > toBlock {
+							continue
+						}
+						expect = append(expect, rec)
+						if rec.Address == recs[0].Address {
+							expectByAddress = append(expectByAddress, rec)
+						}
+					}
+
+					got, err := db.FindInRange(fromBlock, toBlock, nil, qq, 0)
+					if !assertar.NoError(err) {
+						return
+					}
+					if !assertar.Equalf(expect, got, "step %d [%d, %d]", i, fromBlock, toBlock) {
+						return
+					}
+
+					got, err = db.FindInRange(fromBlock, toBlock, []common.Address{recs[0].Address}, qq, 0)
+					if !assertar.NoError(err) {
+						return
+					}
+					if !assertar.Equalf(expectByAddress, got, "step %d [%d, %d] by address", i, fromBlock, toBlock) {
+						return
+					}
+
+					got, err = db.FindInRange(fromBlock, toBlock, []common.Address{recs[0].Address, recs[1].Address}, qq, 0)
+					if !assertar.NoError(err) {
+						return
+					}
+					if !assertar.Equalf(expect, got, "step %d [%d, %d] by addresses", i, fromBlock, toBlock) {
+						return
+					}
+
+					got, err = db.FindInRange(fromBlock, toBlock, []common.Address{{0xff}}, qq, 0)
+					if !assertar.NoError(err) {
+						return
+					}
+					if !assertar.Emptyf(got, "step %d [%d, %d] by another address", i, fromBlock, toBlock) {
+						return
+					}
+
+					if len(expect) == 0 {
+						continue
+					}
+					got, err = db.FindInRange(fromBlock, toBlock, nil, qq, 2)
+					if !assertar.NoError(err) {
+						return
+					}
+					if len(expect) > 2 {
+						expect = expect[:2]
+					}
+					if !assertar.Equalf(expect, got, "step %d [%d, %d] limit", i, fromBlock, toBlock) {
+						return
+					}
+				}
+			}
+		}
+
+		// by address only
+		var expect []*types.Log
+		for _, rec := range recs {
+			if rec.Address == recs[1].Address && rec.BlockNumber >= 1 {
+				expect = append(expect, rec)
+			}
+		}
+		got, err := db.FindInRange(1, 2, []common.Address{recs[1].Address}, [][]common.Hash{{}}, 0)
+		if !assertar.NoError(err) {
+			return
+		}
+		assertar.Equal(expect, got, "by address only")
+
+		// no conditions
+		got, err = db.FindInRange(0, 2, nil, [][]common.Hash{{}}, 0)
+		if !assertar.NoError(err) {
+			return
+		}
+		assertar.Empty(got, "no conditions")
+	}
+
+	t.Run("Find sync", func(t *testing.T) {
+		db.fetchMethod = db.fetchSync
+		find(t)
+	})
+
+	t.Run("Find async", func(t *testing.T) {
+		db.fetchMethod = db.fetchAsync
+		find(t)
+	})
+}
+
+// countingStore counts the records read by iterators.
+type countingStore struct {
+	kvdb.KeyValueStore
+	read int
+}
+
+type countingIterator struct {
+	ethdb.Iterator
+	store *countingStore
+}
+
+func (s *countingStore) NewIteratorWithStart(start []byte) ethdb.Iterator {
+	return &countingIterator{s.KeyValueStore.NewIteratorWithStart(start), s}
+}
+
+func (it *countingIterator) Next() bool {
+	it.store.read++
+	return it.Iterator.Next()
+}
+
+func TestTopicsDbLimit(t *testing.T) {
+	logger.SetTestMode(t)
+
+	const count = 100
+	topic := hash.FakeHash(1)
+	db := New(memorydb.New())
+	for i := 0; i < count; i++ {
+		db.MustPush(&types.Log{
+			BlockNumber: uint64(i),
+			TxHash:      hash.FakeHash(int64(i)),
+			Address:     common.Address{byte(i % 2)},
+			Topics:      []common.Hash{topic},
+		})
+	}
+	topicTable := &countingStore{KeyValueStore: db.table.Topic}
+	addressTable := &countingStore{KeyValueStore: db.table.Address}
+	db.table.Topic = topicTable
+	db.table.Address = addressTable
+
+	find := func(t *testing.T) {
+		assertar := assert.New(t)
+
+		topicTable.read, addressTable.read = 0, 0
+		got, err := db.FindInRange(10, count, nil, [][]common.Hash{{topic}}, 3)
+		if !assertar.NoError(err) {
+			return
+		}
+		if assertar.Len(got, 3) {
+			assertar.Equal(uint64(10), got[0].BlockNumber)
+			assertar.Equal(uint64(12), got[2].BlockNumber)
+		}
+		assertar.Equal(3, topicTable.read, "records after the limit are read")
+
+		topicTable.read, addressTable.read = 0, 0
+		got, err = db.FindInRange(10, count, []common.Address{{1}}, [][]common.Hash{{topic}}, 3)
+		if !assertar.NoError(err) {
+			return
+		}
+		if assertar.Len(got, 3) {
+			assertar.Equal(uint64(11), got[0].BlockNumber)
+			assertar.Equal(uint64(15), got[2].BlockNumber)
+		}
+		assertar.Equal(3, addressTable.read, "records after the limit are read")
+		assertar.Equal(6, topicTable.read, "records after the limit are read")
+	}
+
+	t.Run("Find sync", func(t *testing.T) {
+		db.fetchMethod = db.fetchSync
+		find(t)
+	})
+
+	t.Run("Find async", func(t *testing.T) {
+		db.fetchMethod = db.fetchAsync
+		find(t)
+	})
+}
+
 func genTestData() (
 	topics []common.Hash,
 	recs []*types.Log,
@@ -98,12 +291,14 @@ func genTestData() (
 	recs = make([]*types.Log, count)
 	for i := range recs {
 		from, to := topics4rec(i)
+		// the tx hashes are random, so they aren't ordered as the tx indexes
 		r := &types.Log{
 			BlockNumber: uint64(i / period),
 			BlockHash:   hash.FakeHash(int64(i / period)),
 			TxHash:      hash.FakeHash(int64(i % period)),
+			TxIndex:     uint(i % period),
 			Index:       uint(i % period),
-			Address:     common.Address{0x1, 0x2, 0xff, 0x0},
+			Address:     common.Address{0x1, 0x2, 0xff, byte(i % 2)},
 			Topics:      topics[from:to],
 			Data:        make([]byte, i),
 		}
@@ -113,3 +308,80 @@ func genTestData() (
 
 	return
 }
+
+// pushFormer writes the log record in the former (blockN+TxHash+logIndex) layout.
+func pushFormer(tt *Index, rec *types.Log) {
+	id := make([]byte, 0, formerIDSize)
+	id = append(id, uintToBytes(rec.BlockNumber)...)
+	id = append(id, rec.TxHash.Bytes()...)
+	id = append(id, uintToBytes(uint64(rec.Index))...)
+
+	count := posToBytes(uint8(len(rec.Topics)))
+	for pos, topic := range rec.Topics {
+		key := append(append(topic.Bytes(), posToBytes(uint8(pos))...), id...)
+		_ = tt.former.Topic.Put(key, count)
+		key = append(common.CopyBytes(id), posToBytes(uint8(pos))...)
+		_ = tt.former.Other.Put(key, topic.Bytes())
+	}
+
+	buf := make([]byte, 0, common.AddressLength+common.HashLength+len(rec.Data))
+	buf = append(buf, rec.Address.Bytes()...)
+	buf = append(buf, rec.BlockHash.Bytes()...)
+	buf = append(buf, rec.Data...)
+	_ = tt.former.Logrec.Put(id, buf)
+}
+
+func TestMigrateFormerLayout(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	topics, recs, topics4rec := genTestData()
+
+	db := New(memorydb.New())
+	txIndexes := make(map[common.Hash]uint)
+	for _, rec := range recs {
+		pushFormer(db, rec)
+		txIndexes[rec.TxHash] = rec.TxIndex
+	}
+	got, err := db.FindInRange(0, 3, nil, [][]common.Hash{{topics[0]}}, 0)
+	assertar.NoError(err)
+	assertar.Empty(got, "the former records aren't read")
+
+	txIndex := func(block uint64, tx common.Hash) uint {
+		return txIndexes[tx]
+	}
+	var total int
+	for done := false; !done; {
+		var moved int
+		moved, done, err = db.MigrateFormerLayout(txIndex, 7)
+		if !assertar.NoError(err) {
+			return
+		}
+		assertar.True(moved <= 7)
+		total += moved
+	}
+	assertar.Equal(len(recs), total)
+
+	for i := range topics {
+		var expect []*types.Log
+		for j, rec := range recs {
+			if from, _ := topics4rec(j); from == i {
+				expect = append(expect, rec)
+			}
+		}
+		got, err := db.FindInRange(0, 3, nil, [][]common.Hash{{topics[i]}}, 0)
+		if !assertar.NoError(err) {
+			return
+		}
+		assertar.Equal(expect, got, "topic %d", i)
+	}
+
+	// the former tables are empty
+	assertar.True(isEmpty(db.former.Topic))
+	assertar.True(isEmpty(db.former.Other))
+	assertar.True(isEmpty(db.former.Logrec))
+	moved, done, err := db.MigrateFormerLayout(nil, 7)
+	assertar.NoError(err)
+	assertar.True(done)
+	assertar.Zero(moved)
+}
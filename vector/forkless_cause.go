@@ -3,6 +3,7 @@ package vector
 import (
 	"github.com/Fantom-foundation/go-lachesis/hash"
 	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/inter/pos"
 )
 
 type kv struct {
@@ -59,7 +60,7 @@ func (vi *Index) forklessCause(aID, bID hash.Event) bool {
 		return false
 	}
 
-	yes := vi.validators.NewCounter()
+	yes := vi.newQuorumCounter(a)
 	// calculate forkless causing using the indexes
 	for branchIDint, creatorIdx := range vi.bi.BranchIDCreatorIdxs {
 		branchID := idx.Validator(branchIDint)
@@ -101,3 +102,52 @@ func (vi *Index) NoCheaters(selfParent *hash.Event, options hash.Events) hash.Ev
 	}
 	return filtered
 }
+
+// ObservedCheaters returns the validators which are observed by the event as cheaters, ordered by index.
+func (vi *Index) ObservedCheaters(id hash.Event) []idx.Validator {
+	vi.initBranchesInfo()
+
+	highest := vi.GetHighestBeforeSeq(id)
+	if highest == nil {
+		vi.Log.Crit("Event not found", "event", id.String())
+	}
+	return vi.observedCheaters(highest)
+}
+
+func (vi *Index) observedCheaters(highest HighestBeforeSeq) []idx.Validator {
+	if !vi.atLeastOneFork() {
+		return nil
+	}
+	// no need to merge, because every branch is marked by IsForkDetected if fork is observed
+	var cheaters []idx.Validator
+	for i := idx.Validator(0); i < idx.Validator(vi.validators.Len()); i++ {
+		if highest.Get(i).IsForkDetected() {
+			cheaters = append(cheaters, i)
+		}
+	}
+	return cheaters
+}
+
+// NewQuorumCounter returns a stake counter from the point of view of the event.
+// If cheaters eviction is enabled, the cheaters observed by the event aren't counted,
+// and the quorum is calculated without their stake.
+// The result depends only on the event's subgraph, so it's the same for every node.
+func (vi *Index) NewQuorumCounter(id hash.Event) *pos.StakeCounter {
+	if !vi.cfg.EvictCheaters {
+		return vi.validators.NewCounter()
+	}
+	vi.initBranchesInfo()
+
+	highest := vi.GetHighestBeforeSeq(id)
+	if highest == nil {
+		vi.Log.Crit("Event not found", "event", id.String())
+	}
+	return vi.newQuorumCounter(highest)
+}
+
+func (vi *Index) newQuorumCounter(highest HighestBeforeSeq) *pos.StakeCounter {
+	if !vi.cfg.EvictCheaters {
+		return vi.validators.NewCounter()
+	}
+	return vi.validators.NewCounterExcluding(vi.observedCheaters(highest))
+}
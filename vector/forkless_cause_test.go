@@ -798,3 +798,59 @@ func codegen4ForklessCausedStability() {
 	fmt.Printf("}\n")
 }
 */
+
+func TestForklessCauseEvictCheaters(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	nodes := inter.GenNodes(5)
+	validators := pos.EqualStakeValidators(nodes, 1)
+
+	events := make(map[hash.Event]*inter.EventHeaderData)
+	newEvent := func(creator idx.StakerID, seq idx.Event, lamport idx.Lamport, claimedTime inter.Timestamp, parents ...*inter.EventHeaderData) *inter.EventHeaderData {
+		e := &inter.EventHeaderData{
+			Epoch:       1,
+			Creator:     creator,
+			Seq:         seq,
+			Lamport:     lamport,
+			ClaimedTime: claimedTime,
+			Parents:     hash.Events{},
+			Extra:       []byte{},
+		}
+		for _, p := range parents {
+			e.Parents.Add(p.Hash())
+		}
+		e.RecacheHash()
+		events[e.Hash()] = e
+		return e
+	}
+
+	// nodes[0] is a cheater, nodes[4] is offline
+	a1 := newEvent(nodes[0], 1, 1, 1)
+	a1fork := newEvent(nodes[0], 1, 1, 2)
+	c1 := newEvent(nodes[2], 1, 1, 1)
+	b1 := newEvent(nodes[1], 1, 2, 1, a1, a1fork, c1)
+	d1 := newEvent(nodes[3], 1, 2, 1, c1)
+	c2 := newEvent(nodes[2], 2, 3, 1, c1, b1, d1)
+
+	for _, evict := range []bool{false, true} {
+		cfg := DefaultIndexConfig()
+		cfg.EvictCheaters = evict
+		vi := NewIndex(cfg, validators, memorydb.New(), func(id hash.Event) *inter.EventHeaderData {
+			return events[id]
+		})
+		for _, e := range []*inter.EventHeaderData{a1, a1fork, c1, b1, d1, c2} {
+			vi.Add(e)
+			vi.Flush()
+		}
+
+		assertar.Empty(vi.ObservedCheaters(d1.Hash()))
+		assertar.Equal([]idx.Validator{validators.GetIdx(nodes[0])}, vi.ObservedCheaters(c2.Hash()))
+
+		// c1 is observed by 3 of 5 validators, which is a quorum only if the cheater is evicted
+		assertar.Equal(evict, vi.ForklessCause(c2.Hash(), c1.Hash()), "evict=%v", evict)
+		// d1 doesn't observe the fork, so the cheater isn't evicted from its quorum
+		assertar.False(vi.ForklessCause(d1.Hash(), c1.Hash()), "evict=%v", evict)
+		assertar.Equal(!evict, vi.NewQuorumCounter(c2.Hash()).Count(nodes[0]), "cheater is counted")
+	}
+}
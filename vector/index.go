@@ -28,9 +28,14 @@ type IndexCacheConfig struct {
 	LowestAfterSeq    int `json:"lowestAfterSeq"`
 }
 
-// IndexConfig - Index config (cache sizes)
+// IndexConfig - Index config (cache sizes, cheaters eviction)
 type IndexConfig struct {
 	Caches IndexCacheConfig `json:"cacheSizes"`
+
+	// EvictCheaters excludes the cheaters observed by an event from the quorum of the event,
+	// so the detected cheaters don't slow down the consensus until the epoch is sealed.
+	// It's a consensus rule, so it must be the same for all the network nodes.
+	EvictCheaters bool `json:"evictCheaters"`
 }
 
 // Index is a data to detect forkless-cause condition, calculate median timestamp, detect forks.